@@ -4,4 +4,6 @@ package shared
 const (
 	// HostRemovalAnnotation - Annotation key placed on VM or BMH resources to target them for scale-down
 	HostRemovalAnnotation = "osp-director.openstack.org/delete-host"
+	// BaremetalHostQuarantineReasonAnnotation - Annotation key placed on quarantined BMH resources with the reason
+	BaremetalHostQuarantineReasonAnnotation = "osp-director.openstack.org/quarantine-reason"
 )
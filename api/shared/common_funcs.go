@@ -16,6 +16,11 @@ limitations under the License.
 
 package shared
 
+import (
+	"strconv"
+	"strings"
+)
+
 // MergeStringMaps - merge two or more string->map maps
 func MergeStringMaps(baseMap map[string]string, extraMaps ...map[string]string) map[string]string {
 	InitMap(&baseMap)
@@ -35,3 +40,14 @@ func InitMap(m *map[string]string) {
 		*m = make(map[string]string)
 	}
 }
+
+// GetHostnameIndex - get the index of a "<RoleName>-<number>" hostname, -1 if it has none
+func GetHostnameIndex(hostname string) int {
+	pieces := strings.Split(hostname, "-")
+	index, err := strconv.Atoi(pieces[len(pieces)-1])
+	if err != nil {
+		return -1
+	}
+
+	return index
+}
@@ -16,6 +16,14 @@ limitations under the License.
 
 package shared
 
+import (
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/yaml"
+)
+
 // APIAction - typedef to enumerate API verbs
 type APIAction string
 
@@ -43,3 +51,148 @@ const (
 type OpenStackControlPlaneDefaults struct {
 	OpenStackRelease string
 }
+
+// ScaleDownPolicyType - the order in which hosts get selected for removal on scale-down
+type ScaleDownPolicyType string
+
+const (
+	// ScaleDownPolicyHighestIndex - remove the hosts with the highest hostname index first
+	ScaleDownPolicyHighestIndex ScaleDownPolicyType = "HighestIndex"
+	// ScaleDownPolicyNewest - remove the most recently provisioned hosts first
+	ScaleDownPolicyNewest ScaleDownPolicyType = "Newest"
+	// ScaleDownPolicyOldest - remove the least recently provisioned hosts first
+	ScaleDownPolicyOldest ScaleDownPolicyType = "Oldest"
+	// ScaleDownPolicyNotReadyFirst - remove hosts which are not ready first, then the ones with the highest hostname index
+	ScaleDownPolicyNotReadyFirst ScaleDownPolicyType = "NotReadyFirst"
+)
+
+// ScaleDownPolicy defines which hosts get removed on scale-down if not enough hosts
+// are annotated with the HostRemovalAnnotation
+type ScaleDownPolicy struct {
+	// Type the order in which hosts get selected for removal
+	// +kubebuilder:validation:Enum=HighestIndex;Newest;Oldest;NotReadyFirst
+	// +kubebuilder:default=HighestIndex
+	Type ScaleDownPolicyType `json:"type,omitempty"`
+	// Hostnames ordered list of hostnames to remove before any other host is selected by Type.
+	// Hostnames which are not part of the set are ignored.
+	Hostnames []string `json:"hostnames,omitempty"`
+}
+
+// CtlplaneNetworkConfig defines the links the ctlplane network uses on the hosts. If not set,
+// the ctlplane IP is configured on the CtlplaneInterface.
+type CtlplaneNetworkConfig struct {
+	// Bond Optional. Bond of physical interfaces the ctlplane network uses instead of the CtlplaneInterface
+	Bond *CtlplaneBond `json:"bond,omitempty"`
+	// VLAN Optional. VLAN ID of the ctlplane network, on top of the bond or the CtlplaneInterface
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=4094
+	VLAN int `json:"vlan,omitempty"`
+}
+
+// CtlplaneBond defines a bond used by the ctlplane network
+type CtlplaneBond struct {
+	// Name of the bond interface
+	// +kubebuilder:default=bond0
+	Name string `json:"name,omitempty"`
+	// Members interfaces of the bond
+	// +kubebuilder:validation:MinItems=1
+	Members []string `json:"members"`
+	// Mode of the bond
+	// +kubebuilder:validation:Enum={"balance-rr","active-backup","balance-xor","broadcast","802.3ad","balance-tlb","balance-alb"}
+	// +kubebuilder:default="802.3ad"
+	Mode string `json:"mode,omitempty"`
+	// Options additional bonding driver options, e.g. miimon: "100", xmit_hash_policy: layer3+4
+	Options map[string]string `json:"options,omitempty"`
+}
+
+// Validate - validate the ctlplane network config
+func (c *CtlplaneNetworkConfig) Validate() error {
+	if c == nil || c.Bond == nil {
+		return nil
+	}
+
+	members := map[string]bool{}
+	for _, member := range c.Bond.Members {
+		if members[member] {
+			return fmt.Errorf("\"ctlplaneNetworkConfig.bond.members\" contains %s more than once", member)
+		}
+		if member == c.Bond.Name {
+			return fmt.Errorf("\"ctlplaneNetworkConfig.bond.members\" can not contain the bond %s itself", member)
+		}
+		members[member] = true
+	}
+
+	return nil
+}
+
+// CloudInitPartContentType - the MIME type of a cloud-init user-data part
+type CloudInitPartContentType string
+
+const (
+	// CloudInitPartCloudConfig - cloud-config part, merged with the rendered cloud-config
+	CloudInitPartCloudConfig CloudInitPartContentType = "text/cloud-config"
+	// CloudInitPartShellScript - shell script part, run once on first boot
+	CloudInitPartShellScript CloudInitPartContentType = "text/x-shellscript"
+)
+
+// CloudInitPart defines a site specific cloud-init user-data part, e.g. NTP, packages, proxy
+// settings or CA trust, which gets added to the rendered cloud-config as multipart MIME.
+// The data of the part is either set inline in Content or referenced by SecretRef.
+type CloudInitPart struct {
+	// Name of the part, must be unique within the list of parts
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+	// ContentType of the part
+	// +kubebuilder:validation:Enum={"text/cloud-config","text/x-shellscript"}
+	// +kubebuilder:default="text/cloud-config"
+	ContentType CloudInitPartContentType `json:"contentType,omitempty"`
+	// Content Optional. Inline data of the part
+	Content string `json:"content,omitempty"`
+	// SecretRef Optional. Key of a secret in the namespace of the CR holding the data of the part
+	SecretRef *corev1.SecretKeySelector `json:"secretRef,omitempty"`
+}
+
+// ValidateCloudInitParts - validate the names and sources of the cloud-init parts and the inline content
+func ValidateCloudInitParts(parts []CloudInitPart) error {
+	names := map[string]bool{}
+	for _, part := range parts {
+		if names[part.Name] {
+			return fmt.Errorf("\"cloudInitParts\" contains %s more than once", part.Name)
+		}
+		names[part.Name] = true
+
+		if (part.Content == "") == (part.SecretRef == nil) {
+			return fmt.Errorf("cloudInitPart %s requires exactly one of content or secretRef", part.Name)
+		}
+
+		if part.Content != "" {
+			if err := ValidateCloudInitPartData(part.ContentType, part.Content); err != nil {
+				return fmt.Errorf("cloudInitPart %s: %w", part.Name, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// ValidateCloudInitPartData - validate the data of a cloud-init part. A cloud-config part must start
+// with the #cloud-config header and be a YAML dictionary, a shell script part must start with #!
+func ValidateCloudInitPartData(contentType CloudInitPartContentType, data string) error {
+	switch contentType {
+	case CloudInitPartShellScript:
+		if !strings.HasPrefix(data, "#!") {
+			return fmt.Errorf("%s data must start with #!", contentType)
+		}
+	default:
+		if !strings.HasPrefix(data, "#cloud-config") {
+			return fmt.Errorf("%s data must start with #cloud-config", CloudInitPartCloudConfig)
+		}
+
+		cloudConfig := map[string]interface{}{}
+		if err := yaml.Unmarshal([]byte(data), &cloudConfig); err != nil {
+			return fmt.Errorf("%s data is not valid YAML: %w", CloudInitPartCloudConfig, err)
+		}
+	}
+
+	return nil
+}
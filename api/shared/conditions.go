@@ -84,6 +84,8 @@ const (
 	CommonCondReasonOSNetAvailable ConditionReason = "OSNetAvailable"
 	// CommonCondReasonControllerReferenceError - error set controller reference on object
 	CommonCondReasonControllerReferenceError ConditionReason = "ControllerReferenceError"
+	// CommonCondReasonCloudInitPartError - cloud-init part missing or invalid
+	CommonCondReasonCloudInitPartError ConditionReason = "CloudInitPartError"
 	// CommonCondReasonOwnerRefLabeledObjectsDeleteError - error deleting object using OwnerRef label
 	CommonCondReasonOwnerRefLabeledObjectsDeleteError ConditionReason = "OwnerRefLabeledObjectsDeleteError"
 	// CommonCondReasonRemoveFinalizerError - error removing finalizer from object
@@ -253,6 +255,19 @@ const (
 	BaremetalSetCondReasonVirtualMachineProvisioned ConditionReason = "BaremetalHostProvisioned"
 	// BaremetalSetCondReasonVirtualMachineCountZero - no bmh requested
 	BaremetalSetCondReasonVirtualMachineCountZero ConditionReason = "BaremetalHostCountZero"
+	// BaremetalSetCondReasonProvisioningFailed - bmh is in an error state while provisioning
+	BaremetalSetCondReasonProvisioningFailed ConditionReason = "BaremetalHostProvisioningFailed"
+	// BaremetalSetCondReasonProvisioningTimeout - bmh did not get provisioned within the provisioning timeout
+	BaremetalSetCondReasonProvisioningTimeout ConditionReason = "BaremetalHostProvisioningTimeout"
+
+	//
+	// per host conditions
+	//
+
+	// BaremetalHostCondTypeReplaced - the bmh of the host failed to provision and got replaced
+	BaremetalHostCondTypeReplaced ConditionType = "Replaced"
+	// BaremetalHostCondTypeFailed - the bmh of the host failed to provision and replacement attempts are exhausted
+	BaremetalHostCondTypeFailed ConditionType = "Failed"
 )
 
 // ControlPlane
@@ -325,6 +340,10 @@ const (
 	DeployCondReasonJobFailed ConditionReason = "JobFailed"
 	// DeployCondReasonConfigCreate - error creating/update CM
 	DeployCondReasonConfigCreate ConditionReason = "ConfigCreate"
+	// DeployCondReasonSnapshotCreated - VirtualMachineSnapshots created before the deploy
+	DeployCondReasonSnapshotCreated ConditionReason = "SnapshotCreated"
+	// DeployCondReasonSnapshotError - error creating the VirtualMachineSnapshots before the deploy
+	DeployCondReasonSnapshotError ConditionReason = "SnapshotError"
 )
 
 // EphemeralHeat
@@ -481,6 +500,8 @@ const (
 	NetConfigCondReasonIPReservationError ConditionReason = "IPReservationError"
 	// NetConfigCondReasonIPReservation - ip reservation created
 	NetConfigCondReasonIPReservation ConditionReason = "IPReservationCreated"
+	// NetConfigCondReasonDNSRecordsError - Failed to create or update the DNS records
+	NetConfigCondReasonDNSRecordsError ConditionReason = "DNSRecordsError"
 )
 
 // ProvisionServer
@@ -542,6 +563,8 @@ const (
 	VMSetCondTypeDeprovisioning ConditionType = "Deprovisioning"
 	// VMSetCondTypeError - general catch-all for actual errors
 	VMSetCondTypeError ConditionType = "Error"
+	// VMSetCondTypeFencingVerified - result of the fencing health check, kept in the fencingStatus
+	VMSetCondTypeFencingVerified ConditionType = "FencingVerified"
 
 	//
 	// condition reasones
@@ -593,6 +616,8 @@ const (
 	VMSetCondReasonVirtualMachineProvisioned ConditionReason = "VirtualMachineProvisioned"
 	// VMSetCondReasonVirtualMachineCountZero - no virtual machines requested
 	VMSetCondReasonVirtualMachineCountZero ConditionReason = "VirtualMachineCountZero"
+	// VMSetCondReasonVirtualMachineUpdating - virtual machines get restarted or live migrated to pick up changes of cores and memory
+	VMSetCondReasonVirtualMachineUpdating ConditionReason = "VirtualMachineUpdating"
 
 	// VMSetCondReasonPersitentVolumeClaimNotFound - Persitent Volume Claim Not Found
 	VMSetCondReasonPersitentVolumeClaimNotFound ConditionReason = "PersitentVolumeClaimNotFound"
@@ -602,4 +627,61 @@ const (
 	VMSetCondReasonPersitentVolumeClaimCreating ConditionReason = "PersitentVolumeClaimCreating"
 	// VMSetCondReasonBaseImageNotReady - VM base image not ready
 	VMSetCondReasonBaseImageNotReady ConditionReason = "BaseImageNotReady"
+	// VMSetCondReasonBaseImageError - error creating the base image DataVolume
+	VMSetCondReasonBaseImageError ConditionReason = "BaseImageError"
+	// VMSetCondReasonBaseImageChecksumError - checksum verification of the base image failed
+	VMSetCondReasonBaseImageChecksumError ConditionReason = "BaseImageChecksumError"
+	// VMSetCondReasonFencingVerified - the fencing credentials can get the status of all VMs
+	VMSetCondReasonFencingVerified ConditionReason = "FencingVerified"
+	// VMSetCondReasonFencingCheckFailed - the fencing credentials can not get the status of one or more VMs
+	VMSetCondReasonFencingCheckFailed ConditionReason = "FencingCheckFailed"
+	// VMSetCondReasonFencingCheckPending - the fencing health check did not run yet
+	VMSetCondReasonFencingCheckPending ConditionReason = "FencingCheckPending"
+	// VMSetCondReasonFencingCheckError - error running the fencing health check job
+	VMSetCondReasonFencingCheckError ConditionReason = "FencingCheckError"
+)
+
+// VMSetRestoreRequest
+const (
+	//
+	// condition types
+	//
+
+	// VMSetRestoreCondTypeWaiting - the restore is waiting
+	VMSetRestoreCondTypeWaiting ConditionType = "Waiting"
+	// VMSetRestoreCondTypeStopping - the VMs of the VMSet get stopped
+	VMSetRestoreCondTypeStopping ConditionType = "Stopping"
+	// VMSetRestoreCondTypeRestoring - the VMs of the VMSet get restored
+	VMSetRestoreCondTypeRestoring ConditionType = "Restoring"
+	// VMSetRestoreCondTypeStarting - the restored VMs get started
+	VMSetRestoreCondTypeStarting ConditionType = "Starting"
+	// VMSetRestoreCondTypeFinished - the restore has finished
+	VMSetRestoreCondTypeFinished ConditionType = "Finished"
+	// VMSetRestoreCondTypeError - the restore hit a generic error
+	VMSetRestoreCondTypeError ConditionType = "Error"
+
+	//
+	// condition reasons
+	//
+
+	// VMSetRestoreCondReasonVMSetNotFound - the VMSet to restore does not exist
+	VMSetRestoreCondReasonVMSetNotFound ConditionReason = "VMSetNotFound"
+	// VMSetRestoreCondReasonRunStrategyNotSupported - the run strategy of the VMSet does not allow to stop the VMs
+	VMSetRestoreCondReasonRunStrategyNotSupported ConditionReason = "RunStrategyNotSupported"
+	// VMSetRestoreCondReasonSnapshotNotFound - a VM of the VMSet has no snapshot in the snapshot set
+	VMSetRestoreCondReasonSnapshotNotFound ConditionReason = "SnapshotNotFound"
+	// VMSetRestoreCondReasonSnapshotNotReady - a snapshot of the snapshot set is not ready to use
+	VMSetRestoreCondReasonSnapshotNotReady ConditionReason = "SnapshotNotReady"
+	// VMSetRestoreCondReasonVirtualMachineStopping - VMs of the VMSet are stopping
+	VMSetRestoreCondReasonVirtualMachineStopping ConditionReason = "VirtualMachineStopping"
+	// VMSetRestoreCondReasonVirtualMachineRestoring - VMs of the VMSet are restoring
+	VMSetRestoreCondReasonVirtualMachineRestoring ConditionReason = "VirtualMachineRestoring"
+	// VMSetRestoreCondReasonVirtualMachineStarting - restored VMs are starting
+	VMSetRestoreCondReasonVirtualMachineStarting ConditionReason = "VirtualMachineStarting"
+	// VMSetRestoreCondReasonRestoreError - error restoring a VM
+	VMSetRestoreCondReasonRestoreError ConditionReason = "RestoreError"
+	// VMSetRestoreCondReasonKubevirtError - error stopping or starting a VM
+	VMSetRestoreCondReasonKubevirtError ConditionReason = "KubevirtError"
+	// VMSetRestoreCondReasonRestored - all VMs of the VMSet restored
+	VMSetRestoreCondReasonRestored ConditionReason = "Restored"
 )
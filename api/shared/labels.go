@@ -42,4 +42,7 @@ const (
 	OwnerNameLabelSelector = "osp-director.openstack.org/name"
 	// RequireBMHDeprovision - placed on a BMH to indicate that it must fully deprovision before we are done with it
 	RequireBMHDeprovision = "osp-director.openstack.org/require-deprovision"
+	// BaremetalHostQuarantineLabel - placed on a BMH which failed to provision and got replaced, it does not
+	// get selected by an OpenStackBaremetalSet until the label gets removed
+	BaremetalHostQuarantineLabel = "osp-director.openstack.org/quarantined"
 )
@@ -16,7 +16,12 @@ limitations under the License.
 
 package shared
 
-import "net"
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"strings"
+)
 
 // IsIPv4 checks if an IP is v4.
 func IsIPv4(ip net.IP) bool {
@@ -31,3 +36,35 @@ func IsIPv6(ip net.IP) bool {
 
 	return ip.To16() != nil
 }
+
+// ParseExcludeRange - get the first and last IP of an exclude range, which is either
+// a CIDR, e.g. 172.17.0.0/28, or a start-end pair, e.g. 172.17.0.20-172.17.0.30
+func ParseExcludeRange(excludeRange string) (net.IP, net.IP, error) {
+	if start, end, found := strings.Cut(excludeRange, "-"); found {
+		startIP := net.ParseIP(strings.TrimSpace(start))
+		endIP := net.ParseIP(strings.TrimSpace(end))
+		if startIP == nil || endIP == nil {
+			return nil, nil, fmt.Errorf("exclude range %s has an invalid format", excludeRange)
+		}
+		if IsIPv4(startIP) != IsIPv4(endIP) {
+			return nil, nil, fmt.Errorf("exclude range %s mixes IPv4 and IPv6", excludeRange)
+		}
+		if bytes.Compare(startIP.To16(), endIP.To16()) > 0 {
+			return nil, nil, fmt.Errorf("exclude range %s starts after its end", excludeRange)
+		}
+
+		return startIP, endIP, nil
+	}
+
+	_, ipnet, err := net.ParseCIDR(excludeRange)
+	if err != nil {
+		return nil, nil, fmt.Errorf("exclude range %s is neither a CIDR nor a start-end pair", excludeRange)
+	}
+
+	endIP := make(net.IP, len(ipnet.IP))
+	for i := range ipnet.IP {
+		endIP[i] = ipnet.IP[i] | ^ipnet.Mask[i]
+	}
+
+	return ipnet.IP, endIP, nil
+}
@@ -3,6 +3,10 @@ package v1beta1
 import (
 	"context"
 	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/go-logr/logr"
@@ -114,11 +118,13 @@ func VerifyBaremetalStatusHostRefs(
 	return nil
 }
 
-// VerifyBaremetalSetScaleUp -
-func VerifyBaremetalSetScaleUp(log logr.Logger, instance *OpenStackBaremetalSet, allBmhs *metal3v1.BareMetalHostList, existingBmhs *metal3v1.BareMetalHostList) ([]metal3v1.BareMetalHost, error) {
+// VerifyBaremetalSetScaleUp - get the BaremetalHosts to use for scaling up the set. BaremetalHosts selected
+// via HostAssignments come first, the returned map holds their hostname by BaremetalHost name.
+func VerifyBaremetalSetScaleUp(log logr.Logger, instance *OpenStackBaremetalSet, allBmhs *metal3v1.BareMetalHostList, existingBmhs *metal3v1.BareMetalHostList) ([]metal3v1.BareMetalHost, map[string]string, error) {
 	// How many new BaremetalHost allocations do we need (if any)?
 	newBmhsNeededCount := instance.Spec.Count - len(existingBmhs.Items)
 	availableBaremetalHosts := []metal3v1.BareMetalHost{}
+	pinnedHostnames := map[string]string{}
 
 	if newBmhsNeededCount > 0 {
 		// We have new replicas requested, so search for baremetalhosts that don't have consumerRef or Online set
@@ -132,6 +138,9 @@ func VerifyBaremetalSetScaleUp(log logr.Logger, instance *OpenStackBaremetalSet,
 
 		log.Info(fmt.Sprintf("Attempting to find %d BaremetalHost(s)%s for scale-up of OpenStackBaremetalSet %s", newBmhsNeededCount, labelStr, instance.Name))
 
+		// quarantine reason by BaremetalHost name
+		quarantinedBaremetalHosts := map[string]string{}
+
 		for _, baremetalHost := range allBmhs.Items {
 			mismatch := false
 
@@ -150,11 +159,29 @@ func VerifyBaremetalSetScaleUp(log logr.Logger, instance *OpenStackBaremetalSet,
 				mismatch = true
 			}
 
+			if missing := getMissingCtlplaneBondMembers(instance, &baremetalHost); len(missing) > 0 {
+				log.Info(fmt.Sprintf("BaremetalHost %s cannot be used because it lacks the ctlplane bond members %v", baremetalHost.Name, missing))
+				mismatch = true
+			}
+
+			if _, ok := baremetalHost.Labels[shared.BaremetalHostQuarantineLabel]; ok {
+				log.Info(fmt.Sprintf("BaremetalHost %s cannot be used because it is quarantined", baremetalHost.Name))
+				quarantinedBaremetalHosts[baremetalHost.Name] = baremetalHost.Annotations[shared.BaremetalHostQuarantineReasonAnnotation]
+				mismatch = true
+			}
+
 			if baremetalHost.Status.Provisioning.State != metal3v1.StateAvailable {
 				log.Info("BaremetalHost ProvisioningState is not 'Available'")
 				mismatch = true
 			}
 
+			if instance.Spec.TopologySpread != nil {
+				if _, ok := baremetalHost.Labels[instance.Spec.TopologySpread.TopologyKey]; !ok {
+					log.Info(fmt.Sprintf("BaremetalHost %s cannot be used because it lacks the topology label %s", baremetalHost.Name, instance.Spec.TopologySpread.TopologyKey))
+					mismatch = true
+				}
+			}
+
 			// If for any reason we can't use this BMH, do not add to the list of available BMHs
 			if mismatch {
 				continue
@@ -165,20 +192,356 @@ func VerifyBaremetalSetScaleUp(log logr.Logger, instance *OpenStackBaremetalSet,
 			availableBaremetalHosts = append(availableBaremetalHosts, baremetalHost)
 		}
 
+		//
+		// Hosts with a host assignment get their pinned BaremetalHost, BaremetalHosts pinned
+		// by name are not available for other hosts
+		//
+		pinnedBaremetalHosts, unpinnedBaremetalHosts, err := getHostAssignmentBmhs(
+			instance,
+			GetScaleUpHostnames(instance, newBmhsNeededCount),
+			availableBaremetalHosts,
+			quarantinedBaremetalHosts,
+		)
+		if err != nil {
+			return nil, nil, err
+		}
+
 		// If we can't satisfy the new requested replica count, explicitly state so
-		if newBmhsNeededCount > len(availableBaremetalHosts) {
-			return nil, fmt.Errorf("unable to find %d requested BaremetalHost count (%d in use, %d available)%s for OpenStackBaremetalSet %s",
+		if newBmhsNeededCount > len(pinnedBaremetalHosts)+len(unpinnedBaremetalHosts) {
+			return nil, nil, fmt.Errorf("unable to find %d requested BaremetalHost count (%d in use, %d available)%s for OpenStackBaremetalSet %s",
 				instance.Spec.Count,
 				len(existingBmhs.Items),
-				len(availableBaremetalHosts),
+				len(pinnedBaremetalHosts)+len(unpinnedBaremetalHosts),
 				labelStr,
 				instance.Name)
 		}
 
 		log.Info(fmt.Sprintf("Found sufficient quantity of BaremetalHosts (%v)%s for scale-up of OpenStackBaremetalSet %s", availableBaremetalHosts, labelStr, instance.Name))
+
+		availableBaremetalHosts = []metal3v1.BareMetalHost{}
+		hostnames := make([]string, 0, len(pinnedBaremetalHosts))
+		for hostname := range pinnedBaremetalHosts {
+			hostnames = append(hostnames, hostname)
+		}
+		sort.Strings(hostnames)
+		for _, hostname := range hostnames {
+			availableBaremetalHosts = append(availableBaremetalHosts, pinnedBaremetalHosts[hostname])
+			pinnedHostnames[pinnedBaremetalHosts[hostname].Name] = hostname
+		}
+
+		//
+		// If a topology spread is requested, only return the BaremetalHosts which
+		// keep the set balanced across the failure domains
+		//
+		if instance.Spec.TopologySpread != nil {
+			allocatedBmhs := &metal3v1.BareMetalHostList{
+				Items: append(append([]metal3v1.BareMetalHost{}, existingBmhs.Items...), availableBaremetalHosts...),
+			}
+
+			topologyBmhs, err := getTopologySpreadScaleUpBmhs(instance, unpinnedBaremetalHosts, allocatedBmhs, newBmhsNeededCount-len(pinnedBaremetalHosts))
+			if err != nil {
+				return nil, nil, err
+			}
+
+			return append(availableBaremetalHosts, topologyBmhs...), pinnedHostnames, nil
+		}
+
+		availableBaremetalHosts = append(availableBaremetalHosts, unpinnedBaremetalHosts...)
+	}
+
+	return availableBaremetalHosts, pinnedHostnames, nil
+}
+
+// GetUnassignedHostnames - get the hostnames of the set which have no BaremetalHost, ordered by index
+func GetUnassignedHostnames(instance *OpenStackBaremetalSet) []string {
+	hostnames := []string{}
+
+	for hostname, bmhStatus := range instance.Status.BaremetalHosts {
+		if bmhStatus.HostRef == shared.HostRefInitState {
+			hostnames = append(hostnames, hostname)
+		}
 	}
 
-	return availableBaremetalHosts, nil
+	sort.Slice(hostnames, func(i, j int) bool {
+		return shared.GetHostnameIndex(hostnames[i]) < shared.GetHostnameIndex(hostnames[j])
+	})
+
+	return hostnames
+}
+
+// GetScaleUpHostnames - get the hostnames the next count BaremetalHosts of the set get assigned to. These are the
+// unassigned hostnames of the set, followed by the ones the IPSet creates next (lowest unused index first).
+// Returns an empty list if no new BaremetalHosts are needed, e.g. on scale-down.
+func GetScaleUpHostnames(instance *OpenStackBaremetalSet, count int) []string {
+	if count <= 0 {
+		return []string{}
+	}
+
+	hostnames := GetUnassignedHostnames(instance)
+	usedIndices := map[int]bool{}
+
+	for hostname := range instance.Status.BaremetalHosts {
+		usedIndices[shared.GetHostnameIndex(hostname)] = true
+	}
+
+	for index := 0; len(hostnames) < count; index++ {
+		if !usedIndices[index] {
+			hostnames = append(hostnames, fmt.Sprintf("%s-%d", strings.ToLower(instance.Spec.RoleName), index))
+		}
+	}
+
+	if len(hostnames) > count {
+		hostnames = hostnames[:count]
+	}
+
+	return hostnames
+}
+
+// GetHostAssignment - get the host assignment of the hostname, either by hostname or by hostname index
+func (instance *OpenStackBaremetalSet) GetHostAssignment(hostname string) (HostAssignment, bool) {
+	if assignment, ok := instance.Spec.HostAssignments[hostname]; ok {
+		return assignment, true
+	}
+
+	index := shared.GetHostnameIndex(hostname)
+	if index < 0 {
+		return HostAssignment{}, false
+	}
+
+	assignment, ok := instance.Spec.HostAssignments[strconv.Itoa(index)]
+
+	return assignment, ok
+}
+
+// Matches - check if the BaremetalHost matches the host assignment
+func (assignment HostAssignment) Matches(bmh *metal3v1.BareMetalHost) bool {
+	if assignment.BmhName != "" {
+		return bmh.Name == assignment.BmhName
+	}
+
+	for key, value := range assignment.BmhLabelSelector {
+		if bmhValue, ok := bmh.Labels[key]; !ok || bmhValue != value {
+			return false
+		}
+	}
+
+	return true
+}
+
+// getHostAssignmentBmhs - select the BaremetalHosts for the hostnames which have a host assignment.
+// Returns the selected BaremetalHosts by hostname and the remaining available BaremetalHosts,
+// without the ones pinned by name to any host of the set. The quarantined map holds the quarantine
+// reason by BaremetalHost name, a host pinned to a quarantined BaremetalHost is reported as such.
+func getHostAssignmentBmhs(
+	instance *OpenStackBaremetalSet,
+	hostnames []string,
+	availableBmhs []metal3v1.BareMetalHost,
+	quarantined map[string]string,
+) (map[string]metal3v1.BareMetalHost, []metal3v1.BareMetalHost, error) {
+	pinnedBmhs := map[string]metal3v1.BareMetalHost{}
+	reserved := map[string]bool{}
+	selected := map[string]bool{}
+
+	for _, assignment := range instance.Spec.HostAssignments {
+		if assignment.BmhName != "" {
+			reserved[assignment.BmhName] = true
+		}
+	}
+
+	sortedBmhs := append([]metal3v1.BareMetalHost{}, availableBmhs...)
+	sort.Slice(sortedBmhs, func(i, j int) bool {
+		return sortedBmhs[i].Name < sortedBmhs[j].Name
+	})
+
+	for _, hostname := range hostnames {
+		assignment, ok := instance.GetHostAssignment(hostname)
+		if !ok {
+			continue
+		}
+
+		found := false
+		for _, bmh := range sortedBmhs {
+			if selected[bmh.Name] || !assignment.Matches(&bmh) {
+				continue
+			}
+			// BaremetalHosts pinned by name are only used for their host
+			if assignment.BmhName == "" && reserved[bmh.Name] {
+				continue
+			}
+
+			pinnedBmhs[hostname] = bmh
+			selected[bmh.Name] = true
+			found = true
+			break
+		}
+
+		if reason, ok := quarantined[assignment.BmhName]; !found && ok {
+			return nil, nil, fmt.Errorf("BaremetalHost %s pinned to %s of OpenStackBaremetalSet %s is quarantined: %s. "+
+				"Remove the %s label from the BaremetalHost or change the host assignment",
+				assignment.BmhName,
+				hostname,
+				instance.Name,
+				reason,
+				shared.BaremetalHostQuarantineLabel)
+		}
+		if !found {
+			return nil, nil, fmt.Errorf("unable to find an available BaremetalHost matching the host assignment %+v of %s for OpenStackBaremetalSet %s",
+				assignment,
+				hostname,
+				instance.Name)
+		}
+	}
+
+	unpinnedBmhs := []metal3v1.BareMetalHost{}
+	for _, bmh := range availableBmhs {
+		if !selected[bmh.Name] && !reserved[bmh.Name] {
+			unpinnedBmhs = append(unpinnedBmhs, bmh)
+		}
+	}
+
+	return pinnedBmhs, unpinnedBmhs, nil
+}
+
+// GetTopologyDomainCounts - get the number of BaremetalHosts per failure domain of the instance TopologySpread
+func GetTopologyDomainCounts(instance *OpenStackBaremetalSet, bmhs []metal3v1.BareMetalHost) map[string]int {
+	counts := map[string]int{}
+
+	if instance.Spec.TopologySpread == nil {
+		return counts
+	}
+
+	for _, bmh := range bmhs {
+		if domain, ok := bmh.Labels[instance.Spec.TopologySpread.TopologyKey]; ok {
+			counts[domain]++
+		}
+	}
+
+	return counts
+}
+
+// getTopologySpreadScaleUpBmhs - select count BaremetalHosts from the available ones, always
+// picking from the failure domain which currently has the fewest BaremetalHosts of the set
+func getTopologySpreadScaleUpBmhs(
+	instance *OpenStackBaremetalSet,
+	availableBmhs []metal3v1.BareMetalHost,
+	existingBmhs *metal3v1.BareMetalHostList,
+	count int,
+) ([]metal3v1.BareMetalHost, error) {
+	topologyKey := instance.Spec.TopologySpread.TopologyKey
+	maxSkew := instance.Spec.TopologySpread.MaxSkew
+	if maxSkew < 1 {
+		maxSkew = 1
+	}
+
+	domainCounts := GetTopologyDomainCounts(instance, existingBmhs.Items)
+	candidates := map[string][]metal3v1.BareMetalHost{}
+	for _, bmh := range availableBmhs {
+		domain := bmh.Labels[topologyKey]
+		candidates[domain] = append(candidates[domain], bmh)
+		if _, ok := domainCounts[domain]; !ok {
+			domainCounts[domain] = 0
+		}
+	}
+
+	domains := []string{}
+	for domain, bmhs := range candidates {
+		sort.Slice(bmhs, func(i, j int) bool {
+			return bmhs[i].Name < bmhs[j].Name
+		})
+		domains = append(domains, domain)
+	}
+	sort.Strings(domains)
+
+	selectedBmhs := []metal3v1.BareMetalHost{}
+	for len(selectedBmhs) < count {
+		domain := ""
+		for _, d := range domains {
+			if len(candidates[d]) > 0 && (domain == "" || domainCounts[d] < domainCounts[domain]) {
+				domain = d
+			}
+		}
+
+		if domain == "" || domainCounts[domain]+1-getMinDomainCount(domainCounts) > maxSkew {
+			return nil, fmt.Errorf("unable to spread %d requested BaremetalHost(s) across %s failure domains %v with a max skew of %d for OpenStackBaremetalSet %s",
+				count,
+				topologyKey,
+				domainCounts,
+				maxSkew,
+				instance.Name)
+		}
+
+		selectedBmhs = append(selectedBmhs, candidates[domain][0])
+		candidates[domain] = candidates[domain][1:]
+		domainCounts[domain]++
+	}
+
+	return selectedBmhs, nil
+}
+
+// GetTopologySpreadScaleDownBmhs - select count BaremetalHosts of the set for removal, always picking
+// from the failure domain which currently has the most BaremetalHosts of the set.
+// BaremetalHosts in the exclude list are already being removed and are not taken into account.
+func GetTopologySpreadScaleDownBmhs(
+	instance *OpenStackBaremetalSet,
+	existingBmhs *metal3v1.BareMetalHostList,
+	exclude []string,
+	count int,
+) []string {
+	topologyKey := instance.Spec.TopologySpread.TopologyKey
+
+	// within a failure domain, remove the host with the highest hostname first
+	hostnames := map[string]string{}
+	for hostname, bmhStatus := range instance.Status.BaremetalHosts {
+		hostnames[bmhStatus.HostRef] = hostname
+	}
+
+	candidates := map[string][]string{}
+	for _, bmh := range existingBmhs.Items {
+		if _, ok := bmh.Labels[topologyKey]; !ok || slices.Contains(exclude, bmh.Name) {
+			continue
+		}
+		domain := bmh.Labels[topologyKey]
+		candidates[domain] = append(candidates[domain], bmh.Name)
+	}
+
+	domains := []string{}
+	for domain, bmhs := range candidates {
+		sort.Slice(bmhs, func(i, j int) bool {
+			return hostnames[bmhs[i]] > hostnames[bmhs[j]]
+		})
+		domains = append(domains, domain)
+	}
+	sort.Strings(domains)
+
+	removalBmhs := []string{}
+	for len(removalBmhs) < count {
+		domain := ""
+		for _, d := range domains {
+			if len(candidates[d]) > 0 && (domain == "" || len(candidates[d]) > len(candidates[domain])) {
+				domain = d
+			}
+		}
+
+		if domain == "" {
+			break
+		}
+
+		removalBmhs = append(removalBmhs, candidates[domain][0])
+		candidates[domain] = candidates[domain][1:]
+	}
+
+	return removalBmhs
+}
+
+func getMinDomainCount(domainCounts map[string]int) int {
+	minCount := -1
+	for _, c := range domainCounts {
+		if minCount == -1 || c < minCount {
+			minCount = c
+		}
+	}
+
+	return minCount
 }
 
 // VerifyBaremetalSetScaleDown -
@@ -186,6 +549,12 @@ func VerifyBaremetalSetScaleDown(_ logr.Logger, instance *OpenStackBaremetalSet,
 	// How many new BaremetalHost de-allocations do we need (if any)?
 	bmhsToRemoveCount := len(existingBmhs.Items) - instance.Spec.Count
 
+	// With a topology spread the hosts to remove get selected from the most crowded failure domain,
+	// with a scale-down policy by the policy
+	if instance.Spec.TopologySpread != nil || instance.Spec.ScaleDownPolicy != nil {
+		return nil
+	}
+
 	if bmhsToRemoveCount > removalAnnotatedBmhCount {
 		return fmt.Errorf("unable to find sufficient amount of BaremetalHost replicas annotated for scale-down (%d found, %d requested)", removalAnnotatedBmhCount, bmhsToRemoveCount)
 	}
@@ -193,6 +562,96 @@ func VerifyBaremetalSetScaleDown(_ logr.Logger, instance *OpenStackBaremetalSet,
 	return nil
 }
 
+// GetRollingReprovisionBmhs - get the names of the outdated BaremetalHosts which can get reprovisioned with
+// imageURL now, without exceeding the MaxUnavailable of the RollingReprovision update strategy
+func GetRollingReprovisionBmhs(
+	instance *OpenStackBaremetalSet,
+	existingBmhs *metal3v1.BareMetalHostList,
+	imageURL string,
+) []string {
+	reprovisionBmhs := []string{}
+
+	if instance.Spec.UpdateStrategy == nil ||
+		instance.Spec.UpdateStrategy.Type != BaremetalSetUpdateStrategyRollingReprovision {
+		return reprovisionBmhs
+	}
+
+	maxUnavailable := 1
+	if instance.Spec.UpdateStrategy.RollingReprovision != nil &&
+		instance.Spec.UpdateStrategy.RollingReprovision.MaxUnavailable > 1 {
+		maxUnavailable = instance.Spec.UpdateStrategy.RollingReprovision.MaxUnavailable
+	}
+
+	unavailable := 0
+	outdatedBmhs := []string{}
+	for _, bmh := range existingBmhs.Items {
+		//
+		// a host is unavailable if it is not provisioned or its image changed,
+		// but metal3 did not yet start to reprovision it
+		//
+		if bmh.Status.Provisioning.State != metal3v1.StateProvisioned ||
+			(bmh.Spec.Image != nil && bmh.Spec.Image.URL != bmh.Status.Provisioning.Image.URL) {
+			unavailable++
+			continue
+		}
+
+		if bmh.Spec.Image == nil || bmh.Spec.Image.URL != imageURL {
+			outdatedBmhs = append(outdatedBmhs, bmh.Name)
+		}
+	}
+
+	sort.Strings(outdatedBmhs)
+
+	for _, bmh := range outdatedBmhs {
+		if unavailable >= maxUnavailable {
+			break
+		}
+
+		reprovisionBmhs = append(reprovisionBmhs, bmh)
+		unavailable++
+	}
+
+	return reprovisionBmhs
+}
+
+// VerifyBaremetalSetCtlplaneBondMembers - verify that the BaremetalHosts have NICs for all ctlplane bond members
+func VerifyBaremetalSetCtlplaneBondMembers(instance *OpenStackBaremetalSet, bmhs *metal3v1.BareMetalHostList) error {
+	for _, bmh := range bmhs.Items {
+		if missing := getMissingCtlplaneBondMembers(instance, &bmh); len(missing) > 0 {
+			return fmt.Errorf("BaremetalHost %s of OpenStackBaremetalSet %s lacks the ctlplane bond members %v",
+				bmh.Name,
+				instance.Name,
+				missing)
+		}
+	}
+
+	return nil
+}
+
+// getMissingCtlplaneBondMembers - get the ctlplane bond members which are not in the NIC inventory of the BaremetalHost
+func getMissingCtlplaneBondMembers(instance *OpenStackBaremetalSet, bmh *metal3v1.BareMetalHost) []string {
+	missing := []string{}
+
+	if instance.Spec.CtlplaneNetworkConfig == nil || instance.Spec.CtlplaneNetworkConfig.Bond == nil {
+		return missing
+	}
+
+	nics := []string{}
+	if bmh.Status.HardwareDetails != nil {
+		for _, nic := range bmh.Status.HardwareDetails.NIC {
+			nics = append(nics, nic.Name)
+		}
+	}
+
+	for _, member := range instance.Spec.CtlplaneNetworkConfig.Bond.Members {
+		if !slices.Contains(nics, member) {
+			missing = append(missing, member)
+		}
+	}
+
+	return missing
+}
+
 func verifyBaremetalSetHardwareMatch(
 	log logr.Logger,
 	instance *OpenStackBaremetalSet,
@@ -339,6 +798,68 @@ func verifyBaremetalSetHardwareMatch(
 		}
 	}
 
+	nicReqs := instance.Spec.HardwareReqs.NicReqs
+
+	if nicReqs != (NicReqs{}) {
+		var modelRegex *regexp.Regexp
+		if nicReqs.ModelReq != "" {
+			var err error
+			modelRegex, err = regexp.Compile(nicReqs.ModelReq)
+			if err != nil {
+				log.Info(fmt.Sprintf("%s %s NIC model request '%s' is not a valid regular expression: %v",
+					instance.Kind,
+					instance.Name,
+					nicReqs.ModelReq,
+					err,
+				))
+
+				return false
+			}
+		}
+
+		// Count the NICs which satisfy the speed, model and PXE requests
+		nicCount := 0
+		for _, nic := range bmh.Status.HardwareDetails.NIC {
+			// NIC speed can be exact-match or (default) greater
+			if nicReqs.SpeedReq.Gbps != 0 && nic.SpeedGbps != nicReqs.SpeedReq.Gbps &&
+				(nicReqs.SpeedReq.ExactMatch || nicReqs.SpeedReq.Gbps > nic.SpeedGbps) {
+				continue
+			}
+
+			if modelRegex != nil && !modelRegex.MatchString(nic.Model) {
+				continue
+			}
+
+			// We only care about the PXE flag if the user requested an exact match for it or if PXE is true
+			if (nicReqs.PXEReq.ExactMatch || nicReqs.PXEReq.PXE) && nic.PXE != nicReqs.PXEReq.PXE {
+				continue
+			}
+
+			nicCount++
+		}
+
+		// NIC count can be exact-match or (default) greater, at least one NIC is required if no count got requested
+		countReq := nicReqs.CountReq.Count
+		if countReq == 0 {
+			countReq = 1
+		}
+
+		if nicCount != countReq && (nicReqs.CountReq.ExactMatch || countReq > nicCount) {
+			log.Info(fmt.Sprintf("BaremetalHost %s NIC count %d matching speed '%d', model '%s' and PXE '%v' does not match %s %s request for '%d'",
+				bmh.Name,
+				nicCount,
+				nicReqs.SpeedReq.Gbps,
+				nicReqs.ModelReq,
+				nicReqs.PXEReq.PXE,
+				instance.Kind,
+				instance.Name,
+				countReq,
+			))
+
+			return false
+		}
+	}
+
 	log.Info(fmt.Sprintf("BaremetalHost %s satisfies %s %s hardware requirements",
 		bmh.Name,
 		instance.Kind,
@@ -0,0 +1,551 @@
+package v1beta1
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/go-logr/logr"
+	metal3v1 "github.com/metal3-io/baremetal-operator/apis/metal3.io/v1alpha1"
+	. "github.com/onsi/gomega" //revive:disable:dot-imports
+	"github.com/openstack-k8s-operators/osp-director-operator/api/shared"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func newTopologyBmh(name string, rack string) metal3v1.BareMetalHost {
+	return metal3v1.BareMetalHost{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: map[string]string{"rack": rack},
+		},
+	}
+}
+
+func TestGetTopologySpreadScaleUpBmhs(t *testing.T) {
+
+	tests := []struct {
+		name      string
+		maxSkew   int
+		available []metal3v1.BareMetalHost
+		existing  []metal3v1.BareMetalHost
+		count     int
+		want      []string
+		wantErr   bool
+	}{
+		{
+			name:    "spread new set across racks",
+			maxSkew: 1,
+			available: []metal3v1.BareMetalHost{
+				newTopologyBmh("bmh-a1", "a"),
+				newTopologyBmh("bmh-a2", "a"),
+				newTopologyBmh("bmh-b1", "b"),
+				newTopologyBmh("bmh-b2", "b"),
+				newTopologyBmh("bmh-c1", "c"),
+			},
+			existing: []metal3v1.BareMetalHost{},
+			count:    3,
+			want:     []string{"bmh-a1", "bmh-b1", "bmh-c1"},
+		},
+		{
+			name:    "fill least crowded rack first",
+			maxSkew: 1,
+			available: []metal3v1.BareMetalHost{
+				newTopologyBmh("bmh-a2", "a"),
+				newTopologyBmh("bmh-b2", "b"),
+				newTopologyBmh("bmh-c1", "c"),
+			},
+			existing: []metal3v1.BareMetalHost{
+				newTopologyBmh("bmh-a1", "a"),
+				newTopologyBmh("bmh-b1", "b"),
+			},
+			count: 2,
+			want:  []string{"bmh-c1", "bmh-a2"},
+		},
+		{
+			name:    "max skew violated",
+			maxSkew: 1,
+			available: []metal3v1.BareMetalHost{
+				newTopologyBmh("bmh-a1", "a"),
+				newTopologyBmh("bmh-a2", "a"),
+				newTopologyBmh("bmh-a3", "a"),
+				newTopologyBmh("bmh-b1", "b"),
+			},
+			existing: []metal3v1.BareMetalHost{},
+			count:    4,
+			wantErr:  true,
+		},
+		{
+			name:    "larger max skew",
+			maxSkew: 2,
+			available: []metal3v1.BareMetalHost{
+				newTopologyBmh("bmh-a1", "a"),
+				newTopologyBmh("bmh-a2", "a"),
+				newTopologyBmh("bmh-a3", "a"),
+				newTopologyBmh("bmh-b1", "b"),
+			},
+			existing: []metal3v1.BareMetalHost{},
+			count:    4,
+			want:     []string{"bmh-a1", "bmh-b1", "bmh-a2", "bmh-a3"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			instance := &OpenStackBaremetalSet{
+				Spec: OpenStackBaremetalSetSpec{
+					TopologySpread: &TopologySpread{
+						TopologyKey: "rack",
+						MaxSkew:     tt.maxSkew,
+					},
+				},
+			}
+
+			selected, err := getTopologySpreadScaleUpBmhs(
+				instance,
+				tt.available,
+				&metal3v1.BareMetalHostList{Items: tt.existing},
+				tt.count,
+			)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+
+			names := []string{}
+			for _, bmh := range selected {
+				names = append(names, bmh.Name)
+			}
+			g.Expect(names).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestGetTopologySpreadScaleDownBmhs(t *testing.T) {
+
+	tests := []struct {
+		name     string
+		existing []metal3v1.BareMetalHost
+		exclude  []string
+		count    int
+		want     []string
+	}{
+		{
+			name: "remove from most crowded rack",
+			existing: []metal3v1.BareMetalHost{
+				newTopologyBmh("bmh-a1", "a"),
+				newTopologyBmh("bmh-a2", "a"),
+				newTopologyBmh("bmh-a3", "a"),
+				newTopologyBmh("bmh-b1", "b"),
+			},
+			exclude: []string{},
+			count:   2,
+			want:    []string{"bmh-a3", "bmh-a2"},
+		},
+		{
+			name: "skip hosts already annotated for removal",
+			existing: []metal3v1.BareMetalHost{
+				newTopologyBmh("bmh-a1", "a"),
+				newTopologyBmh("bmh-a2", "a"),
+				newTopologyBmh("bmh-b1", "b"),
+				newTopologyBmh("bmh-b2", "b"),
+			},
+			exclude: []string{"bmh-a2"},
+			count:   1,
+			want:    []string{"bmh-b2"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			instance := &OpenStackBaremetalSet{
+				Spec: OpenStackBaremetalSetSpec{
+					TopologySpread: &TopologySpread{
+						TopologyKey: "rack",
+						MaxSkew:     1,
+					},
+				},
+				Status: OpenStackBaremetalSetStatus{
+					BaremetalHosts: map[string]HostStatus{},
+				},
+			}
+			for idx, bmh := range tt.existing {
+				hostname := fmt.Sprintf("compute-%d", idx)
+				instance.Status.BaremetalHosts[hostname] = HostStatus{
+					IPStatus: IPStatus{
+						Hostname: hostname,
+						HostRef:  bmh.Name,
+					},
+				}
+			}
+
+			removal := GetTopologySpreadScaleDownBmhs(
+				instance,
+				&metal3v1.BareMetalHostList{Items: tt.existing},
+				tt.exclude,
+				tt.count,
+			)
+			g.Expect(removal).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestVerifyBaremetalSetHardwareMatchNicReqs(t *testing.T) {
+
+	bmh := &metal3v1.BareMetalHost{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "bmh",
+		},
+		Status: metal3v1.BareMetalHostStatus{
+			HardwareDetails: &metal3v1.HardwareDetails{
+				NIC: []metal3v1.NIC{
+					{Name: "eno1", Model: "0x8086 0x1572", SpeedGbps: 1, PXE: true},
+					{Name: "ens1f0", Model: "0x15b3 0x1017", SpeedGbps: 25},
+					{Name: "ens1f1", Model: "0x15b3 0x1017", SpeedGbps: 25},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		nicReqs NicReqs
+		want    bool
+	}{
+		{
+			name:    "minimum nic count",
+			nicReqs: NicReqs{CountReq: NicCountReq{Count: 3}},
+			want:    true,
+		},
+		{
+			name:    "too many nics for exact count",
+			nicReqs: NicReqs{CountReq: NicCountReq{Count: 2, ExactMatch: true}},
+			want:    false,
+		},
+		{
+			name: "nic count with minimum speed",
+			nicReqs: NicReqs{
+				CountReq: NicCountReq{Count: 2},
+				SpeedReq: NicSpeedReq{Gbps: 10},
+			},
+			want: true,
+		},
+		{
+			name:    "no nic with requested speed",
+			nicReqs: NicReqs{SpeedReq: NicSpeedReq{Gbps: 100}},
+			want:    false,
+		},
+		{
+			name: "vendor regex",
+			nicReqs: NicReqs{
+				CountReq: NicCountReq{Count: 2, ExactMatch: true},
+				ModelReq: "^0x15b3 ",
+			},
+			want: true,
+		},
+		{
+			name: "pxe capable with speed",
+			nicReqs: NicReqs{
+				SpeedReq: NicSpeedReq{Gbps: 25},
+				PXEReq:   NicPXEReq{PXE: true},
+			},
+			want: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			instance := &OpenStackBaremetalSet{
+				Spec: OpenStackBaremetalSetSpec{
+					HardwareReqs: HardwareReqs{
+						NicReqs: tt.nicReqs,
+					},
+				},
+			}
+
+			g.Expect(verifyBaremetalSetHardwareMatch(logr.Discard(), instance, bmh)).To(Equal(tt.want))
+		})
+	}
+}
+
+func newProvisionedBmh(name string, specImage string, provisionedImage string, state metal3v1.ProvisioningState) metal3v1.BareMetalHost {
+	return metal3v1.BareMetalHost{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+		},
+		Spec: metal3v1.BareMetalHostSpec{
+			Image: &metal3v1.Image{URL: specImage},
+		},
+		Status: metal3v1.BareMetalHostStatus{
+			Provisioning: metal3v1.ProvisionStatus{
+				State: state,
+				Image: metal3v1.Image{URL: provisionedImage},
+			},
+		},
+	}
+}
+
+func TestGetRollingReprovisionBmhs(t *testing.T) {
+
+	tests := []struct {
+		name     string
+		strategy *BaremetalSetUpdateStrategy
+		existing []metal3v1.BareMetalHost
+		want     []string
+	}{
+		{
+			name:     "no update strategy",
+			strategy: nil,
+			existing: []metal3v1.BareMetalHost{
+				newProvisionedBmh("bmh-0", "old", "old", metal3v1.StateProvisioned),
+			},
+			want: []string{},
+		},
+		{
+			name:     "on delete",
+			strategy: &BaremetalSetUpdateStrategy{Type: BaremetalSetUpdateStrategyOnDelete},
+			existing: []metal3v1.BareMetalHost{
+				newProvisionedBmh("bmh-0", "old", "old", metal3v1.StateProvisioned),
+			},
+			want: []string{},
+		},
+		{
+			name:     "first batch",
+			strategy: &BaremetalSetUpdateStrategy{Type: BaremetalSetUpdateStrategyRollingReprovision},
+			existing: []metal3v1.BareMetalHost{
+				newProvisionedBmh("bmh-1", "old", "old", metal3v1.StateProvisioned),
+				newProvisionedBmh("bmh-0", "old", "old", metal3v1.StateProvisioned),
+			},
+			want: []string{"bmh-0"},
+		},
+		{
+			name: "larger max unavailable",
+			strategy: &BaremetalSetUpdateStrategy{
+				Type:               BaremetalSetUpdateStrategyRollingReprovision,
+				RollingReprovision: &RollingReprovisionUpdateStrategy{MaxUnavailable: 2},
+			},
+			existing: []metal3v1.BareMetalHost{
+				newProvisionedBmh("bmh-0", "new", "new", metal3v1.StateProvisioned),
+				newProvisionedBmh("bmh-1", "old", "old", metal3v1.StateProvisioned),
+				newProvisionedBmh("bmh-2", "old", "old", metal3v1.StateProvisioned),
+				newProvisionedBmh("bmh-3", "old", "old", metal3v1.StateProvisioned),
+			},
+			want: []string{"bmh-1", "bmh-2"},
+		},
+		{
+			name:     "wait for reprovision in progress",
+			strategy: &BaremetalSetUpdateStrategy{Type: BaremetalSetUpdateStrategyRollingReprovision},
+			existing: []metal3v1.BareMetalHost{
+				newProvisionedBmh("bmh-0", "new", "old", metal3v1.StateProvisioned),
+				newProvisionedBmh("bmh-1", "old", "old", metal3v1.StateProvisioned),
+			},
+			want: []string{},
+		},
+		{
+			name:     "wait for unavailable host",
+			strategy: &BaremetalSetUpdateStrategy{Type: BaremetalSetUpdateStrategyRollingReprovision},
+			existing: []metal3v1.BareMetalHost{
+				newProvisionedBmh("bmh-0", "new", "", metal3v1.StateProvisioning),
+				newProvisionedBmh("bmh-1", "old", "old", metal3v1.StateProvisioned),
+			},
+			want: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			instance := &OpenStackBaremetalSet{
+				Spec: OpenStackBaremetalSetSpec{
+					UpdateStrategy: tt.strategy,
+				},
+			}
+
+			reprovision := GetRollingReprovisionBmhs(
+				instance,
+				&metal3v1.BareMetalHostList{Items: tt.existing},
+				"new",
+			)
+			g.Expect(reprovision).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestGetScaleUpHostnames(t *testing.T) {
+
+	tests := []struct {
+		name     string
+		hostRefs map[string]string
+		count    int
+		want     []string
+	}{
+		{
+			name:     "new set",
+			hostRefs: map[string]string{},
+			count:    3,
+			want:     []string{"compute-0", "compute-1", "compute-2"},
+		},
+		{
+			name: "unassigned hostnames first",
+			hostRefs: map[string]string{
+				"compute-0":  "bmh-0",
+				"compute-10": shared.HostRefInitState,
+				"compute-2":  shared.HostRefInitState,
+			},
+			count: 3,
+			want:  []string{"compute-2", "compute-10", "compute-1"},
+		},
+		{
+			name: "fill gaps",
+			hostRefs: map[string]string{
+				"compute-0": "bmh-0",
+				"compute-2": "bmh-2",
+			},
+			count: 2,
+			want:  []string{"compute-1", "compute-3"},
+		},
+		{
+			name: "no new hosts needed",
+			hostRefs: map[string]string{
+				"compute-0": "bmh-0",
+				"compute-1": shared.HostRefInitState,
+			},
+			count: 0,
+			want:  []string{},
+		},
+		{
+			name: "scale-down",
+			hostRefs: map[string]string{
+				"compute-0": "bmh-0",
+				"compute-1": shared.HostRefInitState,
+			},
+			count: -1,
+			want:  []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			instance := &OpenStackBaremetalSet{
+				Spec: OpenStackBaremetalSetSpec{
+					RoleName: "Compute",
+				},
+				Status: OpenStackBaremetalSetStatus{
+					BaremetalHosts: map[string]HostStatus{},
+				},
+			}
+			for hostname, hostRef := range tt.hostRefs {
+				instance.Status.BaremetalHosts[hostname] = HostStatus{
+					IPStatus: IPStatus{Hostname: hostname, HostRef: hostRef},
+				}
+			}
+
+			g.Expect(GetScaleUpHostnames(instance, tt.count)).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestGetHostAssignmentBmhs(t *testing.T) {
+
+	tests := []struct {
+		name        string
+		assignments map[string]HostAssignment
+		hostnames   []string
+		want        map[string]string
+		wantUnused  []string
+		wantErr     string
+	}{
+		{
+			name:       "no assignments",
+			hostnames:  []string{"compute-0"},
+			want:       map[string]string{},
+			wantUnused: []string{"bmh-a1", "bmh-a2", "bmh-b1"},
+		},
+		{
+			name: "pin by hostname and index",
+			assignments: map[string]HostAssignment{
+				"compute-0": {BmhName: "bmh-b1"},
+				"1":         {BmhLabelSelector: map[string]string{"rack": "a"}},
+			},
+			hostnames:  []string{"compute-0", "compute-1"},
+			want:       map[string]string{"compute-0": "bmh-b1", "compute-1": "bmh-a1"},
+			wantUnused: []string{"bmh-a2"},
+		},
+		{
+			name: "reserve BaremetalHosts pinned to future hosts",
+			assignments: map[string]HostAssignment{
+				"compute-3": {BmhName: "bmh-a1"},
+				"compute-0": {BmhLabelSelector: map[string]string{"rack": "a"}},
+			},
+			hostnames:  []string{"compute-0"},
+			want:       map[string]string{"compute-0": "bmh-a2"},
+			wantUnused: []string{"bmh-b1"},
+		},
+		{
+			name: "pinned BaremetalHost not available",
+			assignments: map[string]HostAssignment{
+				"compute-0": {BmhName: "bmh-c1"},
+			},
+			hostnames: []string{"compute-0"},
+			wantErr:   "unable to find an available BaremetalHost",
+		},
+		{
+			name: "pinned BaremetalHost quarantined",
+			assignments: map[string]HostAssignment{
+				"compute-0": {BmhName: "bmh-q1"},
+			},
+			hostnames: []string{"compute-0"},
+			wantErr:   "BaremetalHost bmh-q1 pinned to compute-0 of OpenStackBaremetalSet compute is quarantined: provisioning failed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			instance := &OpenStackBaremetalSet{
+				Spec: OpenStackBaremetalSetSpec{
+					RoleName:        "Compute",
+					HostAssignments: tt.assignments,
+				},
+			}
+			instance.Name = "compute"
+
+			pinned, unpinned, err := getHostAssignmentBmhs(
+				instance,
+				tt.hostnames,
+				[]metal3v1.BareMetalHost{
+					newTopologyBmh("bmh-b1", "b"),
+					newTopologyBmh("bmh-a2", "a"),
+					newTopologyBmh("bmh-a1", "a"),
+				},
+				map[string]string{"bmh-q1": "provisioning failed"},
+			)
+			if tt.wantErr != "" {
+				g.Expect(err).To(MatchError(ContainSubstring(tt.wantErr)))
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+
+			names := map[string]string{}
+			for hostname, bmh := range pinned {
+				names[hostname] = bmh.Name
+			}
+			g.Expect(names).To(Equal(tt.want))
+
+			unused := []string{}
+			for _, bmh := range unpinned {
+				unused = append(unused, bmh.Name)
+			}
+			g.Expect(unused).To(ConsistOf(tt.wantUnused))
+		})
+	}
+}
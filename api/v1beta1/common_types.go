@@ -18,6 +18,7 @@ package v1beta1
 
 import (
 	"github.com/openstack-k8s-operators/osp-director-operator/api/shared"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 // Hash - struct to add hashes to status
@@ -53,8 +54,33 @@ type HostStatus struct {
 
 	UserDataSecretName    string `json:"userDataSecretName"`
 	NetworkDataSecretName string `json:"networkDataSecretName"`
+
+	// ImageURL the image the host got provisioned with
+	ImageURL string `json:"imageURL,omitempty"`
+
+	// UpdateState progress of the host in a rolling update
+	UpdateState HostUpdateState `json:"updateState,omitempty"`
+
+	// ProvisioningStartTime time the provisioning of the host started, unset once provisioned
+	ProvisioningStartTime *metav1.Time `json:"provisioningStartTime,omitempty"`
+
+	// FailedHostRefs BaremetalHosts which failed to provision the host and got replaced
+	FailedHostRefs []string `json:"failedHostRefs,omitempty"`
+
+	// Conditions of the host, e.g. why a BaremetalHost got replaced
+	Conditions shared.ConditionList `json:"conditions,omitempty"`
 }
 
+// HostUpdateState - progress of a host in a rolling update
+type HostUpdateState string
+
+const (
+	// HostUpdatePending - the host is outdated and waits to get updated
+	HostUpdatePending HostUpdateState = "Pending"
+	// HostUpdateInProgress - the host is getting updated
+	HostUpdateInProgress HostUpdateState = "InProgress"
+)
+
 // SyncIPsetStatus - sync relevant information from IPSet to CR status
 func SyncIPsetStatus(
 	cond *shared.Condition,
@@ -7,7 +7,11 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/openstack-k8s-operators/osp-director-operator/api/shared"
+	corev1 "k8s.io/api/core/v1"
+	k8s_errors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	goClient "sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -66,3 +70,36 @@ func getRoleNames(namespace string) (map[string]string, error) {
 
 	return found, nil
 }
+
+// ValidateCloudInitParts - validate the cloud-init parts of a BaremetalSet or VMSet. The data of parts
+// which reference a secret is validated if the secret already exists, otherwise the controller waits for it.
+func ValidateCloudInitParts(c goClient.Client, namespace string, parts []shared.CloudInitPart) error {
+	if err := shared.ValidateCloudInitParts(parts); err != nil {
+		return err
+	}
+
+	for _, part := range parts {
+		if part.SecretRef == nil {
+			continue
+		}
+
+		secret := &corev1.Secret{}
+		err := c.Get(context.TODO(), types.NamespacedName{Name: part.SecretRef.Name, Namespace: namespace}, secret)
+		if err != nil {
+			if k8s_errors.IsNotFound(err) {
+				continue
+			}
+			return err
+		}
+
+		data, ok := secret.Data[part.SecretRef.Key]
+		if !ok {
+			return fmt.Errorf("cloudInitPart %s: key %s not found in secret %s", part.Name, part.SecretRef.Key, part.SecretRef.Name)
+		}
+		if err := shared.ValidateCloudInitPartData(part.ContentType, string(data)); err != nil {
+			return fmt.Errorf("cloudInitPart %s: %w", part.Name, err)
+		}
+	}
+
+	return nil
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package v1beta1
 
 import (
+	metal3v1 "github.com/metal3-io/baremetal-operator/apis/metal3.io/v1alpha1"
 	"github.com/openstack-k8s-operators/osp-director-operator/api/shared"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -38,6 +39,8 @@ type OpenStackBaremetalSetSpec struct {
 	DeploymentSSHSecret string `json:"deploymentSSHSecret"`
 	// Interface to use for ctlplane network
 	CtlplaneInterface string `json:"ctlplaneInterface"`
+	// CtlplaneNetworkConfig Optional. Bond and/or VLAN the ctlplane network uses instead of the plain CtlplaneInterface
+	CtlplaneNetworkConfig *shared.CtlplaneNetworkConfig `json:"ctlplaneNetworkConfig,omitempty"`
 	// BmhLabelSelector allows for a sub-selection of BaremetalHosts based on arbitrary labels
 	BmhLabelSelector map[string]string `json:"bmhLabelSelector,omitempty"`
 	// Hardware requests for sub-selection of BaremetalHosts with certain hardware specs
@@ -54,6 +57,93 @@ type OpenStackBaremetalSetSpec struct {
 	// Note that subsequent TripleO deployment will overwrite these values
 	BootstrapDNS     []string `json:"bootstrapDns,omitempty"`
 	DNSSearchDomains []string `json:"dnsSearchDomains,omitempty"`
+	// CloudInitParts Optional. Site specific cloud-init user-data parts, e.g. NTP, packages, proxy settings
+	// or CA trust, added to the rendered cloud-config as multipart MIME
+	CloudInitParts []shared.CloudInitPart `json:"cloudInitParts,omitempty"`
+	// TopologySpread Optional. If supplied, BaremetalHosts get spread across the failure domains
+	// (e.g. rack, power feed, zone) identified by the BaremetalHost label TopologyKey
+	TopologySpread *TopologySpread `json:"topologySpread,omitempty"`
+	// RootDeviceHints Optional. Hints to select the disk the image gets installed on, set on each selected BaremetalHost
+	// before provisioning and reverted on deprovision
+	RootDeviceHints *metal3v1.RootDeviceHints `json:"rootDeviceHints,omitempty"`
+	// RAID Optional. RAID configuration set on each selected BaremetalHost before provisioning and reverted on deprovision
+	RAID *metal3v1.RAIDConfig `json:"raid,omitempty"`
+	// Firmware Optional. BIOS configuration set on each selected BaremetalHost before provisioning and reverted on deprovision
+	Firmware *metal3v1.FirmwareConfig `json:"firmware,omitempty"`
+	// UpdateStrategy Optional. Defines how already provisioned BaremetalHosts get updated when
+	// BaseImageURL or ProvisionServerName changes. If not set, they keep their current image.
+	UpdateStrategy *BaremetalSetUpdateStrategy `json:"updateStrategy,omitempty"`
+	// ScaleDownPolicy Optional. Defines which BaremetalHosts get removed on scale-down if not enough BaremetalHosts
+	// are annotated for deletion. If not set, scale-down requires the osp-director.openstack.org/delete-host annotation.
+	ScaleDownPolicy *shared.ScaleDownPolicy `json:"scaleDownPolicy,omitempty"`
+	// ProvisioningTimeout Optional. Time a selected BaremetalHost can take to get provisioned before it gets
+	// replaced, e.g. "90m". Only used if MaxReplacementAttempts > 0. If not set, BaremetalHosts only get
+	// replaced if they end up in an error state.
+	ProvisioningTimeout *metav1.Duration `json:"provisioningTimeout,omitempty"`
+	// MaxReplacementAttempts Optional. Number of times the BaremetalHost of a host gets replaced by another
+	// free matching BaremetalHost if it fails to provision. Failed BaremetalHosts are released and labeled
+	// with osp-director.openstack.org/quarantined. If 0, failed BaremetalHosts do not get replaced.
+	// +kubebuilder:validation:Minimum=0
+	MaxReplacementAttempts int `json:"maxReplacementAttempts,omitempty"`
+	// HostAssignments Optional. Pins hosts of the set to specific BaremetalHosts. The key is either the
+	// hostname, e.g. compute-3, or the hostname index, e.g. "3". The assignment is used when the host gets
+	// a BaremetalHost on scale-up or on replacement, BaremetalHosts pinned by name are not used for other hosts.
+	HostAssignments map[string]HostAssignment `json:"hostAssignments,omitempty"`
+	// ScalePlanCount Optional. If set, the BaremetalHosts, hostnames and IPs a scale-up of the set to this
+	// count would use get computed and written to status.scalePlan. No BaremetalHost or IP reservation
+	// gets changed, Count is not affected.
+	// +kubebuilder:validation:Minimum=0
+	ScalePlanCount *int `json:"scalePlanCount,omitempty"`
+}
+
+// HostAssignment defines the BaremetalHost a host of the set gets pinned to
+type HostAssignment struct {
+	// BmhName name of the BaremetalHost
+	BmhName string `json:"bmhName,omitempty"`
+	// BmhLabelSelector labels the BaremetalHost must have, used if BmhName is not set
+	BmhLabelSelector map[string]string `json:"bmhLabelSelector,omitempty"`
+}
+
+// BaremetalSetUpdateStrategyType is used to enumerate the update strategies of the set
+type BaremetalSetUpdateStrategyType string
+
+const (
+	// BaremetalSetUpdateStrategyRollingReprovision - deprovision and reprovision outdated BaremetalHosts in batches
+	BaremetalSetUpdateStrategyRollingReprovision BaremetalSetUpdateStrategyType = "RollingReprovision"
+	// BaremetalSetUpdateStrategyOnDelete - outdated BaremetalHosts only get the new image when they get
+	// removed from the set and provisioned again
+	BaremetalSetUpdateStrategyOnDelete BaremetalSetUpdateStrategyType = "OnDelete"
+)
+
+// BaremetalSetUpdateStrategy defines how provisioned BaremetalHosts get updated to a new image
+type BaremetalSetUpdateStrategy struct {
+	// Type of the update strategy
+	// +kubebuilder:validation:Enum=RollingReprovision;OnDelete
+	// +kubebuilder:default=OnDelete
+	Type BaremetalSetUpdateStrategyType `json:"type,omitempty"`
+	// RollingReprovision parameters, only used if Type is RollingReprovision
+	RollingReprovision *RollingReprovisionUpdateStrategy `json:"rollingReprovision,omitempty"`
+}
+
+// RollingReprovisionUpdateStrategy defines the parameters of a rolling reprovision
+type RollingReprovisionUpdateStrategy struct {
+	// MaxUnavailable is the maximum number of BaremetalHosts of the set which can be
+	// unavailable (not provisioned) while the update is in progress
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:default=1
+	MaxUnavailable int `json:"maxUnavailable,omitempty"`
+}
+
+// TopologySpread defines how the BaremetalHosts of the set get distributed across failure domains
+type TopologySpread struct {
+	// TopologyKey is the BaremetalHost label key whose values identify the failure domains.
+	// BaremetalHosts without this label are not used by the OpenStackBaremetalSet.
+	TopologyKey string `json:"topologyKey"`
+	// MaxSkew is the maximum permitted difference between the number of BaremetalHosts
+	// in any two failure domains
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:default=1
+	MaxSkew int `json:"maxSkew,omitempty"`
 }
 
 // OpenStackBaremetalSetStatus defines the observed state of OpenStackBaremetalSet
@@ -61,6 +151,32 @@ type OpenStackBaremetalSetStatus struct {
 	Conditions         shared.ConditionList                    `json:"conditions,omitempty" optional:"true"`
 	ProvisioningStatus OpenStackBaremetalSetProvisioningStatus `json:"provisioningStatus,omitempty"`
 	BaremetalHosts     map[string]HostStatus                   `json:"baremetalHosts,omitempty"`
+	// TopologyDomains the number of BaremetalHosts of the set per failure domain, if TopologySpread is used
+	TopologyDomains map[string]int `json:"topologyDomains,omitempty"`
+	// ScalePlan preview of a scale-up of the set to spec.scalePlanCount
+	ScalePlan *BaremetalSetScalePlan `json:"scalePlan,omitempty"`
+}
+
+// BaremetalSetScalePlan is the preview of a scale-up of the set
+type BaremetalSetScalePlan struct {
+	// Count the plan got computed for
+	Count int `json:"count"`
+	// Feasible is true if enough matching BaremetalHosts are available for the scale-up
+	Feasible bool `json:"feasible"`
+	// Message why the scale-up is not feasible
+	Message string `json:"message,omitempty"`
+	// Hosts which would get added to the set. The IPs are a preview, they can differ if
+	// other roles get scaled up at the same time.
+	Hosts []BaremetalSetScalePlanHost `json:"hosts,omitempty"`
+}
+
+// BaremetalSetScalePlanHost is a host which would get added to the set
+type BaremetalSetScalePlanHost struct {
+	Hostname string `json:"hostname"`
+	// BmhName name of the BaremetalHost the host would get provisioned on
+	BmhName string `json:"bmhName"`
+	// IPAddresses the host would get per network
+	IPAddresses map[string]string `json:"ipAddresses,omitempty"`
 }
 
 // OpenStackBaremetalSetProvisioningStatus represents the overall provisioning state of all BaremetalHosts in
@@ -85,6 +201,7 @@ type HardwareReqs struct {
 	CPUReqs  CPUReqs  `json:"cpuReqs,omitempty"`
 	MemReqs  MemReqs  `json:"memReqs,omitempty"`
 	DiskReqs DiskReqs `json:"diskReqs,omitempty"`
+	NicReqs  NicReqs  `json:"nicReqs,omitempty"`
 }
 
 // CPUReqs defines specific CPU hardware requests
@@ -150,6 +267,44 @@ type DiskSSDReq struct {
 	ExactMatch bool `json:"exactMatch,omitempty"`
 }
 
+// NicReqs defines specific network interface hardware requests.
+// The speed, model and PXE requests select the NICs which are taken into account for the count request.
+type NicReqs struct {
+	// If CountReq is not set, at least one NIC needs to satisfy the other requests
+	CountReq NicCountReq `json:"countReq,omitempty"`
+	SpeedReq NicSpeedReq `json:"speedReq,omitempty"`
+	// ModelReq is a regular expression the NIC model needs to match.
+	// The model is reported as vendor and product ID, e.g. "0x8086 0x1572"
+	ModelReq string `json:"modelReq,omitempty"`
+	// PXE is scalar (bool) because it wouldn't make sense to give it an "exact-match" option
+	PXEReq NicPXEReq `json:"pxeReq,omitempty"`
+}
+
+// NicCountReq defines a specific hardware request for the number of NICs
+type NicCountReq struct {
+	// +kubebuilder:validation:Minimum=1
+	Count int `json:"count,omitempty"`
+	// If ExactMatch == false, actual count > Count will match
+	ExactMatch bool `json:"exactMatch,omitempty"`
+}
+
+// NicSpeedReq defines a specific hardware request for NIC link speed
+type NicSpeedReq struct {
+	// +kubebuilder:validation:Minimum=1
+	Gbps int `json:"gbps,omitempty"`
+	// If ExactMatch == false, actual Gbps > Gbps will match
+	ExactMatch bool `json:"exactMatch,omitempty"`
+}
+
+// NicPXEReq defines a specific hardware request for PXE-capable (true) or non PXE-capable (false) NICs
+type NicPXEReq struct {
+	PXE bool `json:"pxe,omitempty"`
+	// We only actually care about PXE flag if it is true or ExactMatch is set to true.
+	// This second flag is necessary as PXE's bool zero-value (false) is indistinguishable
+	// from it being explicitly set to false
+	ExactMatch bool `json:"exactMatch,omitempty"`
+}
+
 // IsReady - Is this resource in its fully-configured (quiesced) state?
 func (instance *OpenStackBaremetalSet) IsReady() bool {
 	return instance.Status.ProvisioningStatus.State == shared.ProvisioningState(shared.BaremetalSetCondTypeProvisioned) ||
@@ -24,11 +24,14 @@ package v1beta1
 import (
 	"context"
 	"fmt"
+	"regexp"
+	"strings"
 
 	"github.com/openstack-k8s-operators/osp-director-operator/api/shared"
 	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
+	goClient "sigs.k8s.io/controller-runtime/pkg/client"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
@@ -108,7 +111,7 @@ func (r *OpenStackBaremetalSet) ValidateCreate() (admission.Warnings, error) {
 		return nil, err
 	}
 
-	if _, err := VerifyBaremetalSetScaleUp(baremetalsetlog, r, baremetalHostsList, existingBaremetalHosts); err != nil {
+	if _, _, err := VerifyBaremetalSetScaleUp(baremetalsetlog, r, baremetalHostsList, existingBaremetalHosts); err != nil {
 		return nil, err
 	}
 
@@ -143,6 +146,37 @@ func (r *OpenStackBaremetalSet) ValidateUpdate(old runtime.Object) (admission.Wa
 		return nil, fmt.Errorf("cannot change \"bmhLabelSelector\" nor \"hardwareReqs\" when previous \"count\" > 0")
 	}
 
+	//
+	// Force the topology key to remain the same unless the *old* count was 0, as the
+	// already assigned BMHs got spread across the failure domains of that key.
+	//
+	if oldInstance.Spec.Count > 0 && getTopologyKey(r) != getTopologyKey(oldInstance) {
+		return nil, fmt.Errorf("cannot change \"topologySpread.topologyKey\" when previous \"count\" > 0")
+	}
+
+	//
+	// The ctlplane bond members have to exist on the BMHs already in use, as they get
+	// used when a BMH gets reprovisioned
+	//
+	if !equality.Semantic.DeepEqual(r.Spec.CtlplaneNetworkConfig, oldInstance.Spec.CtlplaneNetworkConfig) {
+		existingBaremetalHosts, err := GetBmhHosts(
+			context.TODO(),
+			webhookClient,
+			"openshift-machine-api",
+			map[string]string{
+				shared.OwnerControllerNameLabelSelector: shared.OpenStackBaremetalSetAppLabel,
+				shared.OwnerNameLabelSelector:           r.Name,
+			},
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := VerifyBaremetalSetCtlplaneBondMembers(r, existingBaremetalHosts); err != nil {
+			return nil, err
+		}
+	}
+
 	if r.Spec.Count != oldInstance.Spec.Count {
 		//
 		// Don't allow count changes if instance.Status.BaremetalHosts contains any
@@ -181,7 +215,7 @@ func (r *OpenStackBaremetalSet) ValidateUpdate(old runtime.Object) (admission.Wa
 				return nil, err
 			}
 
-			if _, err := VerifyBaremetalSetScaleUp(baremetalsetlog, r, baremetalHostsList, existingBaremetalHosts); err != nil {
+			if _, _, err := VerifyBaremetalSetScaleUp(baremetalsetlog, r, baremetalHostsList, existingBaremetalHosts); err != nil {
 				return nil, err
 			}
 		} else if r.Spec.Count < oldInstance.Spec.Count {
@@ -293,6 +327,96 @@ func (r *OpenStackBaremetalSet) validateCr() error {
 		return err
 	}
 
+	if err := r.checkHardwareReqs(); err != nil {
+		return err
+	}
+
+	if err := r.Spec.CtlplaneNetworkConfig.Validate(); err != nil {
+		return err
+	}
+
+	if err := ValidateCloudInitParts(webhookClient, r.Namespace, r.Spec.CloudInitParts); err != nil {
+		return err
+	}
+
+	if err := r.checkHostAssignments(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *OpenStackBaremetalSet) checkHostAssignments() error {
+	if len(r.Spec.HostAssignments) == 0 {
+		return nil
+	}
+
+	hostnamePattern := regexp.MustCompile(fmt.Sprintf("^(%s-)?(0|[1-9][0-9]*)$", regexp.QuoteMeta(strings.ToLower(r.Spec.RoleName))))
+	indices := map[string]string{}
+	bmhNames := map[string]string{}
+
+	for key, assignment := range r.Spec.HostAssignments {
+		match := hostnamePattern.FindStringSubmatch(key)
+		if match == nil {
+			return fmt.Errorf("\"hostAssignments\" key %s is neither a hostname of role %s nor a hostname index", key, r.Spec.RoleName)
+		}
+
+		index := match[2]
+		if other, ok := indices[index]; ok {
+			return fmt.Errorf("\"hostAssignments\" keys %s and %s refer to the same host", key, other)
+		}
+		indices[index] = key
+
+		if (assignment.BmhName == "") == (len(assignment.BmhLabelSelector) == 0) {
+			return fmt.Errorf("\"hostAssignments\" of %s must have either \"bmhName\" or \"bmhLabelSelector\"", key)
+		}
+
+		if assignment.BmhName != "" {
+			if other, ok := bmhNames[assignment.BmhName]; ok {
+				return fmt.Errorf("\"hostAssignments\" of %s and %s pin the same BaremetalHost %s", key, other, assignment.BmhName)
+			}
+			bmhNames[assignment.BmhName] = key
+		}
+	}
+
+	//
+	// A BaremetalHost can only be pinned by one OpenStackBaremetalSet
+	//
+	baremetalSetsList := &OpenStackBaremetalSetList{}
+	listOpts := []goClient.ListOption{
+		goClient.InNamespace(r.Namespace),
+	}
+
+	if err := webhookClient.List(context.TODO(), baremetalSetsList, listOpts...); err != nil {
+		return err
+	}
+
+	for _, bmSet := range baremetalSetsList.Items {
+		if bmSet.Name == r.Name {
+			continue
+		}
+
+		for key, assignment := range bmSet.Spec.HostAssignments {
+			if other, ok := bmhNames[assignment.BmhName]; ok && assignment.BmhName != "" {
+				return fmt.Errorf("\"hostAssignments\" of %s pins BaremetalHost %s which is already pinned to %s by OpenStackBaremetalSet %s",
+					other,
+					assignment.BmhName,
+					key,
+					bmSet.Name)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (r *OpenStackBaremetalSet) checkHardwareReqs() error {
+	if r.Spec.HardwareReqs.NicReqs.ModelReq != "" {
+		if _, err := regexp.Compile(r.Spec.HardwareReqs.NicReqs.ModelReq); err != nil {
+			return fmt.Errorf("\"hardwareReqs.nicReqs.modelReq\" %s is not a valid regular expression: %w", r.Spec.HardwareReqs.NicReqs.ModelReq, err)
+		}
+	}
+
 	return nil
 }
 
@@ -303,3 +427,11 @@ func (r *OpenStackBaremetalSet) checkBaseImageReqs() error {
 
 	return nil
 }
+
+func getTopologyKey(instance *OpenStackBaremetalSet) string {
+	if instance.Spec.TopologySpread == nil {
+		return ""
+	}
+
+	return instance.Spec.TopologySpread.TopologyKey
+}
@@ -67,6 +67,14 @@ type OpenStackDeploySpec struct {
 	// +kubebuilder:default=false
 	// Skip NNCP validation to proceed deployment even if one NNCP status returns not all worker nodes are configured
 	SkipNNCPValidation bool `json:"skipNNCPValidation"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=false
+	// SnapshotBeforeDeploy creates a VirtualMachineSnapshot of each VM of the TripleO role VMSets matched by
+	// the ansible limit and waits for them to be ready before the deploy job starts. The snapshot set is named
+	// after the OpenStackDeploy and the config version, a new config version gets a new snapshot set.
+	// It can be restored using an OpenStackVMSetRestoreRequest.
+	SnapshotBeforeDeploy bool `json:"snapshotBeforeDeploy"`
 }
 
 // OpenStackDeployStatus defines the observed state of OpenStackDeploy
@@ -80,6 +88,9 @@ type OpenStackDeployStatus struct {
 	// CurrentReason
 	CurrentReason shared.ConditionReason `json:"currentReason"`
 
+	// SnapshotSet name of the VirtualMachineSnapshot set taken before the deploy of the config version
+	SnapshotSet string `json:"snapshotSet,omitempty"`
+
 	Conditions shared.ConditionList `json:"conditions,omitempty" optional:"true"`
 }
 
@@ -30,12 +30,18 @@ type IPReservation struct {
 	// +kubebuilder:default=false
 	ServiceVIP bool `json:"serviceVIP"`
 	Deleted    bool `json:"deleted"`
+	// +kubebuilder:validation:Optional
+	// IPv6 address of the host on a dual-stack network, IP holds the IPv4 address
+	IPv6 string `json:"ipv6,omitempty"`
 }
 
 // NodeIPReservation contains an IP and Deleted flag
 type NodeIPReservation struct {
 	IP      string `json:"ip"`
 	Deleted bool   `json:"deleted"`
+	// +kubebuilder:validation:Optional
+	// IPv6 address of the host on a dual-stack network, IP holds the IPv4 address
+	IPv6 string `json:"ipv6,omitempty"`
 }
 
 // Route definition
@@ -95,6 +101,15 @@ type OpenStackNetSpec struct {
 	// Routes, list of networks that should be routed via network gateway.
 	Routes []Route `json:"routes"`
 
+	// +kubebuilder:validation:Optional
+	// ExcludeRanges, addresses within the allocation range which do not get assigned
+	ExcludeRanges []string `json:"excludeRanges,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// IPv6 details of a dual-stack network. Cidr, AllocationStart, AllocationEnd, Gateway and Routes
+	// hold the IPv4 details then.
+	IPv6 *NetDetails `json:"ipv6,omitempty"`
+
 	// +kubebuilder:validation:Required
 	// AttachConfiguration, used for virtual machines to attach to this network
 	AttachConfiguration string `json:"attachConfiguration"`
@@ -130,6 +145,10 @@ type OpenStackNetStatus struct {
 	// ReservedIPCount - the count of all IPs ever reserved on this network
 	ReservedIPCount int `json:"reservedIpCount"`
 
+	// ExcludedIPCount - the count of the addresses of the allocation ranges which are excluded
+	// by the exclude ranges
+	ExcludedIPCount int64 `json:"excludedIpCount,omitempty"`
+
 	// CurrentState - the overall state of this network
 	CurrentState shared.ConditionType `json:"currentState"`
 
@@ -157,6 +176,7 @@ func (instance *OpenStackNet) IsReady() bool {
 // +kubebuilder:printcolumn:name="Gateway",type=string,JSONPath=`.spec.gateway`
 // +kubebuilder:printcolumn:name="Routes",type=string,JSONPath=`.spec.routes`
 // +kubebuilder:printcolumn:name="Reserved IPs",type="integer",JSONPath=".status.reservedIpCount"
+// +kubebuilder:printcolumn:name="Excluded IPs",type="integer",JSONPath=".status.excludedIpCount"
 // +kubebuilder:printcolumn:name="Status",type=string,JSONPath=`.status.currentState`,description="Status"
 
 // OpenStackNet represents the IPAM configuration for baremetal and VM hosts within OpenStack Overcloud deployment
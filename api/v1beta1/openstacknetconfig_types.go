@@ -28,6 +28,8 @@ const (
 	ControlPlaneNameLower string = "ctlplane"
 	// DefaultDomainName -
 	DefaultDomainName string = "localdomain"
+	// DefaultDNSRecordTTL - default TTL of the generated DNS records in seconds
+	DefaultDNSRecordTTL int64 = 3600
 )
 
 // NetDetails of a subnet
@@ -51,6 +53,12 @@ type NetDetails struct {
 	// +kubebuilder:validation:Optional
 	// Routes, list of networks that should be routed via network gateway.
 	Routes []Route `json:"routes"`
+
+	// +kubebuilder:validation:Optional
+	// ExcludeRanges, addresses within the allocation range which do not get assigned, e.g. used by
+	// switches or load balancers. Either a CIDR, e.g. 172.17.0.16/28, or a start-end pair,
+	// e.g. 172.17.0.20-172.17.0.30
+	ExcludeRanges []string `json:"excludeRanges,omitempty"`
 }
 
 // Subnet defines the tripleo subnet
@@ -128,6 +136,11 @@ type OpenStackNetConfigSpec struct {
 	// DNSSearchDomains, list of DNS search domains
 	DNSSearchDomains []string `json:"dnsSearchDomains,omitempty"`
 
+	// +kubebuilder:validation:Optional
+	// DNSRecords, configuration of the A/AAAA and PTR records of the hosts and VIPs on all networks, which
+	// get written to the <name>-dns-records ConfigMap
+	DNSRecords DNSRecordsConfig `json:"dnsRecords,omitempty"`
+
 	// +kubebuilder:validation:Required
 	// Networks, list of all tripleo networks of the deployment
 	Networks []Network `json:"networks"`
@@ -153,10 +166,25 @@ type OpenStackNetConfigSpec struct {
 	Reservations map[string]OpenStackNetStaticNodeReservations `json:"reservations"`
 }
 
+// DNSRecordsConfig defines the generated DNS records
+type DNSRecordsConfig struct {
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	// TTL of the records in seconds (default: 3600)
+	TTL int64 `json:"ttl,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=false
+	// ExternalDNS, also create an external-dns DNSEndpoint with the records. Requires the DNSEndpoint CRD
+	// of external-dns to be installed.
+	ExternalDNS bool `json:"externalDNS,omitempty"`
+}
+
 // OpenStackNetStaticNodeReservations defines the static reservations of the nodes
 type OpenStackNetStaticNodeReservations struct {
 	// +kubebuilder:validation:Optional
-	// IPReservations, manual/static IP address reservations per network
+	// IPReservations, manual/static IP address reservations per network. The IPv6 address on a
+	// dual-stack network uses the network name with _ipv6 suffix, e.g. internal_api_ipv6
 	IPReservations map[string]string `json:"ipReservations"`
 
 	// +kubebuilder:validation:Optional
@@ -182,10 +210,18 @@ type OpenStackNetConfigStatus struct {
 
 // OpenStackHostStatus per host IP set
 type OpenStackHostStatus struct {
+	// IPAddresses per network, on a dual-stack network the network holds the IPv4 address
+	// and IPv6AddressKey of the network the IPv6 address
 	IPAddresses          map[string]string `json:"ipaddresses"`
 	OVNBridgeMacAdresses map[string]string `json:"ovnBridgeMacAdresses"`
 }
 
+// IPv6AddressKey - key of the IPv6 address of a dual-stack network in the IPAddresses of a host
+// and in the static IPReservations of a node, e.g. internal_api_ipv6
+func IPv6AddressKey(nameLower string) string {
+	return nameLower + "_ipv6"
+}
+
 // OpenStackNetConfigProvisioningStatus represents the overall provisioning state of
 // the OpenStackNetConfig (with an optional explanatory message)
 type OpenStackNetConfigProvisioningStatus struct {
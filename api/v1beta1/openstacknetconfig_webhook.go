@@ -17,6 +17,7 @@ limitations under the License.
 package v1beta1
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"net"
@@ -24,7 +25,10 @@ import (
 
 	"github.com/openstack-k8s-operators/osp-director-operator/api/shared"
 	nmstate "github.com/openstack-k8s-operators/osp-director-operator/pkg/nmstate"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
@@ -128,6 +132,13 @@ func (r *OpenStackNetConfig) Default() {
 	if r.Spec.DNSSearchDomains == nil {
 		r.Spec.DNSSearchDomains = []string{}
 	}
+
+	//
+	// The default TTL of the DNS records
+	//
+	if r.Spec.DNSRecords.TTL == 0 {
+		r.Spec.DNSRecords.TTL = DefaultDNSRecordTTL
+	}
 }
 
 // TODO(user): change verbs to "verbs=create;update;delete" if you want to enable deletion validation.
@@ -201,6 +212,18 @@ func (r *OpenStackNetConfig) ValidateUpdate(old runtime.Object) (admission.Warni
 		return nil, err
 	}
 
+	//
+	// Verify the network definitions if they or the attach configurations changed,
+	// e.g. added networks, before their OpenStackNets get created
+	//
+	if !equality.Semantic.DeepEqual(r.Spec.Networks, oldInstance.Spec.Networks) ||
+		!equality.Semantic.DeepEqual(r.Spec.AttachConfigurations, oldInstance.Spec.AttachConfigurations) {
+		err = r.validateNetworks()
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	//
 	// Validate static IP address reservations
 	//
@@ -344,6 +367,13 @@ func (r *OpenStackNetConfig) validateStaticIPReservations() error {
 		}
 		for node, res := range osNet.Status.Reservations {
 			netReservations[osNet.Spec.NameLower][res.IP] = node
+			if res.IPv6 != "" {
+				ipv6Key := IPv6AddressKey(osNet.Spec.NameLower)
+				if netReservations[ipv6Key] == nil {
+					netReservations[ipv6Key] = map[string]string{}
+				}
+				netReservations[ipv6Key][res.IPv6] = node
+			}
 		}
 	}
 
@@ -365,14 +395,55 @@ func (r *OpenStackNetConfig) validateStaticIPReservations() error {
 			//
 			for _, osNet := range r.Spec.Networks {
 				for _, subnet := range osNet.Subnets {
-					if subnet.Name == netName {
-						var ipnet *net.IPNet
-						if subnet.IPv4.Cidr != "" {
-							_, ipnet, _ = net.ParseCIDR(subnet.IPv4.Cidr)
-						} else {
-							_, ipnet, _ = net.ParseCIDR(subnet.IPv6.Cidr)
+					var ipnet *net.IPNet
+					var excludeRanges []string
+					switch {
+					case subnet.Name == netName && subnet.IPv4.Cidr != "":
+						_, ipnet, _ = net.ParseCIDR(subnet.IPv4.Cidr)
+						excludeRanges = subnet.IPv4.ExcludeRanges
+					case subnet.Name == netName:
+						_, ipnet, _ = net.ParseCIDR(subnet.IPv6.Cidr)
+						excludeRanges = subnet.IPv6.ExcludeRanges
+					case IPv6AddressKey(subnet.Name) == netName:
+						// IPv6 address of a dual-stack subnet
+						if subnet.IPv4.Cidr == "" || subnet.IPv6.Cidr == "" {
+							return fmt.Errorf("IP address %s of node %s is for the IPv6 family of subnet %s, which is not dual-stack",
+								resIP,
+								node,
+								subnet.Name,
+							)
+						}
+						// the IPv6 address is only reserved together with the IPv4 address of the subnet
+						if _, ok := res.IPReservations[subnet.Name]; !ok {
+							return fmt.Errorf("IP address %s of node %s requires a static IPv4 reservation on subnet %s",
+								resIP,
+								node,
+								subnet.Name,
+							)
 						}
+						_, ipnet, _ = net.ParseCIDR(subnet.IPv6.Cidr)
+						excludeRanges = subnet.IPv6.ExcludeRanges
+					}
 
+					//
+					// static reservations must not be within the exclude ranges
+					//
+					for _, excludeRange := range excludeRanges {
+						start, end, err := shared.ParseExcludeRange(excludeRange)
+						if err != nil {
+							return err
+						}
+						if bytes.Compare(ip.To16(), start.To16()) >= 0 && bytes.Compare(ip.To16(), end.To16()) <= 0 {
+							return fmt.Errorf("IP address %s of node %s is within the exclude range %s of subnet %s",
+								resIP,
+								node,
+								excludeRange,
+								subnet.Name,
+							)
+						}
+					}
+
+					if ipnet != nil {
 						if !ipnet.Contains(ip) {
 							return fmt.Errorf("IP address %s of node %s conflicts with subnet %s definition %s",
 								resIP,
@@ -415,6 +486,100 @@ func (r *OpenStackNetConfig) validateStaticIPReservations() error {
 	return nil
 }
 
+// validateNetDetails - validates the IPv4 or IPv6 details of a subnet
+func validateNetDetails(path *field.Path, details NetDetails, ipv6 bool) (*net.IPNet, field.ErrorList) {
+	allErrs := field.ErrorList{}
+
+	ip, ipnet, err := net.ParseCIDR(details.Cidr)
+	if err != nil {
+		return nil, append(allErrs, field.Invalid(path.Child("cidr"), details.Cidr, err.Error()))
+	}
+
+	// validate provided IP is of the correct family
+	if ipv6 && !shared.IsIPv6(ip) {
+		return nil, append(allErrs, field.Invalid(path.Child("cidr"), details.Cidr, "not a valid IPv6 cidr"))
+	}
+	if !ipv6 && !shared.IsIPv4(ip) {
+		return nil, append(allErrs, field.Invalid(path.Child("cidr"), details.Cidr, "not a valid IPv4 cidr"))
+	}
+
+	//
+	// check if subnet AllocationStart, AllocationEnd and Gateway has
+	// * a valid format
+	// * are part of the specified subnet.Cidr
+	//
+	allocationStart, errs := validateSubnetIP(path.Child("allocationStart"), details.AllocationStart, ipnet)
+	allErrs = append(allErrs, errs...)
+	allocationEnd, errs := validateSubnetIP(path.Child("allocationEnd"), details.AllocationEnd, ipnet)
+	allErrs = append(allErrs, errs...)
+	if details.Gateway != "" {
+		_, errs := validateSubnetIP(path.Child("gateway"), details.Gateway, ipnet)
+		allErrs = append(allErrs, errs...)
+	}
+
+	if allocationStart != nil && allocationEnd != nil &&
+		bytes.Compare(allocationStart.To16(), allocationEnd.To16()) > 0 {
+		allErrs = append(allErrs, field.Invalid(path.Child("allocationEnd"), details.AllocationEnd,
+			fmt.Sprintf("must not be lower than allocationStart %s", details.AllocationStart)))
+	}
+
+	//
+	// check if the exclude ranges are within the subnet.Cidr
+	//
+	for idx, excludeRange := range details.ExcludeRanges {
+		start, end, err := shared.ParseExcludeRange(excludeRange)
+		if err != nil {
+			allErrs = append(allErrs, field.Invalid(path.Child("excludeRanges").Index(idx), excludeRange, err.Error()))
+			continue
+		}
+		if !ipnet.Contains(start) || !ipnet.Contains(end) {
+			allErrs = append(allErrs, field.Invalid(path.Child("excludeRanges").Index(idx), excludeRange,
+				fmt.Sprintf("conflicts with cidr %s", ipnet.String())))
+		}
+	}
+
+	//
+	// check if the routes have a destination of the subnet family and a nexthop
+	// which is directly reachable within the subnet.Cidr
+	//
+	for idx, route := range details.Routes {
+		routePath := path.Child("routes").Index(idx)
+
+		destIP, _, err := net.ParseCIDR(route.Destination)
+		switch {
+		case err != nil:
+			allErrs = append(allErrs, field.Invalid(routePath.Child("destination"), route.Destination, err.Error()))
+		case shared.IsIPv6(destIP) != ipv6:
+			allErrs = append(allErrs, field.Invalid(routePath.Child("destination"), route.Destination,
+				fmt.Sprintf("not of the same IP family as cidr %s", ipnet.String())))
+		}
+
+		nexthop := net.ParseIP(route.Nexthop)
+		switch {
+		case nexthop == nil:
+			allErrs = append(allErrs, field.Invalid(routePath.Child("nexthop"), route.Nexthop, "invalid IP address"))
+		case !ipnet.Contains(nexthop):
+			allErrs = append(allErrs, field.Invalid(routePath.Child("nexthop"), route.Nexthop,
+				fmt.Sprintf("not reachable within cidr %s", ipnet.String())))
+		}
+	}
+
+	return ipnet, allErrs
+}
+
+// validateSubnetIP - validates the format of an IP address and that it is part of the ipnet
+func validateSubnetIP(path *field.Path, ipToTest string, ipnet *net.IPNet) (net.IP, field.ErrorList) {
+	ip := net.ParseIP(ipToTest)
+	if ip == nil {
+		return nil, field.ErrorList{field.Invalid(path, ipToTest, "invalid IP address")}
+	}
+	if !ipnet.Contains(ip) {
+		return nil, field.ErrorList{field.Invalid(path, ipToTest, fmt.Sprintf("conflicts with cidr %s", ipnet.String()))}
+	}
+
+	return ip, nil
+}
+
 func checkDomainName(domainName string) error {
 
 	// TODO: implement the same validation as freeipa validate_domain_name()
@@ -425,96 +590,113 @@ func checkDomainName(domainName string) error {
 	return nil
 }
 
+// subnetCidr - parsed cidr of a subnet and its field path
+type subnetCidr struct {
+	path  *field.Path
+	ipnet *net.IPNet
+}
+
 // validateNetworks - validates the details provided for a networks definition
+// and their consistency with each other and with the attach configurations
 func (r *OpenStackNetConfig) validateNetworks() error {
+	allErrs := field.ErrorList{}
+	networksPath := field.NewPath("spec", "networks")
 
 	isCtlplaneNetwork := false
+	cidrs := []subnetCidr{}
+	// attachConfiguration -> vlan -> path of the first subnet using it
+	vlans := map[string]map[int]*field.Path{}
 
-	for _, osnet := range r.Spec.Networks {
+	for netIdx, osnet := range r.Spec.Networks {
+		netPath := networksPath.Index(netIdx)
 		if osnet.IsControlPlane {
 			isCtlplaneNetwork = true
 		}
 
-		for _, subnet := range osnet.Subnets {
+		attachConfigurations := map[string]bool{}
+
+		for subnetIdx, subnet := range osnet.Subnets {
+			subnetPath := netPath.Child("subnets").Index(subnetIdx)
+
 			//
-			// A subnet can only have either IPv4 or IPv6 definition
+			// A subnet needs an IPv4 or IPv6 definition, or both for a dual-stack subnet
 			//
-			if subnet.IPv4.Cidr != "" && subnet.IPv6.Cidr != "" {
-				return fmt.Errorf("subnet %s can only be IPv4 or IPv6, not both", subnet.Name)
+			if subnet.IPv4.Cidr == "" && subnet.IPv6.Cidr == "" {
+				// we should never hit this as cidr is a required parameter
+				allErrs = append(allErrs, field.Required(subnetPath, "either ipv4.cidr or ipv6.cidr must be provided"))
 			}
 
-			var ipnet *net.IPNet
-			var err error
-			subnetDetails := map[string]string{}
 			if subnet.IPv4.Cidr != "" {
-				var ip net.IP
-				ip, ipnet, err = net.ParseCIDR(subnet.IPv4.Cidr)
-				if err != nil {
-					return err
-				}
-
-				// validate provided IP is correct IPv4
-				if shared.IsIPv4(ip) {
-					subnetDetails["AllocationStart"] = subnet.IPv4.AllocationStart
-					subnetDetails["AllocationEnd"] = subnet.IPv4.AllocationEnd
-					if subnet.IPv4.Gateway != "" {
-						subnetDetails["Gateway"] = subnet.IPv4.Gateway
-					}
-				} else {
-					return fmt.Errorf("%s is not a valid IPv4 cidr", subnet.IPv4.Cidr)
+				ipnet, errs := validateNetDetails(subnetPath.Child("ipv4"), subnet.IPv4, false)
+				allErrs = append(allErrs, errs...)
+				if ipnet != nil {
+					cidrs = append(cidrs, subnetCidr{path: subnetPath.Child("ipv4", "cidr"), ipnet: ipnet})
 				}
-			} else if subnet.IPv6.Cidr != "" {
-				var ip net.IP
-				ip, ipnet, err = net.ParseCIDR(subnet.IPv6.Cidr)
-				if err != nil {
-					return err
+			}
+			if subnet.IPv6.Cidr != "" {
+				ipnet, errs := validateNetDetails(subnetPath.Child("ipv6"), subnet.IPv6, true)
+				allErrs = append(allErrs, errs...)
+				if ipnet != nil {
+					cidrs = append(cidrs, subnetCidr{path: subnetPath.Child("ipv6", "cidr"), ipnet: ipnet})
 				}
+			}
 
-				// validate provided IP is correct IPv6
-				if shared.IsIPv6(ip) {
-					subnetDetails["AllocationStart"] = subnet.IPv6.AllocationStart
-					subnetDetails["AllocationEnd"] = subnet.IPv6.AllocationEnd
-					if subnet.IPv6.Gateway != "" {
-						subnetDetails["Gateway"] = subnet.IPv6.Gateway
-					}
-
+			//
+			// A VLAN ID can only be used once per attach configuration
+			//
+			if subnet.Vlan != 0 {
+				if vlans[subnet.AttachConfiguration] == nil {
+					vlans[subnet.AttachConfiguration] = map[int]*field.Path{}
+				}
+				if vlanPath, ok := vlans[subnet.AttachConfiguration][subnet.Vlan]; ok {
+					allErrs = append(allErrs, field.Duplicate(subnetPath.Child("vlan"),
+						fmt.Sprintf("%d already used by %s on attachConfiguration %s", subnet.Vlan, vlanPath.String(), subnet.AttachConfiguration)))
 				} else {
-					return fmt.Errorf("%s is not a valid IPv6 cidr", subnet.IPv4.Cidr)
+					vlans[subnet.AttachConfiguration][subnet.Vlan] = subnetPath
 				}
-			} else {
-				// we should never hit this as cidr is a required parameter
-				return fmt.Errorf("either IPv4.Cidr or IPv6.Cidr must be provided %s", subnet.Name)
 			}
 
 			//
-			// check if subnet AllocationStart, AllocationEnd and Gateway has
-			// * a valid format
-			// * are part of the specified subnet.Cird
+			// The MTU of the network must fit into the MTU of the bridge of the attach configuration
 			//
-			for ipType, ipToTest := range subnetDetails {
-				ip := net.ParseIP(ipToTest)
-				if ip == nil {
-					return fmt.Errorf("%s IP address %s of subnet %s has an invalid format",
-						ipType,
-						ipToTest,
-						subnet.Name)
-				}
-				if !ipnet.Contains(ip) {
-					return fmt.Errorf("%s IP address %s of subnet %s conflicts with cidr %s",
-						ipType,
-						ipToTest,
-						subnet.Name,
-						ipnet.String())
+			if attachCfg, ok := r.Spec.AttachConfigurations[subnet.AttachConfiguration]; ok && !attachConfigurations[subnet.AttachConfiguration] {
+				attachConfigurations[subnet.AttachConfiguration] = true
+
+				bridgeMTU, err := nmstate.GetDesiredStateBridgeMTU(attachCfg.NodeNetworkConfigurationPolicy.DesiredState.Raw)
+				if err != nil {
+					allErrs = append(allErrs, field.Invalid(
+						field.NewPath("spec", "attachConfigurations").Key(subnet.AttachConfiguration).Child("nodeNetworkConfigurationPolicy", "desiredState"),
+						subnet.AttachConfiguration,
+						err.Error()))
+				} else if bridgeMTU > 0 && osnet.MTU > bridgeMTU {
+					allErrs = append(allErrs, field.Invalid(netPath.Child("mtu"), osnet.MTU,
+						fmt.Sprintf("larger than the MTU %d of the bridge of attachConfiguration %s", bridgeMTU, subnet.AttachConfiguration)))
 				}
 			}
 		}
 	}
 
+	//
+	// The cidrs of all networks and subnets must not overlap
+	//
+	for i := range cidrs {
+		for j := i + 1; j < len(cidrs); j++ {
+			if cidrs[i].ipnet.Contains(cidrs[j].ipnet.IP) || cidrs[j].ipnet.Contains(cidrs[i].ipnet.IP) {
+				allErrs = append(allErrs, field.Invalid(cidrs[j].path, cidrs[j].ipnet.String(),
+					fmt.Sprintf("overlaps with cidr %s of %s", cidrs[i].ipnet.String(), cidrs[i].path.String())))
+			}
+		}
+	}
+
 	//
 	// if there is no ctlplane tagged network, return error
 	//
 	if !isCtlplaneNetwork {
-		return fmt.Errorf("no network tagged as IsControlPlane")
+		allErrs = append(allErrs, field.Required(networksPath, "no network tagged as isControlPlane"))
+	}
+
+	if len(allErrs) > 0 {
+		return apierrors.NewInvalid(GroupVersion.WithKind("OpenStackNetConfig").GroupKind(), r.Name, allErrs)
 	}
 
 	return nil
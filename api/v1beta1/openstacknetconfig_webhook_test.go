@@ -0,0 +1,342 @@
+package v1beta1
+
+import (
+	"testing"
+
+	nmstateapi "github.com/nmstate/kubernetes-nmstate/api/shared"
+	. "github.com/onsi/gomega" //revive:disable:dot-imports
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+func TestValidateNetworks(t *testing.T) {
+	ipv4 := NetDetails{
+		Cidr:            "172.17.0.0/24",
+		AllocationStart: "172.17.0.10",
+		AllocationEnd:   "172.17.0.250",
+	}
+	ipv6 := NetDetails{
+		Cidr:            "fd00:fd00:fd00:2000::/64",
+		AllocationStart: "fd00:fd00:fd00:2000::10",
+		AllocationEnd:   "fd00:fd00:fd00:2000:ffff:ffff:ffff:fffe",
+	}
+
+	tests := []struct {
+		name    string
+		subnet  Subnet
+		wantErr bool
+	}{
+		{
+			name:   "IPv4 subnet",
+			subnet: Subnet{Name: "internal_api", IPv4: ipv4},
+		},
+		{
+			name:   "IPv6 subnet",
+			subnet: Subnet{Name: "internal_api", IPv6: ipv6},
+		},
+		{
+			name:   "dual-stack subnet",
+			subnet: Subnet{Name: "internal_api", IPv4: ipv4, IPv6: ipv6},
+		},
+		{
+			name:    "IPv6 cidr as IPv4 details",
+			subnet:  Subnet{Name: "internal_api", IPv4: ipv6, IPv6: ipv6},
+			wantErr: true,
+		},
+		{
+			name: "IPv6 allocation range outside of the cidr",
+			subnet: Subnet{Name: "internal_api", IPv4: ipv4, IPv6: NetDetails{
+				Cidr:            ipv6.Cidr,
+				AllocationStart: "fd00:fd00:fd00:3000::10",
+				AllocationEnd:   ipv6.AllocationEnd,
+			}},
+			wantErr: true,
+		},
+		{
+			name: "gateway outside of the cidr",
+			subnet: Subnet{Name: "internal_api", IPv4: NetDetails{
+				Cidr:            ipv4.Cidr,
+				AllocationStart: ipv4.AllocationStart,
+				AllocationEnd:   ipv4.AllocationEnd,
+				Gateway:         "172.17.1.1",
+			}},
+			wantErr: true,
+		},
+		{
+			name: "reversed allocation range",
+			subnet: Subnet{Name: "internal_api", IPv4: NetDetails{
+				Cidr:            ipv4.Cidr,
+				AllocationStart: ipv4.AllocationEnd,
+				AllocationEnd:   ipv4.AllocationStart,
+			}},
+			wantErr: true,
+		},
+		{
+			name: "route nexthop within the cidr",
+			subnet: Subnet{Name: "internal_api", IPv4: NetDetails{
+				Cidr:            ipv4.Cidr,
+				AllocationStart: ipv4.AllocationStart,
+				AllocationEnd:   ipv4.AllocationEnd,
+				Routes:          []Route{{Destination: "172.18.0.0/24", Nexthop: "172.17.0.1"}},
+			}},
+		},
+		{
+			name: "route nexthop not reachable",
+			subnet: Subnet{Name: "internal_api", IPv4: NetDetails{
+				Cidr:            ipv4.Cidr,
+				AllocationStart: ipv4.AllocationStart,
+				AllocationEnd:   ipv4.AllocationEnd,
+				Routes:          []Route{{Destination: "172.18.0.0/24", Nexthop: "172.18.0.1"}},
+			}},
+			wantErr: true,
+		},
+		{
+			name: "route destination of the wrong family",
+			subnet: Subnet{Name: "internal_api", IPv4: NetDetails{
+				Cidr:            ipv4.Cidr,
+				AllocationStart: ipv4.AllocationStart,
+				AllocationEnd:   ipv4.AllocationEnd,
+				Routes:          []Route{{Destination: "fd00:fd00:fd00:3000::/64", Nexthop: "172.17.0.1"}},
+			}},
+			wantErr: true,
+		},
+		{
+			name: "cidr overlapping the ctlplane",
+			subnet: Subnet{Name: "internal_api", IPv4: NetDetails{
+				Cidr:            "192.168.0.0/16",
+				AllocationStart: "192.168.30.10",
+				AllocationEnd:   "192.168.30.250",
+			}},
+			wantErr: true,
+		},
+		{
+			name: "exclude ranges",
+			subnet: Subnet{Name: "internal_api", IPv4: NetDetails{
+				Cidr:            ipv4.Cidr,
+				AllocationStart: ipv4.AllocationStart,
+				AllocationEnd:   ipv4.AllocationEnd,
+				ExcludeRanges:   []string{"172.17.0.16/28", "172.17.0.40-172.17.0.50"},
+			}},
+		},
+		{
+			name: "exclude range outside of the cidr",
+			subnet: Subnet{Name: "internal_api", IPv4: NetDetails{
+				Cidr:            ipv4.Cidr,
+				AllocationStart: ipv4.AllocationStart,
+				AllocationEnd:   ipv4.AllocationEnd,
+				ExcludeRanges:   []string{"172.17.0.250-172.17.1.10"},
+			}},
+			wantErr: true,
+		},
+		{
+			name: "exclude range of the wrong family",
+			subnet: Subnet{Name: "internal_api", IPv4: ipv4, IPv6: NetDetails{
+				Cidr:            ipv6.Cidr,
+				AllocationStart: ipv6.AllocationStart,
+				AllocationEnd:   ipv6.AllocationEnd,
+				ExcludeRanges:   []string{"172.17.0.16/28"},
+			}},
+			wantErr: true,
+		},
+		{
+			name: "invalid exclude range",
+			subnet: Subnet{Name: "internal_api", IPv4: NetDetails{
+				Cidr:            ipv4.Cidr,
+				AllocationStart: ipv4.AllocationStart,
+				AllocationEnd:   ipv4.AllocationEnd,
+				ExcludeRanges:   []string{"172.17.0.50-172.17.0.40"},
+			}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			netConfig := &OpenStackNetConfig{
+				Spec: OpenStackNetConfigSpec{
+					Networks: []Network{
+						{
+							Name:           "Control",
+							NameLower:      "ctlplane",
+							IsControlPlane: true,
+							Subnets: []Subnet{
+								{
+									Name: "ctlplane",
+									IPv4: NetDetails{
+										Cidr:            "192.168.25.0/24",
+										AllocationStart: "192.168.25.100",
+										AllocationEnd:   "192.168.25.250",
+									},
+								},
+							},
+						},
+						{
+							Name:      "InternalApi",
+							NameLower: "internal_api",
+							Subnets:   []Subnet{tt.subnet},
+						},
+					},
+				},
+			}
+
+			err := netConfig.validateNetworks()
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).ToNot(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestValidateNetworksConsistency(t *testing.T) {
+	attachConfigurations := map[string]NodeConfigurationPolicy{
+		"br-osp": {
+			NodeNetworkConfigurationPolicy: nmstateapi.NodeNetworkConfigurationPolicySpec{
+				DesiredState: nmstateapi.State{
+					Raw: []byte(`
+interfaces:
+- bridge:
+    port:
+    - name: enp7s0
+  name: br-osp
+  state: up
+  type: linux-bridge
+  mtu: 9000
+`),
+				},
+			},
+		},
+		"br-ex": {
+			NodeNetworkConfigurationPolicy: nmstateapi.NodeNetworkConfigurationPolicySpec{
+				DesiredState: nmstateapi.State{
+					Raw: []byte(`
+interfaces:
+- bridge:
+    port:
+    - name: enp6s0
+  name: br-ex
+  state: up
+  type: linux-bridge
+`),
+				},
+			},
+		},
+	}
+
+	// network - a network with a single /24 subnet of the prefix, e.g. 172.17.0
+	network := func(name string, mtu int, prefix string, vlan int, attachConfiguration string) Network {
+		return Network{
+			Name:      name,
+			NameLower: name,
+			MTU:       mtu,
+			Subnets: []Subnet{
+				{
+					Name: name,
+					IPv4: NetDetails{
+						Cidr:            prefix + ".0/24",
+						AllocationStart: prefix + ".10",
+						AllocationEnd:   prefix + ".250",
+					},
+					Vlan:                vlan,
+					AttachConfiguration: attachConfiguration,
+				},
+			},
+		}
+	}
+
+	tests := []struct {
+		name      string
+		networks  []Network
+		wantPaths []string
+	}{
+		{
+			name: "consistent networks",
+			networks: []Network{
+				network("internal_api", 1500, "172.17.0", 20, "br-osp"),
+				network("storage", 9000, "172.18.0", 30, "br-osp"),
+				network("tenant", 1500, "172.19.0", 20, "br-ex"),
+			},
+		},
+		{
+			name: "overlapping cidrs",
+			networks: []Network{
+				network("internal_api", 1500, "172.17.0", 20, "br-osp"),
+				network("storage", 1500, "172.17.0", 30, "br-osp"),
+			},
+			wantPaths: []string{"spec.networks[2].subnets[0].ipv4.cidr"},
+		},
+		{
+			name: "duplicate vlan on one attach configuration",
+			networks: []Network{
+				network("internal_api", 1500, "172.17.0", 20, "br-osp"),
+				network("storage", 1500, "172.18.0", 20, "br-osp"),
+			},
+			wantPaths: []string{"spec.networks[2].subnets[0].vlan"},
+		},
+		{
+			name: "mtu larger than the bridge mtu",
+			networks: []Network{
+				network("internal_api", 9216, "172.17.0", 20, "br-osp"),
+			},
+			wantPaths: []string{"spec.networks[1].mtu"},
+		},
+		{
+			name: "all errors get reported",
+			networks: []Network{
+				network("internal_api", 9216, "172.17.0", 20, "br-osp"),
+				network("storage", 1500, "172.17.0", 20, "br-osp"),
+			},
+			wantPaths: []string{
+				"spec.networks[1].mtu",
+				"spec.networks[2].subnets[0].vlan",
+				"spec.networks[2].subnets[0].ipv4.cidr",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			netConfig := &OpenStackNetConfig{
+				Spec: OpenStackNetConfigSpec{
+					AttachConfigurations: attachConfigurations,
+					Networks: append([]Network{
+						{
+							Name:           "Control",
+							NameLower:      "ctlplane",
+							IsControlPlane: true,
+							MTU:            1500,
+							Subnets: []Subnet{
+								{
+									Name: "ctlplane",
+									IPv4: NetDetails{
+										Cidr:            "192.168.25.0/24",
+										AllocationStart: "192.168.25.100",
+										AllocationEnd:   "192.168.25.250",
+									},
+									AttachConfiguration: "br-ex",
+								},
+							},
+						},
+					}, tt.networks...),
+				},
+			}
+
+			err := netConfig.validateNetworks()
+			if len(tt.wantPaths) == 0 {
+				g.Expect(err).ToNot(HaveOccurred())
+				return
+			}
+
+			g.Expect(apierrors.IsInvalid(err)).To(BeTrue())
+			paths := []string{}
+			for _, cause := range err.(*apierrors.StatusError).ErrStatus.Details.Causes {
+				paths = append(paths, cause.Field)
+			}
+			g.Expect(paths).To(ConsistOf(tt.wantPaths))
+		})
+	}
+}
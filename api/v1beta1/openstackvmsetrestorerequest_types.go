@@ -0,0 +1,85 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"github.com/openstack-k8s-operators/osp-director-operator/api/shared"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// OpenStackVMSetRestoreRequestSpec defines the desired state of OpenStackVMSetRestoreRequest
+type OpenStackVMSetRestoreRequestSpec struct {
+	// +kubebuilder:validation:MinLength=1
+	// VMSet name of the OpenStackVMSet to restore
+	VMSet string `json:"vmSet"`
+
+	// +kubebuilder:validation:MinLength=1
+	// SnapshotSet name of the VirtualMachineSnapshot set to restore, e.g. the snapshotSet in the status
+	// of the OpenStackDeploy which created the snapshots with snapshotBeforeDeploy
+	SnapshotSet string `json:"snapshotSet"`
+}
+
+// OpenStackVMSetRestoreRequestStatus defines the observed state of OpenStackVMSetRestoreRequest
+type OpenStackVMSetRestoreRequestStatus struct {
+	// CompletionTimestamp - If the request succeeded, the timestamp for that completion
+	CompletionTimestamp metav1.Time `json:"completionTimestamp,omitempty" optional:"true"`
+
+	// CurrentState
+	CurrentState shared.ProvisioningState `json:"currentState"`
+
+	// CurrentReason
+	CurrentReason shared.ConditionReason `json:"currentReason"`
+
+	// StoppedVMs VMs which got stopped for the restore and get started again when it finished
+	StoppedVMs []string `json:"stoppedVMs,omitempty"`
+
+	// Restores VirtualMachineRestore per VM of the VMSet
+	Restores map[string]string `json:"restores,omitempty"`
+
+	Conditions shared.ConditionList `json:"conditions,omitempty" optional:"true"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:resource:shortName=osvmsetrestore;osvmsetrestores
+//+operator-sdk:csv:customresourcedefinitions:displayName="OpenStack VMSet Restore Request"
+//+kubebuilder:printcolumn:name="VMSet",type=string,JSONPath=`.spec.vmSet`,description="VMSet"
+//+kubebuilder:printcolumn:name="Snapshot Set",type=string,JSONPath=`.spec.snapshotSet`,description="Snapshot Set"
+//+kubebuilder:printcolumn:name="Status",type=string,JSONPath=`.status.currentState`,description="Status"
+//+kubebuilder:printcolumn:name="Completion Timestamp",type=string,JSONPath=`.status.completionTimestamp`,description="Completion Timestamp"
+
+// OpenStackVMSetRestoreRequest a request to revert the VMs of an OpenStackVMSet to a set of VirtualMachineSnapshots
+type OpenStackVMSetRestoreRequest struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   OpenStackVMSetRestoreRequestSpec   `json:"spec,omitempty"`
+	Status OpenStackVMSetRestoreRequestStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// OpenStackVMSetRestoreRequestList contains a list of OpenStackVMSetRestoreRequest
+type OpenStackVMSetRestoreRequestList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []OpenStackVMSetRestoreRequest `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&OpenStackVMSetRestoreRequest{}, &OpenStackVMSetRestoreRequestList{})
+}
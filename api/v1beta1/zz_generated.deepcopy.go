@@ -21,11 +21,77 @@ limitations under the License.
 package v1beta1
 
 import (
-	"github.com/k8snetworkplumbingwg/network-attachment-definition-client/pkg/apis/k8s.cni.cncf.io/v1"
+	k8s_cni_cncf_iov1 "github.com/k8snetworkplumbingwg/network-attachment-definition-client/pkg/apis/k8s.cni.cncf.io/v1"
+	"github.com/metal3-io/baremetal-operator/apis/metal3.io/v1alpha1"
 	"github.com/openstack-k8s-operators/osp-director-operator/api/shared"
+	"k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BaremetalSetScalePlan) DeepCopyInto(out *BaremetalSetScalePlan) {
+	*out = *in
+	if in.Hosts != nil {
+		in, out := &in.Hosts, &out.Hosts
+		*out = make([]BaremetalSetScalePlanHost, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BaremetalSetScalePlan.
+func (in *BaremetalSetScalePlan) DeepCopy() *BaremetalSetScalePlan {
+	if in == nil {
+		return nil
+	}
+	out := new(BaremetalSetScalePlan)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BaremetalSetScalePlanHost) DeepCopyInto(out *BaremetalSetScalePlanHost) {
+	*out = *in
+	if in.IPAddresses != nil {
+		in, out := &in.IPAddresses, &out.IPAddresses
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BaremetalSetScalePlanHost.
+func (in *BaremetalSetScalePlanHost) DeepCopy() *BaremetalSetScalePlanHost {
+	if in == nil {
+		return nil
+	}
+	out := new(BaremetalSetScalePlanHost)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BaremetalSetUpdateStrategy) DeepCopyInto(out *BaremetalSetUpdateStrategy) {
+	*out = *in
+	if in.RollingReprovision != nil {
+		in, out := &in.RollingReprovision, &out.RollingReprovision
+		*out = new(RollingReprovisionUpdateStrategy)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BaremetalSetUpdateStrategy.
+func (in *BaremetalSetUpdateStrategy) DeepCopy() *BaremetalSetUpdateStrategy {
+	if in == nil {
+		return nil
+	}
+	out := new(BaremetalSetUpdateStrategy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CPUCountReq) DeepCopyInto(out *CPUCountReq) {
 	*out = *in
@@ -97,6 +163,21 @@ func (in *CrsForBackup) DeepCopy() *CrsForBackup {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DNSRecordsConfig) DeepCopyInto(out *DNSRecordsConfig) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DNSRecordsConfig.
+func (in *DNSRecordsConfig) DeepCopy() *DNSRecordsConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(DNSRecordsConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DiskGbReq) DeepCopyInto(out *DiskGbReq) {
 	*out = *in
@@ -150,6 +231,7 @@ func (in *HardwareReqs) DeepCopyInto(out *HardwareReqs) {
 	out.CPUReqs = in.CPUReqs
 	out.MemReqs = in.MemReqs
 	out.DiskReqs = in.DiskReqs
+	out.NicReqs = in.NicReqs
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HardwareReqs.
@@ -204,7 +286,7 @@ func (in *Host) DeepCopyInto(out *Host) {
 	}
 	if in.NAD != nil {
 		in, out := &in.NAD, &out.NAD
-		*out = make(map[string]v1.NetworkAttachmentDefinition, len(*in))
+		*out = make(map[string]k8s_cni_cncf_iov1.NetworkAttachmentDefinition, len(*in))
 		for key, val := range *in {
 			(*out)[key] = *val.DeepCopy()
 		}
@@ -221,10 +303,48 @@ func (in *Host) DeepCopy() *Host {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HostAssignment) DeepCopyInto(out *HostAssignment) {
+	*out = *in
+	if in.BmhLabelSelector != nil {
+		in, out := &in.BmhLabelSelector, &out.BmhLabelSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostAssignment.
+func (in *HostAssignment) DeepCopy() *HostAssignment {
+	if in == nil {
+		return nil
+	}
+	out := new(HostAssignment)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *HostStatus) DeepCopyInto(out *HostStatus) {
 	*out = *in
 	in.IPStatus.DeepCopyInto(&out.IPStatus)
+	if in.ProvisioningStartTime != nil {
+		in, out := &in.ProvisioningStartTime, &out.ProvisioningStartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.FailedHostRefs != nil {
+		in, out := &in.FailedHostRefs, &out.FailedHostRefs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make(shared.ConditionList, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostStatus.
@@ -313,6 +433,11 @@ func (in *NetDetails) DeepCopyInto(out *NetDetails) {
 		*out = make([]Route, len(*in))
 		copy(*out, *in)
 	}
+	if in.ExcludeRanges != nil {
+		in, out := &in.ExcludeRanges, &out.ExcludeRanges
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetDetails.
@@ -347,6 +472,69 @@ func (in *Network) DeepCopy() *Network {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NicCountReq) DeepCopyInto(out *NicCountReq) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NicCountReq.
+func (in *NicCountReq) DeepCopy() *NicCountReq {
+	if in == nil {
+		return nil
+	}
+	out := new(NicCountReq)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NicPXEReq) DeepCopyInto(out *NicPXEReq) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NicPXEReq.
+func (in *NicPXEReq) DeepCopy() *NicPXEReq {
+	if in == nil {
+		return nil
+	}
+	out := new(NicPXEReq)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NicReqs) DeepCopyInto(out *NicReqs) {
+	*out = *in
+	out.CountReq = in.CountReq
+	out.SpeedReq = in.SpeedReq
+	out.PXEReq = in.PXEReq
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NicReqs.
+func (in *NicReqs) DeepCopy() *NicReqs {
+	if in == nil {
+		return nil
+	}
+	out := new(NicReqs)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NicSpeedReq) DeepCopyInto(out *NicSpeedReq) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NicSpeedReq.
+func (in *NicSpeedReq) DeepCopy() *NicSpeedReq {
+	if in == nil {
+		return nil
+	}
+	out := new(NicSpeedReq)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NodeConfigurationPolicy) DeepCopyInto(out *NodeConfigurationPolicy) {
 	*out = *in
@@ -698,6 +886,11 @@ func (in *OpenStackBaremetalSetProvisioningStatus) DeepCopy() *OpenStackBaremeta
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *OpenStackBaremetalSetSpec) DeepCopyInto(out *OpenStackBaremetalSetSpec) {
 	*out = *in
+	if in.CtlplaneNetworkConfig != nil {
+		in, out := &in.CtlplaneNetworkConfig, &out.CtlplaneNetworkConfig
+		*out = new(shared.CtlplaneNetworkConfig)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.BmhLabelSelector != nil {
 		in, out := &in.BmhLabelSelector, &out.BmhLabelSelector
 		*out = make(map[string]string, len(*in))
@@ -721,6 +914,60 @@ func (in *OpenStackBaremetalSetSpec) DeepCopyInto(out *OpenStackBaremetalSetSpec
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.CloudInitParts != nil {
+		in, out := &in.CloudInitParts, &out.CloudInitParts
+		*out = make([]shared.CloudInitPart, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.TopologySpread != nil {
+		in, out := &in.TopologySpread, &out.TopologySpread
+		*out = new(TopologySpread)
+		**out = **in
+	}
+	if in.RootDeviceHints != nil {
+		in, out := &in.RootDeviceHints, &out.RootDeviceHints
+		*out = new(v1alpha1.RootDeviceHints)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RAID != nil {
+		in, out := &in.RAID, &out.RAID
+		*out = new(v1alpha1.RAIDConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Firmware != nil {
+		in, out := &in.Firmware, &out.Firmware
+		*out = new(v1alpha1.FirmwareConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.UpdateStrategy != nil {
+		in, out := &in.UpdateStrategy, &out.UpdateStrategy
+		*out = new(BaremetalSetUpdateStrategy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ScaleDownPolicy != nil {
+		in, out := &in.ScaleDownPolicy, &out.ScaleDownPolicy
+		*out = new(shared.ScaleDownPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ProvisioningTimeout != nil {
+		in, out := &in.ProvisioningTimeout, &out.ProvisioningTimeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.HostAssignments != nil {
+		in, out := &in.HostAssignments, &out.HostAssignments
+		*out = make(map[string]HostAssignment, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+	if in.ScalePlanCount != nil {
+		in, out := &in.ScalePlanCount, &out.ScalePlanCount
+		*out = new(int)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenStackBaremetalSetSpec.
@@ -751,6 +998,18 @@ func (in *OpenStackBaremetalSetStatus) DeepCopyInto(out *OpenStackBaremetalSetSt
 			(*out)[key] = *val.DeepCopy()
 		}
 	}
+	if in.TopologyDomains != nil {
+		in, out := &in.TopologyDomains, &out.TopologyDomains
+		*out = make(map[string]int, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.ScalePlan != nil {
+		in, out := &in.ScalePlan, &out.ScalePlan
+		*out = new(BaremetalSetScalePlan)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenStackBaremetalSetStatus.
@@ -2041,6 +2300,7 @@ func (in *OpenStackNetConfigSpec) DeepCopyInto(out *OpenStackNetConfigSpec) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	out.DNSRecords = in.DNSRecords
 	if in.Networks != nil {
 		in, out := &in.Networks, &out.Networks
 		*out = make([]Network, len(*in))
@@ -2183,6 +2443,16 @@ func (in *OpenStackNetSpec) DeepCopyInto(out *OpenStackNetSpec) {
 		*out = make([]Route, len(*in))
 		copy(*out, *in)
 	}
+	if in.ExcludeRanges != nil {
+		in, out := &in.ExcludeRanges, &out.ExcludeRanges
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.IPv6 != nil {
+		in, out := &in.IPv6, &out.IPv6
+		*out = new(NetDetails)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.RoleReservations != nil {
 		in, out := &in.RoleReservations, &out.RoleReservations
 		*out = make(map[string]OpenStackNetRoleReservation, len(*in))
@@ -2461,6 +2731,115 @@ func (in *OpenStackVMSetProvisioningStatus) DeepCopy() *OpenStackVMSetProvisioni
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpenStackVMSetRestoreRequest) DeepCopyInto(out *OpenStackVMSetRestoreRequest) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenStackVMSetRestoreRequest.
+func (in *OpenStackVMSetRestoreRequest) DeepCopy() *OpenStackVMSetRestoreRequest {
+	if in == nil {
+		return nil
+	}
+	out := new(OpenStackVMSetRestoreRequest)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OpenStackVMSetRestoreRequest) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpenStackVMSetRestoreRequestList) DeepCopyInto(out *OpenStackVMSetRestoreRequestList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]OpenStackVMSetRestoreRequest, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenStackVMSetRestoreRequestList.
+func (in *OpenStackVMSetRestoreRequestList) DeepCopy() *OpenStackVMSetRestoreRequestList {
+	if in == nil {
+		return nil
+	}
+	out := new(OpenStackVMSetRestoreRequestList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OpenStackVMSetRestoreRequestList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpenStackVMSetRestoreRequestSpec) DeepCopyInto(out *OpenStackVMSetRestoreRequestSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenStackVMSetRestoreRequestSpec.
+func (in *OpenStackVMSetRestoreRequestSpec) DeepCopy() *OpenStackVMSetRestoreRequestSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(OpenStackVMSetRestoreRequestSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpenStackVMSetRestoreRequestStatus) DeepCopyInto(out *OpenStackVMSetRestoreRequestStatus) {
+	*out = *in
+	in.CompletionTimestamp.DeepCopyInto(&out.CompletionTimestamp)
+	if in.StoppedVMs != nil {
+		in, out := &in.StoppedVMs, &out.StoppedVMs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Restores != nil {
+		in, out := &in.Restores, &out.Restores
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make(shared.ConditionList, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenStackVMSetRestoreRequestStatus.
+func (in *OpenStackVMSetRestoreRequestStatus) DeepCopy() *OpenStackVMSetRestoreRequestStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(OpenStackVMSetRestoreRequestStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *OpenStackVMSetSpec) DeepCopyInto(out *OpenStackVMSetSpec) {
 	*out = *in
@@ -2561,6 +2940,21 @@ func (in *Physnet) DeepCopy() *Physnet {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RollingReprovisionUpdateStrategy) DeepCopyInto(out *RollingReprovisionUpdateStrategy) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RollingReprovisionUpdateStrategy.
+func (in *RollingReprovisionUpdateStrategy) DeepCopy() *RollingReprovisionUpdateStrategy {
+	if in == nil {
+		return nil
+	}
+	out := new(RollingReprovisionUpdateStrategy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Route) DeepCopyInto(out *Route) {
 	*out = *in
@@ -2608,6 +3002,21 @@ func (in *Subnet) DeepCopy() *Subnet {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TopologySpread) DeepCopyInto(out *TopologySpread) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TopologySpread.
+func (in *TopologySpread) DeepCopy() *TopologySpread {
+	if in == nil {
+		return nil
+	}
+	out := new(TopologySpread)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TripleoRoleOverrideSpec) DeepCopyInto(out *TripleoRoleOverrideSpec) {
 	*out = *in
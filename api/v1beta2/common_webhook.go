@@ -7,9 +7,14 @@ import (
 	"context"
 	"fmt"
 	"regexp"
+	"time"
 
 	ospdirectorv1beta1 "github.com/openstack-k8s-operators/osp-director-operator/api/v1beta1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	k8s_errors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	goClient "sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -69,12 +74,12 @@ func getRoleNames(namespace string) (map[string]string, error) {
 	return found, nil
 }
 
-// validateRootDisk - validate configured rootdisk
-func validateRootDisk(newDisk OpenStackVMSetDisk, currentDisk OpenStackVMSetDisk) error {
+// validateRootDisk - validate configured rootdisk, the BaseImageSource can change until the base image is ready
+func validateRootDisk(newDisk OpenStackVMSetDisk, currentDisk OpenStackVMSetDisk, baseImageReady bool) error {
 	//
-	// validate DiskSize don't change
+	// validate DiskSize don't shrink
 	//
-	if err := diskSizeChanged(newDisk.Name, newDisk.DiskSize, currentDisk.DiskSize); err != nil {
+	if err := diskSizeDecreased(newDisk.Name, newDisk.DiskSize, currentDisk.DiskSize); err != nil {
 		return err
 	}
 
@@ -106,6 +111,127 @@ func validateRootDisk(newDisk OpenStackVMSetDisk, currentDisk OpenStackVMSetDisk
 		return err
 	}
 
+	//
+	// validate BaseImageSource don't change once the base image is ready
+	//
+	if baseImageReady && !equality.Semantic.DeepEqual(newDisk.BaseImageSource, currentDisk.BaseImageSource) {
+		return fmt.Errorf("BaseImageSource must not change after the base image is ready %s - new %v / current %v", newDisk.Name, newDisk.BaseImageSource, currentDisk.BaseImageSource)
+	}
+
+	return nil
+}
+
+// validateBaseImageSource - validate the base image of the rootdisk is either a volume name or exactly one source
+func validateBaseImageSource(disk OpenStackVMSetDisk) error {
+	source := disk.BaseImageSource
+	if source == nil {
+		if disk.BaseImageVolumeName == "" {
+			return fmt.Errorf("either baseImageVolumeName or baseImageSource is required - %s", disk.Name)
+		}
+		return nil
+	}
+
+	if disk.BaseImageVolumeName != "" {
+		return fmt.Errorf("baseImageVolumeName and baseImageSource are mutually exclusive - %s", disk.Name)
+	}
+
+	count := 0
+	if source.HTTP != nil {
+		count++
+	}
+	if source.Registry != nil {
+		count++
+	}
+	if source.PVC != nil {
+		count++
+	}
+	if source.Snapshot != nil {
+		count++
+	}
+	if count != 1 {
+		return fmt.Errorf("baseImageSource requires exactly one of http, registry, pvc or snapshot - %s", disk.Name)
+	}
+
+	return nil
+}
+
+// isBaseImageReady - the base image DataVolume of the VMSet is ready
+func isBaseImageReady(status OpenStackVMSetStatus) bool {
+	return status.BaseImage != nil && status.BaseImage.Ready
+}
+
+// getVMSetBaseImageReady - the base image DataVolume of the VMSet of a controlplane role is ready
+func getVMSetBaseImageReady(namespace string, name string) (bool, error) {
+	vmSet := &OpenStackVMSet{}
+	err := webhookClient.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: namespace}, vmSet)
+	if err != nil {
+		if k8s_errors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return isBaseImageReady(vmSet.Status), nil
+}
+
+// validateFirmware - validate the firmware settings of the VMs
+func validateFirmware(firmware *VMFirmware) error {
+	if firmware == nil {
+		return nil
+	}
+
+	if firmware.SecureBoot && firmware.Bootloader != VMBootloaderUEFI {
+		return fmt.Errorf("firmware secureBoot requires bootloader %s", VMBootloaderUEFI)
+	}
+
+	return nil
+}
+
+// validateFencingCheck - validate the fencing health check settings of the VMs
+func validateFencingCheck(fencingCheck *VMFencingCheck) error {
+	if fencingCheck == nil {
+		return nil
+	}
+
+	if fencingCheck.Interval.Duration < time.Minute {
+		return fmt.Errorf("fencingCheck interval %s must be at least 1m", fencingCheck.Interval.Duration)
+	}
+
+	return nil
+}
+
+// validatePerformance - validate the performance settings against the cores and memory of the VMs
+func validatePerformance(performance *VMPerformance, cores uint32, memory uint32) error {
+	if performance == nil {
+		return nil
+	}
+
+	if performance.IsolateEmulatorThread && !performance.DedicatedCPUPlacement {
+		return fmt.Errorf("performance isolateEmulatorThread requires dedicatedCpuPlacement")
+	}
+
+	if performance.NUMAGuestMappingPassthrough && (!performance.DedicatedCPUPlacement || performance.HugepagesPageSize == "") {
+		return fmt.Errorf("performance numaGuestMappingPassthrough requires dedicatedCpuPlacement and hugepagesPageSize")
+	}
+
+	if performance.DedicatedCPUPlacement && cores == 0 {
+		return fmt.Errorf("performance dedicatedCpuPlacement requires cores to be set")
+	}
+
+	if performance.HugepagesPageSize != "" {
+		pageSize, err := resource.ParseQuantity(performance.HugepagesPageSize)
+		if err != nil {
+			return fmt.Errorf("performance hugepagesPageSize %s invalid: %w", performance.HugepagesPageSize, err)
+		}
+
+		memoryBytes := int64(memory) * 1024 * 1024 * 1024
+		if memoryBytes == 0 || memoryBytes%pageSize.Value() != 0 {
+			return fmt.Errorf("performance hugepagesPageSize %s requires memory %dGB to be a multiple of the page size",
+				performance.HugepagesPageSize,
+				memory)
+		}
+	}
+
 	return nil
 }
 
@@ -121,6 +247,13 @@ func validateAdditionalDisks(newDisks []OpenStackVMSetDisk, currentDisks []OpenS
 	}
 
 	for _, disk := range newDisks {
+		//
+		// validate the base image source is only used for the rootdisk
+		//
+		if disk.BaseImageSource != nil {
+			return fmt.Errorf("baseImageSource is only supported for the rootDisk - %s", disk.Name)
+		}
+
 		//
 		// validate Disk Name is not 'rootdisk' as this is reserved for the boot disk
 		//
@@ -150,9 +283,9 @@ func validateAdditionalDisks(newDisks []OpenStackVMSetDisk, currentDisks []OpenS
 		//
 		if curDisk, ok := curDisks[disk.Name]; ok {
 			//
-			// validate DiskSize don't change
+			// validate DiskSize don't shrink
 			//
-			if err := diskSizeChanged(disk.Name, disk.DiskSize, curDisk.DiskSize); err != nil {
+			if err := diskSizeDecreased(disk.Name, disk.DiskSize, curDisk.DiskSize); err != nil {
 				return err
 			}
 
@@ -183,13 +316,13 @@ func validateAdditionalDisks(newDisks []OpenStackVMSetDisk, currentDisks []OpenS
 	return nil
 }
 
-// diskSizeChanged -
-func diskSizeChanged(diskName string, diskSize uint32, curDiskSize uint32) error {
+// diskSizeDecreased -
+func diskSizeDecreased(diskName string, diskSize uint32, curDiskSize uint32) error {
 	//
-	// validate DiskSize don't change
+	// validate DiskSize don't shrink, PVCs can only be expanded
 	//
-	if diskSize != curDiskSize {
-		return fmt.Errorf("disk size must not change %s - new %v / current %v", diskName, diskSize, curDiskSize)
+	if diskSize < curDiskSize {
+		return fmt.Errorf("disk size must not shrink %s - new %v / current %v", diskName, diskSize, curDiskSize)
 	}
 
 	return nil
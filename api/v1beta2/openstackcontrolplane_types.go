@@ -114,6 +114,10 @@ type OpenStackVirtualMachineRoleSpec struct {
 	// Interface to use for ctlplane network
 	CtlplaneInterface string `json:"ctlplaneInterface"`
 
+	// +kubebuilder:validation:Optional
+	// CtlplaneNetworkConfig Optional. Bond and/or VLAN the ctlplane network uses instead of the plain CtlplaneInterface
+	CtlplaneNetworkConfig *shared.CtlplaneNetworkConfig `json:"ctlplaneNetworkConfig,omitempty"`
+
 	// +kubebuilder:default={ctlplane,external,internalapi,tenant,storage,storagemgmt}
 	// Networks the name(s) of the OpenStackNetworks used to generate IPs
 	Networks []string `json:"networks"`
@@ -139,6 +143,44 @@ type OpenStackVirtualMachineRoleSpec struct {
 	// EvictionStrategy defines if the VirtualMachineInstance should be
 	// migrated instead of shut-off.
 	EvictionStrategy *virtv1.EvictionStrategy `json:"evictionStrategy,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// ScaleDownPolicy defines which VMs get removed on scale-down if not enough VMs are annotated
+	// for deletion. If not set, scale-down requires the osp-director.openstack.org/delete-host annotation.
+	ScaleDownPolicy *shared.ScaleDownPolicy `json:"scaleDownPolicy,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// Placement defines how the VMs get placed on the worker nodes. If not set, two VMs of the set
+	// preferably do not run on the same worker node. Changes only apply to newly created VMs and to running VMs
+	// after their next manual restart, they are not rolled out using the updateStrategy.
+	Placement *VMPlacement `json:"placement,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// UpdateStrategy defines how running VMs pick up changes of Cores, Memory, Firmware, Performance and
+	// Watchdog. If not set, the VMs pick them up on their next manual restart.
+	UpdateStrategy *VMUpdateStrategy `json:"updateStrategy,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// Performance dedicated CPUs, hugepages and NUMA topology of the VMs. Changes get rolled out using the updateStrategy.
+	Performance *VMPerformance `json:"performance,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// Firmware bootloader, Secure Boot and vTPM of the VMs. Changes get rolled out using the updateStrategy.
+	Firmware *VMFirmware `json:"firmware,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// CloudInitParts site specific cloud-init user-data parts, e.g. NTP, packages, proxy settings
+	// or CA trust, added to the rendered cloud-config as multipart MIME. Only used on the first boot of a VM.
+	CloudInitParts []shared.CloudInitPart `json:"cloudInitParts,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// Watchdog adds an i6300esb watchdog device to the VMs. Changes get rolled out using the updateStrategy.
+	Watchdog *VMWatchdog `json:"watchdog,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// FencingCheck periodically verifies that the fencing credentials used by pacemaker can get the status of the VMs.
+	// The result is the FencingVerified condition in the fencingStatus.
+	FencingCheck *VMFencingCheck `json:"fencingCheck,omitempty"`
 }
 
 // OpenStackControlPlaneStatus defines the observed state of OpenStackControlPlane
@@ -149,6 +191,23 @@ type OpenStackControlPlaneStatus struct {
 
 	// OSPVersion the OpenStack version to render templates files
 	OSPVersion shared.OSPVersion `json:"ospVersion"`
+
+	// Health summary of the guest-level state of the VMs of all VMSets
+	Health OpenStackControlPlaneHealthStatus `json:"health,omitempty"`
+}
+
+// OpenStackControlPlaneHealthStatus represents the guest-level health of the VMs of all OpenStackVMSets
+type OpenStackControlPlaneHealthStatus struct {
+	// State Healthy if all VMs are healthy, Down if a VMSet has no healthy VM, otherwise Degraded
+	State VMHealthState `json:"state,omitempty"`
+	// HealthyCount number of healthy VMs
+	HealthyCount int `json:"healthyCount,omitempty"`
+	// DegradedCount number of degraded VMs
+	DegradedCount int `json:"degradedCount,omitempty"`
+	// DownCount number of VMs without a running VMI
+	DownCount int `json:"downCount,omitempty"`
+	// UnhealthyVMs hostnames of the degraded and down VMs
+	UnhealthyVMs []string `json:"unhealthyVMs,omitempty"`
 }
 
 // OpenStackControlPlaneProvisioningStatus represents the overall provisioning state of
@@ -177,6 +236,7 @@ func (instance *OpenStackControlPlane) IsReady() bool {
 // +kubebuilder:printcolumn:name="VMSets Desired",type="integer",JSONPath=".status.provisioningStatus.desiredCount",description="VMSets Desired"
 // +kubebuilder:printcolumn:name="VMSets Ready",type="integer",JSONPath=".status.provisioningStatus.readyCount",description="VMSets Ready"
 // +kubebuilder:printcolumn:name="Client Ready",type="boolean",JSONPath=".status.provisioningStatus.clientReady",description="Client Ready"
+// +kubebuilder:printcolumn:name="Health",type="string",JSONPath=".status.health.state",description="Health"
 // +kubebuilder:printcolumn:name="Status",type="string",JSONPath=".status.provisioningStatus.state",description="Status"
 // +kubebuilder:printcolumn:name="Reason",type="string",JSONPath=".status.provisioningStatus.reason",description="Reason"
 
@@ -257,6 +257,47 @@ func (r *OpenStackControlPlane) ValidateCreate() (admission.Warnings, error) {
 			return nil, err
 		}
 
+		//
+		// validate ctlplane network config
+		//
+		if err := vmspec.CtlplaneNetworkConfig.Validate(); err != nil {
+			return nil, err
+		}
+
+		//
+		// validate performance settings
+		//
+		if err := validatePerformance(vmspec.Performance, vmspec.Cores, vmspec.Memory); err != nil {
+			return nil, err
+		}
+
+		//
+		// validate firmware settings
+		//
+		if err := validateFirmware(vmspec.Firmware); err != nil {
+			return nil, err
+		}
+
+		//
+		// validate fencing health check settings
+		//
+		if err := validateFencingCheck(vmspec.FencingCheck); err != nil {
+			return nil, err
+		}
+
+		//
+		// validate cloud-init parts
+		//
+		if err := ospdirectorv1beta1.ValidateCloudInitParts(webhookClient, r.Namespace, vmspec.CloudInitParts); err != nil {
+			return nil, err
+		}
+
+		//
+		// validate base image source of the rootdisk
+		//
+		if err := validateBaseImageSource(vmspec.RootDisk); err != nil {
+			return nil, err
+		}
 	}
 
 	return nil, nil
@@ -304,7 +345,11 @@ func (r *OpenStackControlPlane) ValidateUpdate(old runtime.Object) (admission.Wa
 		// validate rootdisk if its the converted definition format
 		//
 		if _, ok := r.Annotations[shared.RootDiskConvertedAnnotation]; ok && oldVMSpec.RootDisk.DiskSize > 0 {
-			if err := validateRootDisk(vmspec.RootDisk, oldVMSpec.RootDisk); err != nil {
+			baseImageReady, err := getVMSetBaseImageReady(r.Namespace, strings.ToLower(vmspec.RoleName))
+			if err != nil {
+				return nil, err
+			}
+			if err := validateRootDisk(vmspec.RootDisk, oldVMSpec.RootDisk, baseImageReady); err != nil {
 				return nil, err
 			}
 		}
@@ -315,6 +360,41 @@ func (r *OpenStackControlPlane) ValidateUpdate(old runtime.Object) (admission.Wa
 		if err := validateAdditionalDisks(vmspec.AdditionalDisks, oldVMSpec.AdditionalDisks); err != nil {
 			return nil, err
 		}
+
+		//
+		// validate performance settings
+		//
+		if err := validatePerformance(vmspec.Performance, vmspec.Cores, vmspec.Memory); err != nil {
+			return nil, err
+		}
+
+		//
+		// validate firmware settings
+		//
+		if err := validateFirmware(vmspec.Firmware); err != nil {
+			return nil, err
+		}
+
+		//
+		// validate fencing health check settings
+		//
+		if err := validateFencingCheck(vmspec.FencingCheck); err != nil {
+			return nil, err
+		}
+
+		//
+		// validate cloud-init parts
+		//
+		if err := ospdirectorv1beta1.ValidateCloudInitParts(webhookClient, r.Namespace, vmspec.CloudInitParts); err != nil {
+			return nil, err
+		}
+
+		//
+		// validate base image source of the rootdisk
+		//
+		if err := validateBaseImageSource(vmspec.RootDisk); err != nil {
+			return nil, err
+		}
 	}
 
 	return nil, nil
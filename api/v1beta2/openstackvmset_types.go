@@ -19,6 +19,7 @@ package v1beta2
 import (
 	networkv1 "github.com/k8snetworkplumbingwg/network-attachment-definition-client/pkg/apis/k8s.cni.cncf.io/v1"
 	"github.com/openstack-k8s-operators/osp-director-operator/api/shared"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	virtv1 "kubevirt.io/api/core/v1"
 )
@@ -33,7 +34,9 @@ type OpenStackVMSetSpec struct {
 	Memory uint32 `json:"memory"`
 	// RootDisk specification of the VM
 	RootDisk OpenStackVMSetDisk `json:"rootDisk"`
-	// AdditionalDisks additional disks to add to the VM
+	// AdditionalDisks additional disks to add to the VM. Disks added to running VMs get hot plugged
+	// if possible, otherwise they get attached on the next restart. Removed disks get detached from the VMs,
+	// their PVCs are kept until the VMs get deleted.
 	AdditionalDisks []OpenStackVMSetDisk `json:"additionalDisks,omitempty"`
 	// +kubebuilder:validation:Optional
 	// +kubebuilder:validation:Enum=auto;shared
@@ -56,6 +59,10 @@ type OpenStackVMSetSpec struct {
 	// Interface to use for ctlplane network
 	CtlplaneInterface string `json:"ctlplaneInterface"`
 
+	// +kubebuilder:validation:Optional
+	// CtlplaneNetworkConfig Optional. Bond and/or VLAN the ctlplane network uses instead of the plain CtlplaneInterface
+	CtlplaneNetworkConfig *shared.CtlplaneNetworkConfig `json:"ctlplaneNetworkConfig,omitempty"`
+
 	// +kubebuilder:default={ctlplane,external,internalapi,tenant,storage,storagemgmt}
 	// Networks the name(s) of the OpenStackNetworks used to generate IPs
 	Networks []string `json:"networks"`
@@ -89,6 +96,215 @@ type OpenStackVMSetSpec struct {
 	// EvictionStrategy defines if the VirtualMachineInstance should be
 	// migrated instead of shut-off.
 	EvictionStrategy *virtv1.EvictionStrategy `json:"evictionStrategy,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// ScaleDownPolicy defines which VMs get removed on scale-down if not enough VMs are annotated
+	// for deletion. If not set, scale-down requires the osp-director.openstack.org/delete-host annotation.
+	ScaleDownPolicy *shared.ScaleDownPolicy `json:"scaleDownPolicy,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// Placement defines how the VMs get placed on the worker nodes. If not set, two VMs of the set
+	// preferably do not run on the same worker node. Changes only apply to newly created VMs and to running VMs
+	// after their next manual restart, they are not rolled out using the updateStrategy.
+	Placement *VMPlacement `json:"placement,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// UpdateStrategy defines how running VMs pick up changes of Cores, Memory, Firmware, Performance and
+	// Watchdog. If not set, the VMs pick them up on their next manual restart.
+	UpdateStrategy *VMUpdateStrategy `json:"updateStrategy,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// Performance dedicated CPUs, hugepages and NUMA topology of the VMs. Changes get rolled out using the updateStrategy.
+	Performance *VMPerformance `json:"performance,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// Firmware bootloader, Secure Boot and vTPM of the VMs. Changes get rolled out using the updateStrategy.
+	Firmware *VMFirmware `json:"firmware,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// CloudInitParts site specific cloud-init user-data parts, e.g. NTP, packages, proxy settings
+	// or CA trust, added to the rendered cloud-config as multipart MIME. Only used on the first boot of a VM.
+	CloudInitParts []shared.CloudInitPart `json:"cloudInitParts,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// Watchdog adds an i6300esb watchdog device to the VMs. Changes get rolled out using the updateStrategy.
+	Watchdog *VMWatchdog `json:"watchdog,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// FencingCheck periodically verifies that the fencing credentials used by pacemaker can get the status of the VMs.
+	// The result is the FencingVerified condition in the fencingStatus.
+	FencingCheck *VMFencingCheck `json:"fencingCheck,omitempty"`
+}
+
+// VMAntiAffinityType is used to enumerate the anti-affinity modes between the VMs of a set
+type VMAntiAffinityType string
+
+const (
+	// VMAntiAffinityPreferred - VMs of the set preferably do not run in the same topology domain
+	VMAntiAffinityPreferred VMAntiAffinityType = "Preferred"
+	// VMAntiAffinityRequired - VMs of the set never run in the same topology domain
+	VMAntiAffinityRequired VMAntiAffinityType = "Required"
+	// VMAntiAffinityNone - no anti-affinity between the VMs of the set
+	VMAntiAffinityNone VMAntiAffinityType = "None"
+)
+
+// VMPlacement defines how the VMs of a set get placed on the worker nodes
+type VMPlacement struct {
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=Preferred;Required;None
+	// +kubebuilder:default=Preferred
+	// AntiAffinity between the VMs of the set
+	AntiAffinity VMAntiAffinityType `json:"antiAffinity,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default="kubernetes.io/hostname"
+	// AntiAffinityTopologyKey node label key of the topology domains the anti-affinity applies to
+	AntiAffinityTopologyKey string `json:"antiAffinityTopologyKey,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// TopologySpreadConstraints spread the VMs of the set across topology domains, e.g. zones or racks
+	TopologySpreadConstraints []VMTopologySpreadConstraint `json:"topologySpreadConstraints,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// Tolerations of the VMs, e.g. to run them on tainted worker nodes
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+}
+
+// VMTopologySpreadConstraint defines how the VMs of a set get spread across the domains of a topology
+type VMTopologySpreadConstraint struct {
+	// +kubebuilder:validation:MinLength=1
+	// TopologyKey node label key of the topology domains, e.g. topology.kubernetes.io/zone
+	TopologyKey string `json:"topologyKey"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:default=1
+	// MaxSkew maximum permitted difference between the number of VMs in any two topology domains
+	MaxSkew int32 `json:"maxSkew,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=DoNotSchedule;ScheduleAnyway
+	// +kubebuilder:default=DoNotSchedule
+	// WhenUnsatisfiable defines if a VM which can not satisfy the constraint gets scheduled anyway
+	WhenUnsatisfiable corev1.UnsatisfiableConstraintAction `json:"whenUnsatisfiable,omitempty"`
+}
+
+// VMUpdateStrategyType is used to enumerate how running VMs pick up changes of Cores, Memory, Firmware, Performance and Watchdog
+type VMUpdateStrategyType string
+
+const (
+	// VMUpdateStrategyManual - the VMs pick up the changes on their next manual restart
+	VMUpdateStrategyManual VMUpdateStrategyType = "Manual"
+	// VMUpdateStrategyRestart - the VMs get restarted one at a time
+	VMUpdateStrategyRestart VMUpdateStrategyType = "Restart"
+	// VMUpdateStrategyLiveMigrate - the VMs get live migrated one at a time. VMs which can not
+	// be live migrated, or which still run with the old settings after the migration, get restarted.
+	VMUpdateStrategyLiveMigrate VMUpdateStrategyType = "LiveMigrate"
+)
+
+// VMUpdateStrategy defines how running VMs pick up changes of Cores, Memory, Firmware, Performance and Watchdog
+type VMUpdateStrategy struct {
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=Manual;Restart;LiveMigrate
+	// +kubebuilder:default=Manual
+	// Type of the update. Restart and LiveMigrate update one VM at a time and wait for
+	// its VirtualMachineInstance to be running with the guest agent connected before moving on.
+	Type VMUpdateStrategyType `json:"type,omitempty"`
+}
+
+// VMBootloaderType is used to enumerate the bootloaders of the VMs
+type VMBootloaderType string
+
+const (
+	// VMBootloaderBIOS - the VMs boot with BIOS
+	VMBootloaderBIOS VMBootloaderType = "bios"
+	// VMBootloaderUEFI - the VMs boot with UEFI
+	VMBootloaderUEFI VMBootloaderType = "uefi"
+)
+
+// VMFirmware defines the firmware of the VMs of a set
+type VMFirmware struct {
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=bios
+	// +kubebuilder:validation:Enum=bios;uefi
+	// Bootloader of the VMs. The base image must support booting with the bootloader.
+	Bootloader VMBootloaderType `json:"bootloader,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// SecureBoot enables UEFI Secure Boot and the required System Management Mode. Requires bootloader uefi.
+	SecureBoot bool `json:"secureBoot,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// TPM adds a virtual TPM device to the VMs
+	TPM *VMTPM `json:"tpm,omitempty"`
+}
+
+// VMTPM defines the virtual TPM device of the VMs of a set
+type VMTPM struct {
+	// +kubebuilder:validation:Optional
+	// Persistent keeps the state of the vTPM across restarts and migrations of the VMs.
+	// Requires a VMStateStorageClass configured in the KubeVirt CR.
+	Persistent bool `json:"persistent,omitempty"`
+}
+
+// VMWatchdogAction is used to enumerate the actions of the watchdog if the guest stops servicing it
+type VMWatchdogAction string
+
+const (
+	// VMWatchdogActionReset - reset the VM
+	VMWatchdogActionReset VMWatchdogAction = "reset"
+	// VMWatchdogActionPoweroff - power off the VM
+	VMWatchdogActionPoweroff VMWatchdogAction = "poweroff"
+	// VMWatchdogActionShutdown - gracefully shut down the VM
+	VMWatchdogActionShutdown VMWatchdogAction = "shutdown"
+)
+
+// VMWatchdog defines the i6300esb watchdog device of the VMs of a set
+type VMWatchdog struct {
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=reset
+	// +kubebuilder:validation:Enum=reset;poweroff;shutdown
+	// Action taken if the guest stops servicing the watchdog, e.g. after a kernel hang.
+	// The watchdog service has to be enabled inside the guest.
+	Action VMWatchdogAction `json:"action,omitempty"`
+}
+
+// VMFencingCheck defines the fencing health check of the VMs of a set
+type VMFencingCheck struct {
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default="1h"
+	// Interval between two fencing health checks. The check also runs when the VMs of the set change.
+	Interval metav1.Duration `json:"interval,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// InsecureSkipTLSVerify skips the verification of the API server certificate, like the fence_kubevirt agent
+	// of pacemaker does. By default the certificate gets verified with the CA bundle of the cluster.
+	InsecureSkipTLSVerify bool `json:"insecureSkipTLSVerify,omitempty"`
+}
+
+// VMPerformance defines the dedicated resources and NUMA topology of the VMs of a set
+type VMPerformance struct {
+	// +kubebuilder:validation:Optional
+	// DedicatedCPUPlacement pins each vCPU of the VMs to a dedicated physical CPU of the worker node.
+	// Requires the CPU manager to be enabled on the worker nodes.
+	DedicatedCPUPlacement bool `json:"dedicatedCpuPlacement,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// IsolateEmulatorThread runs the QEMU emulator thread on an additional dedicated physical CPU.
+	// Requires dedicatedCpuPlacement.
+	IsolateEmulatorThread bool `json:"isolateEmulatorThread,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum={"2Mi","1Gi"}
+	// HugepagesPageSize backs the memory of the VMs with hugepages of this size.
+	// The worker nodes must provide enough hugepages of the size for the Memory of the VMs.
+	HugepagesPageSize string `json:"hugepagesPageSize,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// NUMAGuestMappingPassthrough passes the NUMA topology of the dedicated CPUs and hugepages
+	// of the worker node to the guest. Requires dedicatedCpuPlacement and hugepagesPageSize.
+	// VMs with NUMA passthrough can not be live migrated.
+	NUMAGuestMappingPassthrough bool `json:"numaGuestMappingPassthrough,omitempty"`
 }
 
 // OpenStackVMSetDisk defines additional disk properties
@@ -96,7 +312,8 @@ type OpenStackVMSetDisk struct {
 	// Name of the disk, e.g. used to do the PVC request.
 	// Must consist of lower case alphanumeric characters or '-', and must start and end with an alphanumeric character
 	Name string `json:"name"`
-	// Disc size in GB
+	// Disc size in GB. The disk size can be increased, which expands the PVCs of the disks
+	// if the storage class allows volume expansion.
 	DiskSize uint32 `json:"diskSize"`
 	// StorageClass to be used for the disk
 	StorageClass string `json:"storageClass,omitempty"`
@@ -119,8 +336,90 @@ type OpenStackVMSetDisk struct {
 	// DedicatedIOThread - Disks with dedicatedIOThread set to true will be allocated an exclusive thread.
 	// This is generally useful if a specific Disk is expected to have heavy I/O traffic, e.g. a database spindle.
 	DedicatedIOThread bool `json:"dedicatedIOThread"`
+	// +kubebuilder:validation:Optional
 	// BaseImageVolumeName used as the base volume for the rootdisk of the VM
-	BaseImageVolumeName string `json:"baseImageVolumeName"`
+	BaseImageVolumeName string `json:"baseImageVolumeName,omitempty"`
+	// +kubebuilder:validation:Optional
+	// BaseImageSource the base volume for the rootdisk of the VM gets created from.
+	// Alternative to a pre-built BaseImageVolumeName, exactly one source must be set.
+	// Can be changed until the base image is ready.
+	BaseImageSource *BaseImageSource `json:"baseImageSource,omitempty"`
+}
+
+// BaseImageSource defines the source the base image DataVolume of the VMs gets imported or cloned from
+type BaseImageSource struct {
+	// +kubebuilder:validation:Optional
+	// HTTP import the base image from an http(s) URL
+	HTTP *BaseImageSourceHTTP `json:"http,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// Registry import the base image from a container disk image in a registry
+	Registry *BaseImageSourceRegistry `json:"registry,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// PVC clone the base image from an existing PVC
+	PVC *BaseImageSourcePVC `json:"pvc,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// Snapshot clone the base image from an existing VolumeSnapshot
+	Snapshot *BaseImageSourceSnapshot `json:"snapshot,omitempty"`
+}
+
+// BaseImageSourceHTTP defines an http(s) URL the base image gets imported from
+type BaseImageSourceHTTP struct {
+	// URL of the qcow2 or raw image, optionally gz or xz compressed
+	URL string `json:"url"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Pattern=`^(md5|sha1|sha256|sha512):[0-9a-fA-F]+$`
+	// Checksum of the image at the URL, e.g. sha256:<hex digest>. If set, the content at the URL gets verified before the import.
+	Checksum string `json:"checksum,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// ChecksumImageURL image of the job verifying the checksum, defaults to the image downloader image
+	ChecksumImageURL string `json:"checksumImageURL,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// SecretRef secret with accessKeyId and secretKey to authenticate at the URL
+	SecretRef string `json:"secretRef,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// CertConfigMap config map with the CA bundle to verify the certificate of the URL
+	CertConfigMap string `json:"certConfigMap,omitempty"`
+}
+
+// BaseImageSourceRegistry defines a container disk image in a registry the base image gets imported from
+type BaseImageSourceRegistry struct {
+	// URL of the container disk image, e.g. docker://registry.example.com/rhel:9
+	URL string `json:"url"`
+
+	// +kubebuilder:validation:Optional
+	// SecretRef secret with accessKeyId and secretKey to authenticate at the registry
+	SecretRef string `json:"secretRef,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// CertConfigMap config map with the CA bundle to verify the certificate of the registry
+	CertConfigMap string `json:"certConfigMap,omitempty"`
+}
+
+// BaseImageSourcePVC defines an existing PVC the base image gets cloned from
+type BaseImageSourcePVC struct {
+	// Name of the PVC
+	Name string `json:"name"`
+
+	// +kubebuilder:validation:Optional
+	// Namespace of the PVC, defaults to the namespace of the VMSet
+	Namespace string `json:"namespace,omitempty"`
+}
+
+// BaseImageSourceSnapshot defines an existing VolumeSnapshot the base image gets cloned from
+type BaseImageSourceSnapshot struct {
+	// Name of the VolumeSnapshot
+	Name string `json:"name"`
+
+	// +kubebuilder:validation:Optional
+	// Namespace of the VolumeSnapshot, defaults to the namespace of the VMSet
+	Namespace string `json:"namespace,omitempty"`
 }
 
 // OpenStackVMSetStatus defines the observed state of OpenStackVMSet
@@ -132,6 +431,154 @@ type OpenStackVMSetStatus struct {
 	// VMpods are the names of the kubevirt controller vm pods
 	VMpods  []string              `json:"vmpods,omitempty"`
 	VMHosts map[string]HostStatus `json:"vmHosts,omitempty"`
+	// PlacementWarnings lists where the running VMs break the placement of the set,
+	// e.g. after an eviction when the anti-affinity is only preferred
+	PlacementWarnings []string `json:"placementWarnings,omitempty"`
+	// UpdateStatus progress of the running VMs picking up changes of Cores, Memory, Firmware, Performance and Watchdog
+	UpdateStatus OpenStackVMSetUpdateStatus `json:"updateStatus,omitempty"`
+	// BaseImage state of the base image DataVolume created from the BaseImageSource of the RootDisk
+	BaseImage *OpenStackVMSetBaseImageStatus `json:"baseImage,omitempty"`
+	// DiskStatus size and expansion state of the disks of the VMs, per hostname
+	DiskStatus map[string][]OpenStackVMSetDiskStatus `json:"diskStatus,omitempty"`
+	// VMStatus guest-level state of the VMs, per hostname
+	VMStatus map[string]OpenStackVMSetVMStatus `json:"vmStatus,omitempty"`
+	// Health summary of the guest-level state of the VMs
+	Health OpenStackVMSetHealthStatus `json:"health,omitempty"`
+	// FencingStatus result of the fencing health check of the VMs
+	FencingStatus *OpenStackVMSetFencingStatus `json:"fencingStatus,omitempty"`
+}
+
+// OpenStackVMSetFencingStatus represents the result of the fencing health check of the VMs
+type OpenStackVMSetFencingStatus struct {
+	// Conditions holds the FencingVerified condition. It is kept apart from the
+	// provisioning conditions, which only have a single current condition.
+	Conditions shared.ConditionList `json:"conditions,omitempty" optional:"true"`
+	// LastCheckTime time the last fencing health check finished
+	LastCheckTime *metav1.Time `json:"lastCheckTime,omitempty"`
+	// CheckedVMs hostnames of the VMs checked by the last fencing health check
+	CheckedVMs []string `json:"checkedVMs,omitempty"`
+	// FailedVMs hostnames of the VMs the fencing credentials could not get the status of
+	FailedVMs []string `json:"failedVMs,omitempty"`
+}
+
+// OpenStackVMSetBaseImageStatus represents the state of the base image DataVolume of a VMSet
+type OpenStackVMSetBaseImageStatus struct {
+	// DataVolume name of the base image
+	DataVolume string `json:"dataVolume"`
+	// SourceHash hash of the BaseImageSource the DataVolume got created from
+	SourceHash string `json:"sourceHash,omitempty"`
+	// ChecksumVerified the content at the http URL matched the checksum at the time of the check,
+	// before the import. The imported DataVolume is not verified.
+	ChecksumVerified bool `json:"checksumVerified,omitempty"`
+	// Phase of the DataVolume import or clone
+	Phase string `json:"phase,omitempty"`
+	// Progress of the DataVolume import or clone
+	Progress string `json:"progress,omitempty"`
+	// Failures number of times the DataVolume import or clone failed and the DataVolume got recreated
+	Failures int32 `json:"failures,omitempty"`
+	// LastFailureTime time the operator noticed the current failure of the DataVolume
+	LastFailureTime *metav1.Time `json:"lastFailureTime,omitempty"`
+	// Ready the base image can be used for the root disks of the VMs
+	Ready bool `json:"ready"`
+}
+
+// DiskExpansionState is used to enumerate the expansion states of a VM disk
+type DiskExpansionState string
+
+const (
+	// DiskExpansionStateNone - the disk has the requested size
+	DiskExpansionStateNone DiskExpansionState = "None"
+	// DiskExpansionStateExpanding - the PVC of the disk gets expanded by the storage provider
+	DiskExpansionStateExpanding DiskExpansionState = "Expanding"
+	// DiskExpansionStateFileSystemResizePending - the volume got expanded, the file system resize is pending
+	DiskExpansionStateFileSystemResizePending DiskExpansionState = "FileSystemResizePending"
+	// DiskExpansionStateNotSupported - the storage class of the PVC does not allow volume expansion
+	DiskExpansionStateNotSupported DiskExpansionState = "NotSupported"
+)
+
+// OpenStackVMSetDiskStatus represents the size and expansion state of a VM disk
+type OpenStackVMSetDiskStatus struct {
+	// Name of the disk, rootdisk for the root disk
+	Name string `json:"name"`
+	// PersistentVolumeClaim of the disk
+	PersistentVolumeClaim string `json:"persistentVolumeClaim"`
+	// RequestedSize of the disk
+	RequestedSize string `json:"requestedSize"`
+	// Capacity of the PVC of the disk
+	Capacity string `json:"capacity,omitempty"`
+	// ExpansionState of the disk
+	ExpansionState DiskExpansionState `json:"expansionState"`
+	// Hotplugged disk got added to the running VM
+	Hotplugged bool `json:"hotplugged,omitempty"`
+}
+
+// OpenStackVMSetUpdateStatus represents the progress of the running VMs picking up changes of Cores, Memory, Firmware, Performance and Watchdog
+type OpenStackVMSetUpdateStatus struct {
+	// UpdatedVMs hostnames of the VMs running with the requested Cores and Memory
+	UpdatedVMs []string `json:"updatedVMs,omitempty"`
+	// PendingVMs hostnames of the VMs which still need a restart or live migration
+	PendingVMs []string `json:"pendingVMs,omitempty"`
+	// CurrentVM hostname of the VM which gets updated right now
+	CurrentVM string `json:"currentVM,omitempty"`
+	// CurrentAction is the Restart or LiveMigrate triggered for the CurrentVM
+	CurrentAction VMUpdateStrategyType `json:"currentAction,omitempty"`
+	// CurrentActionRef UID of the VirtualMachineInstance (Restart) or of its last
+	// migration (LiveMigrate) when the CurrentAction got triggered
+	CurrentActionRef string `json:"currentActionRef,omitempty"`
+}
+
+// VMHealthState - the guest-level health of a VM
+type VMHealthState string
+
+const (
+	// VMHealthHealthy - the VMI is running and ready with the guest agent connected
+	VMHealthHealthy VMHealthState = "Healthy"
+	// VMHealthDegraded - the VMI is running, but is not ready or the guest agent is not connected
+	VMHealthDegraded VMHealthState = "Degraded"
+	// VMHealthDown - there is no running VMI for the VM
+	VMHealthDown VMHealthState = "Down"
+)
+
+// OpenStackVMSetVMStatus represents the guest-level state of a VM of the set
+type OpenStackVMSetVMStatus struct {
+	// Health of the VM derived from the VMI phase, readiness and guest agent connection
+	Health VMHealthState `json:"health"`
+	// Phase of the VirtualMachineInstance
+	Phase string `json:"phase,omitempty"`
+	// Ready condition of the VirtualMachineInstance
+	Ready bool `json:"ready,omitempty"`
+	// AgentConnected is the guest agent of the VM connected
+	AgentConnected bool `json:"agentConnected,omitempty"`
+	// NodeName worker node the VM runs on
+	NodeName string `json:"nodeName,omitempty"`
+	// MigrationState of the last live migration of the VM: InProgress, Succeeded or Failed
+	MigrationState string `json:"migrationState,omitempty"`
+	// MigrationTargetNode worker node of the last live migration of the VM
+	MigrationTargetNode string `json:"migrationTargetNode,omitempty"`
+	// Interfaces and IP addresses reported by the guest agent
+	Interfaces []OpenStackVMSetVMInterfaceStatus `json:"interfaces,omitempty"`
+}
+
+// OpenStackVMSetVMInterfaceStatus represents an interface of a VM as reported by the guest
+type OpenStackVMSetVMInterfaceStatus struct {
+	// Name of the interface in the VM spec
+	Name string `json:"name,omitempty"`
+	// InterfaceName of the interface inside the guest
+	InterfaceName string `json:"interfaceName,omitempty"`
+	// MAC address of the interface
+	MAC string `json:"mac,omitempty"`
+	// IPAddresses of the interface
+	IPAddresses []string `json:"ipAddresses,omitempty"`
+}
+
+// OpenStackVMSetHealthStatus represents the guest-level health of all VMs in the OpenStackVMSet
+type OpenStackVMSetHealthStatus struct {
+	// HealthyCount number of healthy VMs
+	HealthyCount int `json:"healthyCount,omitempty"`
+	// DegradedCount number of degraded VMs
+	DegradedCount int `json:"degradedCount,omitempty"`
+	// DownCount number of VMs without a running VMI
+	DownCount int `json:"downCount,omitempty"`
 }
 
 // OpenStackVMSetProvisioningStatus represents the overall provisioning state of all VMs in
@@ -172,6 +619,7 @@ func (instance *OpenStackVMSet) IsReady() bool {
 // +kubebuilder:printcolumn:name="RootDisk",type="integer",JSONPath=".spec.rootDisk.diskSize",description="Root Disk Size"
 // +kubebuilder:printcolumn:name="Desired",type="integer",JSONPath=".spec.vmCount",description="Desired"
 // +kubebuilder:printcolumn:name="Ready",type="integer",JSONPath=".status.provisioningStatus.readyCount",description="Ready"
+// +kubebuilder:printcolumn:name="Healthy",type="integer",JSONPath=".status.health.healthyCount",description="Healthy"
 // +kubebuilder:printcolumn:name="Status",type="string",JSONPath=".status.provisioningStatus.state",description="Status"
 // +kubebuilder:printcolumn:name="Reason",type="string",JSONPath=".status.provisioningStatus.reason",description="Reason"
 
@@ -35,11 +35,21 @@ import (
 	ospdirectorv1beta1 "github.com/openstack-k8s-operators/osp-director-operator/api/v1beta1"
 )
 
+// OpenStackVMSetDefaults -
+type OpenStackVMSetDefaults struct {
+	ChecksumImageURL string
+}
+
+var openstackVMSetDefaults OpenStackVMSetDefaults
+
 // log is for logging in this package.
 var vmsetlog = logf.Log.WithName("vmset-resource")
 
 // SetupWebhookWithManager - register this webhook with the controller manager
-func (r *OpenStackVMSet) SetupWebhookWithManager(mgr ctrl.Manager) error {
+func (r *OpenStackVMSet) SetupWebhookWithManager(mgr ctrl.Manager, defaults OpenStackVMSetDefaults) error {
+
+	openstackVMSetDefaults = defaults
+
 	if webhookClient == nil {
 		webhookClient = mgr.GetClient()
 	}
@@ -92,6 +102,14 @@ func (r *OpenStackVMSet) Default() {
 		vmsetlog.Info(fmt.Sprintf("%s %s labels set to %v", r.GetObjectKind().GroupVersionKind().Kind, r.Name, r.GetLabels()))
 	}
 
+	//
+	// set the image of the job verifying the checksum of the base image
+	//
+	if source := r.Spec.RootDisk.BaseImageSource; source != nil && source.HTTP != nil &&
+		source.HTTP.Checksum != "" && source.HTTP.ChecksumImageURL == "" {
+		source.HTTP.ChecksumImageURL = openstackVMSetDefaults.ChecksumImageURL
+	}
+
 	//
 	// set spec.domainName , dnsSearchDomains and bootstrapDNS from osnetcfg if not specified
 	//
@@ -179,7 +197,7 @@ func (r *OpenStackVMSet) ValidateUpdate(old runtime.Object) (admission.Warnings,
 	// validate rootdisk
 	//
 	if oldInstance.Spec.RootDisk.DiskSize > 0 {
-		if err := validateRootDisk(r.Spec.RootDisk, oldInstance.Spec.RootDisk); err != nil {
+		if err := validateRootDisk(r.Spec.RootDisk, oldInstance.Spec.RootDisk, isBaseImageReady(oldInstance.Status)); err != nil {
 			return nil, err
 		}
 	}
@@ -206,5 +224,29 @@ func (r *OpenStackVMSet) validateCr() error {
 		return err
 	}
 
+	if err := r.Spec.CtlplaneNetworkConfig.Validate(); err != nil {
+		return err
+	}
+
+	if err := validatePerformance(r.Spec.Performance, r.Spec.Cores, r.Spec.Memory); err != nil {
+		return err
+	}
+
+	if err := validateFirmware(r.Spec.Firmware); err != nil {
+		return err
+	}
+
+	if err := validateFencingCheck(r.Spec.FencingCheck); err != nil {
+		return err
+	}
+
+	if err := ospdirectorv1beta1.ValidateCloudInitParts(webhookClient, r.Namespace, r.Spec.CloudInitParts); err != nil {
+		return err
+	}
+
+	if err := validateBaseImageSource(r.Spec.RootDisk); err != nil {
+		return err
+	}
+
 	return nil
 }
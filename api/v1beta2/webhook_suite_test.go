@@ -103,7 +103,7 @@ var _ = BeforeSuite(func() {
 	})
 	Expect(err).NotTo(HaveOccurred())
 
-	//	err = (&OpenStackVMSet{}).SetupWebhookWithManager(mgr)
+	//	err = (&OpenStackVMSet{}).SetupWebhookWithManager(mgr, OpenStackVMSetDefaults{})
 	//	Expect(err).NotTo(HaveOccurred())
 
 	//+kubebuilder:scaffold:webhook
@@ -23,10 +23,106 @@ package v1beta2
 import (
 	k8s_cni_cncf_iov1 "github.com/k8snetworkplumbingwg/network-attachment-definition-client/pkg/apis/k8s.cni.cncf.io/v1"
 	"github.com/openstack-k8s-operators/osp-director-operator/api/shared"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"kubevirt.io/api/core/v1"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BaseImageSource) DeepCopyInto(out *BaseImageSource) {
+	*out = *in
+	if in.HTTP != nil {
+		in, out := &in.HTTP, &out.HTTP
+		*out = new(BaseImageSourceHTTP)
+		**out = **in
+	}
+	if in.Registry != nil {
+		in, out := &in.Registry, &out.Registry
+		*out = new(BaseImageSourceRegistry)
+		**out = **in
+	}
+	if in.PVC != nil {
+		in, out := &in.PVC, &out.PVC
+		*out = new(BaseImageSourcePVC)
+		**out = **in
+	}
+	if in.Snapshot != nil {
+		in, out := &in.Snapshot, &out.Snapshot
+		*out = new(BaseImageSourceSnapshot)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BaseImageSource.
+func (in *BaseImageSource) DeepCopy() *BaseImageSource {
+	if in == nil {
+		return nil
+	}
+	out := new(BaseImageSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BaseImageSourceHTTP) DeepCopyInto(out *BaseImageSourceHTTP) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BaseImageSourceHTTP.
+func (in *BaseImageSourceHTTP) DeepCopy() *BaseImageSourceHTTP {
+	if in == nil {
+		return nil
+	}
+	out := new(BaseImageSourceHTTP)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BaseImageSourcePVC) DeepCopyInto(out *BaseImageSourcePVC) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BaseImageSourcePVC.
+func (in *BaseImageSourcePVC) DeepCopy() *BaseImageSourcePVC {
+	if in == nil {
+		return nil
+	}
+	out := new(BaseImageSourcePVC)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BaseImageSourceRegistry) DeepCopyInto(out *BaseImageSourceRegistry) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BaseImageSourceRegistry.
+func (in *BaseImageSourceRegistry) DeepCopy() *BaseImageSourceRegistry {
+	if in == nil {
+		return nil
+	}
+	out := new(BaseImageSourceRegistry)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BaseImageSourceSnapshot) DeepCopyInto(out *BaseImageSourceSnapshot) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BaseImageSourceSnapshot.
+func (in *BaseImageSourceSnapshot) DeepCopy() *BaseImageSourceSnapshot {
+	if in == nil {
+		return nil
+	}
+	out := new(BaseImageSourceSnapshot)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CrsForBackup) DeepCopyInto(out *CrsForBackup) {
 	*out = *in
@@ -252,6 +348,26 @@ func (in *OpenStackControlPlane) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpenStackControlPlaneHealthStatus) DeepCopyInto(out *OpenStackControlPlaneHealthStatus) {
+	*out = *in
+	if in.UnhealthyVMs != nil {
+		in, out := &in.UnhealthyVMs, &out.UnhealthyVMs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenStackControlPlaneHealthStatus.
+func (in *OpenStackControlPlaneHealthStatus) DeepCopy() *OpenStackControlPlaneHealthStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(OpenStackControlPlaneHealthStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *OpenStackControlPlaneList) DeepCopyInto(out *OpenStackControlPlaneList) {
 	*out = *in
@@ -351,6 +467,7 @@ func (in *OpenStackControlPlaneStatus) DeepCopyInto(out *OpenStackControlPlaneSt
 		}
 	}
 	out.ProvisioningStatus = in.ProvisioningStatus
+	in.Health.DeepCopyInto(&out.Health)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenStackControlPlaneStatus.
@@ -390,9 +507,48 @@ func (in *OpenStackVMSet) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpenStackVMSetBaseImageStatus) DeepCopyInto(out *OpenStackVMSetBaseImageStatus) {
+	*out = *in
+	if in.LastFailureTime != nil {
+		in, out := &in.LastFailureTime, &out.LastFailureTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenStackVMSetBaseImageStatus.
+func (in *OpenStackVMSetBaseImageStatus) DeepCopy() *OpenStackVMSetBaseImageStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(OpenStackVMSetBaseImageStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpenStackVMSetDefaults) DeepCopyInto(out *OpenStackVMSetDefaults) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenStackVMSetDefaults.
+func (in *OpenStackVMSetDefaults) DeepCopy() *OpenStackVMSetDefaults {
+	if in == nil {
+		return nil
+	}
+	out := new(OpenStackVMSetDefaults)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *OpenStackVMSetDisk) DeepCopyInto(out *OpenStackVMSetDisk) {
 	*out = *in
+	if in.BaseImageSource != nil {
+		in, out := &in.BaseImageSource, &out.BaseImageSource
+		*out = new(BaseImageSource)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenStackVMSetDisk.
@@ -405,6 +561,72 @@ func (in *OpenStackVMSetDisk) DeepCopy() *OpenStackVMSetDisk {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpenStackVMSetDiskStatus) DeepCopyInto(out *OpenStackVMSetDiskStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenStackVMSetDiskStatus.
+func (in *OpenStackVMSetDiskStatus) DeepCopy() *OpenStackVMSetDiskStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(OpenStackVMSetDiskStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpenStackVMSetFencingStatus) DeepCopyInto(out *OpenStackVMSetFencingStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make(shared.ConditionList, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.LastCheckTime != nil {
+		in, out := &in.LastCheckTime, &out.LastCheckTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CheckedVMs != nil {
+		in, out := &in.CheckedVMs, &out.CheckedVMs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.FailedVMs != nil {
+		in, out := &in.FailedVMs, &out.FailedVMs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenStackVMSetFencingStatus.
+func (in *OpenStackVMSetFencingStatus) DeepCopy() *OpenStackVMSetFencingStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(OpenStackVMSetFencingStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpenStackVMSetHealthStatus) DeepCopyInto(out *OpenStackVMSetHealthStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenStackVMSetHealthStatus.
+func (in *OpenStackVMSetHealthStatus) DeepCopy() *OpenStackVMSetHealthStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(OpenStackVMSetHealthStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *OpenStackVMSetList) DeepCopyInto(out *OpenStackVMSetList) {
 	*out = *in
@@ -455,11 +677,18 @@ func (in *OpenStackVMSetProvisioningStatus) DeepCopy() *OpenStackVMSetProvisioni
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *OpenStackVMSetSpec) DeepCopyInto(out *OpenStackVMSetSpec) {
 	*out = *in
-	out.RootDisk = in.RootDisk
+	in.RootDisk.DeepCopyInto(&out.RootDisk)
 	if in.AdditionalDisks != nil {
 		in, out := &in.AdditionalDisks, &out.AdditionalDisks
 		*out = make([]OpenStackVMSetDisk, len(*in))
-		copy(*out, *in)
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.CtlplaneNetworkConfig != nil {
+		in, out := &in.CtlplaneNetworkConfig, &out.CtlplaneNetworkConfig
+		*out = new(shared.CtlplaneNetworkConfig)
+		(*in).DeepCopyInto(*out)
 	}
 	if in.Networks != nil {
 		in, out := &in.Networks, &out.Networks
@@ -488,6 +717,48 @@ func (in *OpenStackVMSetSpec) DeepCopyInto(out *OpenStackVMSetSpec) {
 		*out = new(v1.EvictionStrategy)
 		**out = **in
 	}
+	if in.ScaleDownPolicy != nil {
+		in, out := &in.ScaleDownPolicy, &out.ScaleDownPolicy
+		*out = new(shared.ScaleDownPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Placement != nil {
+		in, out := &in.Placement, &out.Placement
+		*out = new(VMPlacement)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.UpdateStrategy != nil {
+		in, out := &in.UpdateStrategy, &out.UpdateStrategy
+		*out = new(VMUpdateStrategy)
+		**out = **in
+	}
+	if in.Performance != nil {
+		in, out := &in.Performance, &out.Performance
+		*out = new(VMPerformance)
+		**out = **in
+	}
+	if in.Firmware != nil {
+		in, out := &in.Firmware, &out.Firmware
+		*out = new(VMFirmware)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CloudInitParts != nil {
+		in, out := &in.CloudInitParts, &out.CloudInitParts
+		*out = make([]shared.CloudInitPart, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Watchdog != nil {
+		in, out := &in.Watchdog, &out.Watchdog
+		*out = new(VMWatchdog)
+		**out = **in
+	}
+	if in.FencingCheck != nil {
+		in, out := &in.FencingCheck, &out.FencingCheck
+		*out = new(VMFencingCheck)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenStackVMSetSpec.
@@ -523,6 +794,46 @@ func (in *OpenStackVMSetStatus) DeepCopyInto(out *OpenStackVMSetStatus) {
 			(*out)[key] = *val.DeepCopy()
 		}
 	}
+	if in.PlacementWarnings != nil {
+		in, out := &in.PlacementWarnings, &out.PlacementWarnings
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	in.UpdateStatus.DeepCopyInto(&out.UpdateStatus)
+	if in.BaseImage != nil {
+		in, out := &in.BaseImage, &out.BaseImage
+		*out = new(OpenStackVMSetBaseImageStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DiskStatus != nil {
+		in, out := &in.DiskStatus, &out.DiskStatus
+		*out = make(map[string][]OpenStackVMSetDiskStatus, len(*in))
+		for key, val := range *in {
+			var outVal []OpenStackVMSetDiskStatus
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				inVal := (*in)[key]
+				in, out := &inVal, &outVal
+				*out = make([]OpenStackVMSetDiskStatus, len(*in))
+				copy(*out, *in)
+			}
+			(*out)[key] = outVal
+		}
+	}
+	if in.VMStatus != nil {
+		in, out := &in.VMStatus, &out.VMStatus
+		*out = make(map[string]OpenStackVMSetVMStatus, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+	out.Health = in.Health
+	if in.FencingStatus != nil {
+		in, out := &in.FencingStatus, &out.FencingStatus
+		*out = new(OpenStackVMSetFencingStatus)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenStackVMSetStatus.
@@ -535,14 +846,88 @@ func (in *OpenStackVMSetStatus) DeepCopy() *OpenStackVMSetStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpenStackVMSetUpdateStatus) DeepCopyInto(out *OpenStackVMSetUpdateStatus) {
+	*out = *in
+	if in.UpdatedVMs != nil {
+		in, out := &in.UpdatedVMs, &out.UpdatedVMs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.PendingVMs != nil {
+		in, out := &in.PendingVMs, &out.PendingVMs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenStackVMSetUpdateStatus.
+func (in *OpenStackVMSetUpdateStatus) DeepCopy() *OpenStackVMSetUpdateStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(OpenStackVMSetUpdateStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpenStackVMSetVMInterfaceStatus) DeepCopyInto(out *OpenStackVMSetVMInterfaceStatus) {
+	*out = *in
+	if in.IPAddresses != nil {
+		in, out := &in.IPAddresses, &out.IPAddresses
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenStackVMSetVMInterfaceStatus.
+func (in *OpenStackVMSetVMInterfaceStatus) DeepCopy() *OpenStackVMSetVMInterfaceStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(OpenStackVMSetVMInterfaceStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpenStackVMSetVMStatus) DeepCopyInto(out *OpenStackVMSetVMStatus) {
+	*out = *in
+	if in.Interfaces != nil {
+		in, out := &in.Interfaces, &out.Interfaces
+		*out = make([]OpenStackVMSetVMInterfaceStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenStackVMSetVMStatus.
+func (in *OpenStackVMSetVMStatus) DeepCopy() *OpenStackVMSetVMStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(OpenStackVMSetVMStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *OpenStackVirtualMachineRoleSpec) DeepCopyInto(out *OpenStackVirtualMachineRoleSpec) {
 	*out = *in
-	out.RootDisk = in.RootDisk
+	in.RootDisk.DeepCopyInto(&out.RootDisk)
 	if in.AdditionalDisks != nil {
 		in, out := &in.AdditionalDisks, &out.AdditionalDisks
 		*out = make([]OpenStackVMSetDisk, len(*in))
-		copy(*out, *in)
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.CtlplaneNetworkConfig != nil {
+		in, out := &in.CtlplaneNetworkConfig, &out.CtlplaneNetworkConfig
+		*out = new(shared.CtlplaneNetworkConfig)
+		(*in).DeepCopyInto(*out)
 	}
 	if in.Networks != nil {
 		in, out := &in.Networks, &out.Networks
@@ -561,6 +946,48 @@ func (in *OpenStackVirtualMachineRoleSpec) DeepCopyInto(out *OpenStackVirtualMac
 		*out = new(v1.EvictionStrategy)
 		**out = **in
 	}
+	if in.ScaleDownPolicy != nil {
+		in, out := &in.ScaleDownPolicy, &out.ScaleDownPolicy
+		*out = new(shared.ScaleDownPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Placement != nil {
+		in, out := &in.Placement, &out.Placement
+		*out = new(VMPlacement)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.UpdateStrategy != nil {
+		in, out := &in.UpdateStrategy, &out.UpdateStrategy
+		*out = new(VMUpdateStrategy)
+		**out = **in
+	}
+	if in.Performance != nil {
+		in, out := &in.Performance, &out.Performance
+		*out = new(VMPerformance)
+		**out = **in
+	}
+	if in.Firmware != nil {
+		in, out := &in.Firmware, &out.Firmware
+		*out = new(VMFirmware)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CloudInitParts != nil {
+		in, out := &in.CloudInitParts, &out.CloudInitParts
+		*out = make([]shared.CloudInitPart, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Watchdog != nil {
+		in, out := &in.Watchdog, &out.Watchdog
+		*out = new(VMWatchdog)
+		**out = **in
+	}
+	if in.FencingCheck != nil {
+		in, out := &in.FencingCheck, &out.FencingCheck
+		*out = new(VMFencingCheck)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenStackVirtualMachineRoleSpec.
@@ -572,3 +999,141 @@ func (in *OpenStackVirtualMachineRoleSpec) DeepCopy() *OpenStackVirtualMachineRo
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VMFencingCheck) DeepCopyInto(out *VMFencingCheck) {
+	*out = *in
+	out.Interval = in.Interval
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VMFencingCheck.
+func (in *VMFencingCheck) DeepCopy() *VMFencingCheck {
+	if in == nil {
+		return nil
+	}
+	out := new(VMFencingCheck)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VMFirmware) DeepCopyInto(out *VMFirmware) {
+	*out = *in
+	if in.TPM != nil {
+		in, out := &in.TPM, &out.TPM
+		*out = new(VMTPM)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VMFirmware.
+func (in *VMFirmware) DeepCopy() *VMFirmware {
+	if in == nil {
+		return nil
+	}
+	out := new(VMFirmware)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VMPerformance) DeepCopyInto(out *VMPerformance) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VMPerformance.
+func (in *VMPerformance) DeepCopy() *VMPerformance {
+	if in == nil {
+		return nil
+	}
+	out := new(VMPerformance)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VMPlacement) DeepCopyInto(out *VMPlacement) {
+	*out = *in
+	if in.TopologySpreadConstraints != nil {
+		in, out := &in.TopologySpreadConstraints, &out.TopologySpreadConstraints
+		*out = make([]VMTopologySpreadConstraint, len(*in))
+		copy(*out, *in)
+	}
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]corev1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VMPlacement.
+func (in *VMPlacement) DeepCopy() *VMPlacement {
+	if in == nil {
+		return nil
+	}
+	out := new(VMPlacement)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VMTPM) DeepCopyInto(out *VMTPM) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VMTPM.
+func (in *VMTPM) DeepCopy() *VMTPM {
+	if in == nil {
+		return nil
+	}
+	out := new(VMTPM)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VMTopologySpreadConstraint) DeepCopyInto(out *VMTopologySpreadConstraint) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VMTopologySpreadConstraint.
+func (in *VMTopologySpreadConstraint) DeepCopy() *VMTopologySpreadConstraint {
+	if in == nil {
+		return nil
+	}
+	out := new(VMTopologySpreadConstraint)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VMUpdateStrategy) DeepCopyInto(out *VMUpdateStrategy) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VMUpdateStrategy.
+func (in *VMUpdateStrategy) DeepCopy() *VMUpdateStrategy {
+	if in == nil {
+		return nil
+	}
+	out := new(VMUpdateStrategy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VMWatchdog) DeepCopyInto(out *VMWatchdog) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VMWatchdog.
+func (in *VMWatchdog) DeepCopy() *VMWatchdog {
+	if in == nil {
+		return nil
+	}
+	out := new(VMWatchdog)
+	in.DeepCopyInto(out)
+	return out
+}
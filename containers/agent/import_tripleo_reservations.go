@@ -0,0 +1,174 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/golang/glog"
+	ospdirectorv1beta1 "github.com/openstack-k8s-operators/osp-director-operator/api/v1beta1"
+	"github.com/openstack-k8s-operators/osp-director-operator/pkg/openstacknetconfig"
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/yaml"
+)
+
+var (
+	importTripleoReservationsCmd = &cobra.Command{
+		Use:   "import-tripleo-reservations",
+		Short: "Import the IP and MAC reservations of an existing TripleO deployment",
+		Long: `Reads the network_data.yaml and environment files, like ips-from-pool-all.yaml or overcloud-baremetal-deployed.yaml,
+of an existing TripleO deployment and prints the static reservations of its hosts as a merge patch for an OpenStackNetConfig.`,
+		Run: runImportTripleoReservationsCmd,
+	}
+
+	importTripleoReservationsOpts struct {
+		networkData  string
+		environments []string
+		stackName    string
+		namespace    string
+		netConfig    string
+		output       string
+	}
+
+	openstackNetConfigGVR = schema.GroupVersionResource{
+		Group:    "osp-director.openstack.org",
+		Version:  "v1beta1",
+		Resource: "openstacknetconfigs",
+	}
+)
+
+func init() {
+	rootCmd.AddCommand(importTripleoReservationsCmd)
+	importTripleoReservationsCmd.PersistentFlags().StringVar(&importTripleoReservationsOpts.networkData, "networkData", "", "TripleO network_data.yaml of the deployment.")
+	importTripleoReservationsCmd.PersistentFlags().StringArrayVar(&importTripleoReservationsOpts.environments, "environment", []string{}, "TripleO environment file with the IPs of the hosts, e.g. ips-from-pool-all.yaml or overcloud-baremetal-deployed.yaml. Can be specified multiple times, later files override earlier ones.")
+	importTripleoReservationsCmd.PersistentFlags().StringVar(&importTripleoReservationsOpts.stackName, "stackName", openstacknetconfig.TripleoDefaultStackName, "Name of the overcloud stack used in the HostnameFormat of the roles.")
+	importTripleoReservationsCmd.PersistentFlags().StringVar(&importTripleoReservationsOpts.namespace, "namespace", "openstack", "Namespace of the OpenStackNetConfig.")
+	importTripleoReservationsCmd.PersistentFlags().StringVar(&importTripleoReservationsOpts.netConfig, "netConfig", "", "OpenStackNetConfig to check for conflicts with its reservations and assigned addresses.")
+	importTripleoReservationsCmd.PersistentFlags().StringVar(&importTripleoReservationsOpts.output, "output", "", "File to write the merge patch to, default stdout.")
+}
+
+func runImportTripleoReservationsCmd(_ *cobra.Command, _ []string) {
+	err := flag.Set("logtostderr", "true")
+	if err != nil {
+		panic(err.Error())
+	}
+
+	flag.Parse()
+
+	if importTripleoReservationsOpts.networkData == "" {
+		glog.Fatalf("networkData is required")
+	}
+	if len(importTripleoReservationsOpts.environments) == 0 {
+		glog.Fatalf("at least one environment is required")
+	}
+
+	networkData, err := os.ReadFile(importTripleoReservationsOpts.networkData)
+	if err != nil {
+		glog.Fatalf("failed to read networkData: %v", err)
+	}
+
+	environments := [][]byte{}
+	for _, environment := range importTripleoReservationsOpts.environments {
+		data, err := os.ReadFile(environment)
+		if err != nil {
+			glog.Fatalf("failed to read environment: %v", err)
+		}
+		environments = append(environments, data)
+	}
+
+	tripleoImport, err := openstacknetconfig.ImportTripleoReservations(
+		importTripleoReservationsOpts.stackName,
+		networkData,
+		environments...,
+	)
+	if err != nil {
+		glog.Fatalf("failed to import reservations: %v", err)
+	}
+
+	conflicts := tripleoImport.Conflicts
+	if importTripleoReservationsOpts.netConfig != "" {
+		netConfig, err := getOpenStackNetConfig(importTripleoReservationsOpts.namespace, importTripleoReservationsOpts.netConfig)
+		if err != nil {
+			glog.Fatalf("failed to get OpenStackNetConfig %s: %v", importTripleoReservationsOpts.netConfig, err)
+		}
+		conflicts = append(conflicts, tripleoImport.CheckConflicts(netConfig)...)
+	}
+
+	if len(conflicts) > 0 {
+		for _, conflict := range conflicts {
+			glog.Errorf("conflict: %s", conflict)
+		}
+		glog.Exitf("found %d conflicts, no reservations imported", len(conflicts))
+	}
+
+	patch, err := yaml.Marshal(map[string]interface{}{
+		"spec": map[string]interface{}{
+			"reservations": tripleoImport.Reservations,
+		},
+	})
+	if err != nil {
+		glog.Fatalf("failed to marshal reservations: %v", err)
+	}
+
+	// document which TripleO host got which hostname
+	hostnames := []string{}
+	for hostname := range tripleoImport.Hostnames {
+		hostnames = append(hostnames, hostname)
+	}
+	sort.Strings(hostnames)
+	header := "# imported hosts, hostname: TripleO hostname\n"
+	for _, hostname := range hostnames {
+		header += fmt.Sprintf("#   %s: %s\n", hostname, tripleoImport.Hostnames[hostname])
+	}
+	patch = append([]byte(header), patch...)
+
+	if importTripleoReservationsOpts.output == "" {
+		fmt.Print(string(patch))
+		return
+	}
+
+	err = os.WriteFile(importTripleoReservationsOpts.output, patch, 0644)
+	if err != nil {
+		glog.Fatalf("failed to write output: %v", err)
+	}
+	glog.V(0).Infof("Imported reservations of %d hosts to %s", len(tripleoImport.Reservations), importTripleoReservationsOpts.output)
+}
+
+// getOpenStackNetConfig - get the OpenStackNetConfig from the cluster
+func getOpenStackNetConfig(namespace string, name string) (*ospdirectorv1beta1.OpenStackNetConfig, error) {
+	var config *rest.Config
+	var err error
+	kubeconfig := os.Getenv("KUBECONFIG")
+	if kubeconfig != "" {
+		config, err = clientcmd.BuildConfigFromFlags("", kubeconfig)
+	} else {
+		// creates the in-cluster config
+		config, err = rest.InClusterConfig()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	dClient := dynamic.NewForConfigOrDie(config)
+
+	unstructured, err := dClient.Resource(openstackNetConfigGVR).Namespace(namespace).Get(context.Background(), name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	netConfig := &ospdirectorv1beta1.OpenStackNetConfig{}
+	err = runtime.DefaultUnstructuredConverter.FromUnstructured(unstructured.UnstructuredContent(), netConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	return netConfig, nil
+}
@@ -21,6 +21,7 @@ import (
 	"fmt"
 	"net"
 	"reflect"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
@@ -47,6 +48,7 @@ import (
 	"github.com/openstack-k8s-operators/osp-director-operator/pkg/baremetalset"
 	"github.com/openstack-k8s-operators/osp-director-operator/pkg/common"
 	openstackipset "github.com/openstack-k8s-operators/osp-director-operator/pkg/openstackipset"
+	openstacknet "github.com/openstack-k8s-operators/osp-director-operator/pkg/openstacknet"
 	"github.com/openstack-k8s-operators/osp-director-operator/pkg/provisionserver"
 )
 
@@ -401,6 +403,12 @@ func (r *OpenStackBaremetalSetReconciler) Reconcile(ctx context.Context, req ctr
 
 	for _, status := range ipsetStatus {
 		hostStatus := ospdirectorv1beta1.SyncIPsetStatus(cond, instance.Status.BaremetalHosts, status)
+
+		// the IPSet might not yet be aware that the BaremetalHost got replaced
+		if slices.Contains(hostStatus.FailedHostRefs, hostStatus.HostRef) {
+			hostStatus.HostRef = instance.Status.BaremetalHosts[status.Hostname].HostRef
+		}
+
 		instance.Status.BaremetalHosts[status.Hostname] = hostStatus
 	}
 
@@ -422,6 +430,13 @@ func (r *OpenStackBaremetalSetReconciler) Reconcile(ctx context.Context, req ctr
 		return ctrl.Result{}, err
 	}
 
+	//
+	//   Preview a scale-up if requested
+	//
+	if err := r.planBaremetalHosts(ctx, instance, cond, osNetCfg); err != nil {
+		return ctrl.Result{}, err
+	}
+
 	//
 	//   Provision requested replicas
 	//
@@ -444,7 +459,8 @@ func (r *OpenStackBaremetalSetReconciler) Reconcile(ctx context.Context, req ctr
 	for _, bmh := range instance.Status.BaremetalHosts {
 		if strings.EqualFold(string(bmh.ProvisioningState), string(shared.BaremetalSetCondTypeProvisioned)) {
 			readyCount++
-		} else if strings.EqualFold(string(bmh.ProvisioningState), string(shared.BaremetalSetCondTypeError)) {
+		} else if strings.EqualFold(string(bmh.ProvisioningState), string(shared.BaremetalSetCondTypeError)) ||
+			bmh.Conditions.Find(shared.BaremetalHostCondTypeFailed) != nil {
 			bmhErrors++
 		}
 	}
@@ -480,6 +496,13 @@ func (r *OpenStackBaremetalSetReconciler) Reconcile(ctx context.Context, req ctr
 		}
 	}
 
+	// Check the provisioning timeout of BaremetalHosts which are still provisioning
+	if instance.Spec.MaxReplacementAttempts > 0 &&
+		instance.Spec.ProvisioningTimeout != nil &&
+		readyCount < len(instance.Status.BaremetalHosts) {
+		return ctrl.Result{RequeueAfter: time.Duration(60) * time.Second}, nil
+	}
+
 	return ctrl.Result{}, nil
 }
 
@@ -494,6 +517,13 @@ func (r *OpenStackBaremetalSetReconciler) getNormalizedStatus(status *ospdirecto
 		s.Conditions[idx].LastHeartbeatTime = metav1.Time{}
 		s.Conditions[idx].LastTransitionTime = metav1.Time{}
 	}
+	for hostname, bmhStatus := range s.BaremetalHosts {
+		for idx := range bmhStatus.Conditions {
+			bmhStatus.Conditions[idx].LastHeartbeatTime = metav1.Time{}
+			bmhStatus.Conditions[idx].LastTransitionTime = metav1.Time{}
+		}
+		s.BaremetalHosts[hostname] = bmhStatus
+	}
 
 	return s
 }
@@ -686,6 +716,48 @@ func (r *OpenStackBaremetalSetReconciler) doBMHDelete(
 	// How many new BaremetalHost de-allocations do we need (if any)?
 	bmhsToRemoveCount := len(baremetalHostsList.Items) - instance.Spec.Count
 
+	// With a scale-down policy, BaremetalHosts which are not annotated for removal get
+	// selected from the policy hostnames list first and then by the policy type.
+	// If a topology spread is used, it takes precedence over the policy type.
+	if instance.Spec.ScaleDownPolicy != nil && bmhsToRemoveCount > len(removalAnnotatedBaremetalHosts) {
+		candidates := r.getScaleDownCandidates(instance, baremetalHostsList)
+		if instance.Spec.TopologySpread != nil {
+			removalAnnotatedBaremetalHosts = append(
+				removalAnnotatedBaremetalHosts,
+				common.GetScaleDownHostsByName(
+					instance.Spec.ScaleDownPolicy,
+					candidates,
+					removalAnnotatedBaremetalHosts,
+					bmhsToRemoveCount-len(removalAnnotatedBaremetalHosts),
+				)...,
+			)
+		} else {
+			removalAnnotatedBaremetalHosts = append(
+				removalAnnotatedBaremetalHosts,
+				common.GetScaleDownHosts(
+					instance.Spec.ScaleDownPolicy,
+					candidates,
+					removalAnnotatedBaremetalHosts,
+					bmhsToRemoveCount-len(removalAnnotatedBaremetalHosts),
+				)...,
+			)
+		}
+	}
+
+	// With a topology spread, BaremetalHosts which are not annotated for removal get
+	// selected from the most crowded failure domain
+	if instance.Spec.TopologySpread != nil && bmhsToRemoveCount > len(removalAnnotatedBaremetalHosts) {
+		removalAnnotatedBaremetalHosts = append(
+			removalAnnotatedBaremetalHosts,
+			ospdirectorv1beta1.GetTopologySpreadScaleDownBmhs(
+				instance,
+				baremetalHostsList,
+				removalAnnotatedBaremetalHosts,
+				bmhsToRemoveCount-len(removalAnnotatedBaremetalHosts),
+			)...,
+		)
+	}
+
 	if bmhsToRemoveCount > 0 {
 		bmhsRemovedCount := 0
 
@@ -734,6 +806,34 @@ func (r *OpenStackBaremetalSetReconciler) doBMHDelete(
 	return deletedHosts, nil
 }
 
+// Get the BaremetalHosts of the set as candidates for the scale-down policy
+func (r *OpenStackBaremetalSetReconciler) getScaleDownCandidates(
+	instance *ospdirectorv1beta1.OpenStackBaremetalSet,
+	baremetalHostsList *metal3v1.BareMetalHostList,
+) []common.ScaleDownCandidate {
+	hostnames := map[string]string{}
+	for hostname, bmhStatus := range instance.Status.BaremetalHosts {
+		hostnames[bmhStatus.HostRef] = hostname
+	}
+
+	candidates := []common.ScaleDownCandidate{}
+	for _, bmh := range baremetalHostsList.Items {
+		provisioned := bmh.CreationTimestamp
+		if !bmh.Status.OperationHistory.Provision.Start.IsZero() {
+			provisioned = bmh.Status.OperationHistory.Provision.Start
+		}
+
+		candidates = append(candidates, common.ScaleDownCandidate{
+			Name:        bmh.Name,
+			Hostname:    hostnames[bmh.Name],
+			Provisioned: provisioned,
+			Ready:       bmh.Status.Provisioning.State == metal3v1.StateProvisioned,
+		})
+	}
+
+	return candidates
+}
+
 // Provision BaremetalHost resources based on replica count
 func (r *OpenStackBaremetalSetReconciler) ensureBaremetalHosts(
 	ctx context.Context,
@@ -766,8 +866,23 @@ func (r *OpenStackBaremetalSetReconciler) ensureBaremetalHosts(
 		return err
 	}
 
+	// Release BaremetalHosts which failed to provision, their hostnames get assigned to new ones
+	releasedBaremetalHosts, err := r.replaceFailedBaremetalHosts(ctx, instance, cond, existingBaremetalHosts)
+	if err != nil {
+		return err
+	}
+	if len(releasedBaremetalHosts) > 0 {
+		remainingBaremetalHosts := []metal3v1.BareMetalHost{}
+		for _, bmh := range existingBaremetalHosts.Items {
+			if !slices.Contains(releasedBaremetalHosts, bmh.Name) {
+				remainingBaremetalHosts = append(remainingBaremetalHosts, bmh)
+			}
+		}
+		existingBaremetalHosts.Items = remainingBaremetalHosts
+	}
+
 	// Verify that we have enough hosts with the right hardware reqs available for scaling-up
-	availableBaremetalHosts, err := ospdirectorv1beta1.VerifyBaremetalSetScaleUp(r.GetLogger(), instance, baremetalHostsList, existingBaremetalHosts)
+	availableBaremetalHosts, pinnedHostnames, err := ospdirectorv1beta1.VerifyBaremetalSetScaleUp(r.GetLogger(), instance, baremetalHostsList, existingBaremetalHosts)
 
 	if err != nil {
 		cond.Message = err.Error()
@@ -787,14 +902,13 @@ func (r *OpenStackBaremetalSetReconciler) ensureBaremetalHosts(
 	newBmhsNeededCount := instance.Spec.Count - len(existingBaremetalHosts.Items)
 
 	// Sort the list of available BaremetalHosts
-	sort.Slice(availableBaremetalHosts[:], func(i, j int) bool {
-		return availableBaremetalHosts[i].Name < availableBaremetalHosts[j].Name
-	})
+	sortScaleUpBaremetalHosts(availableBaremetalHosts, pinnedHostnames)
 
 	// For each available BaremetalHost that we need to allocate, we update the
 	// reference to use our image and set the user data to use our cloud-init secret.
 	// Then we add the status to store the BMH name, cloud-init secret name, management
 	// IP and BMH power status for the particular worker
+	allocatedBaremetalHosts := append([]metal3v1.BareMetalHost{}, existingBaremetalHosts.Items...)
 	for i := 0; i < len(availableBaremetalHosts) && i < newBmhsNeededCount; i++ {
 		err := r.baremetalHostProvision(
 			ctx,
@@ -802,7 +916,9 @@ func (r *OpenStackBaremetalSetReconciler) ensureBaremetalHosts(
 			cond,
 			osNetCfg,
 			&availableBaremetalHosts[i],
+			pinnedHostnames[availableBaremetalHosts[i].Name],
 			provisionServer.Status.LocalImageURL,
+			false,
 			sshSecret,
 			passwordSecret,
 		)
@@ -810,17 +926,45 @@ func (r *OpenStackBaremetalSetReconciler) ensureBaremetalHosts(
 		if err != nil {
 			return err
 		}
+
+		allocatedBaremetalHosts = append(allocatedBaremetalHosts, availableBaremetalHosts[i])
+	}
+
+	// Update the BaremetalHost count per failure domain
+	if instance.Spec.TopologySpread != nil {
+		instance.Status.TopologyDomains = ospdirectorv1beta1.GetTopologyDomainCounts(instance, allocatedBaremetalHosts)
+	} else {
+		instance.Status.TopologyDomains = nil
 	}
 
+	// With the RollingReprovision update strategy, get the next batch of outdated
+	// BaremetalHosts to reprovision with the current image
+	reprovisionBaremetalHosts := ospdirectorv1beta1.GetRollingReprovisionBmhs(
+		instance,
+		existingBaremetalHosts,
+		provisionServer.Status.LocalImageURL,
+	)
+
 	// Now reconcile existing BaremetalHosts for this OpenStackBaremetalSet
 	for _, bmh := range existingBaremetalHosts.Items {
+		reprovision := slices.Contains(reprovisionBaremetalHosts, bmh.Name)
+		if reprovision {
+			common.LogForObject(
+				r,
+				fmt.Sprintf("Reprovisioning BaremetalHost %s with image %s", bmh.Name, provisionServer.Status.LocalImageURL),
+				instance,
+			)
+		}
+
 		err := r.baremetalHostProvision(
 			ctx,
 			instance,
 			cond,
 			osNetCfg,
 			&bmh,
+			"",
 			provisionServer.Status.LocalImageURL,
+			reprovision,
 			sshSecret,
 			passwordSecret,
 		)
@@ -833,6 +977,221 @@ func (r *OpenStackBaremetalSetReconciler) ensureBaremetalHosts(
 	return nil
 }
 
+// sortScaleUpBaremetalHosts - sort the BaremetalHosts for a scale-up by name, the ones pinned to a hostname first
+func sortScaleUpBaremetalHosts(bmhs []metal3v1.BareMetalHost, pinnedHostnames map[string]string) {
+	sort.SliceStable(bmhs, func(i, j int) bool {
+		_, iPinned := pinnedHostnames[bmhs[i].Name]
+		_, jPinned := pinnedHostnames[bmhs[j].Name]
+		if iPinned != jPinned {
+			return iPinned
+		}
+		return bmhs[i].Name < bmhs[j].Name
+	})
+}
+
+// planBaremetalHosts - preview the BaremetalHosts, hostnames and IPs a scale-up of the set to
+// spec.scalePlanCount would use. Runs the same selection as ensureBaremetalHosts, but does not
+// change any BaremetalHost or IP reservation.
+func (r *OpenStackBaremetalSetReconciler) planBaremetalHosts(
+	ctx context.Context,
+	instance *ospdirectorv1beta1.OpenStackBaremetalSet,
+	cond *shared.Condition,
+	osNetCfg *ospdirectorv1beta1.OpenStackNetConfig,
+) error {
+	if instance.Spec.ScalePlanCount == nil {
+		instance.Status.ScalePlan = nil
+		return nil
+	}
+
+	plan := &ospdirectorv1beta1.BaremetalSetScalePlan{
+		Count:    *instance.Spec.ScalePlanCount,
+		Feasible: true,
+	}
+
+	planInstance := instance.DeepCopy()
+	planInstance.Spec.Count = plan.Count
+
+	baremetalHostsList, err := ospdirectorv1beta1.GetBmhHosts(
+		ctx,
+		r.GetClient(),
+		"openshift-machine-api",
+		instance.Spec.BmhLabelSelector,
+	)
+	if err != nil {
+		cond.Message = "Failed to get list of all BareMetalHost(s)"
+		cond.Reason = shared.BaremetalHostCondReasonListError
+		cond.Type = shared.BaremetalSetCondTypeError
+
+		return err
+	}
+
+	existingBaremetalHosts, err := r.getExistingBaremetalHosts(ctx, instance, cond)
+	if err != nil {
+		return err
+	}
+
+	availableBaremetalHosts, pinnedHostnames, err := ospdirectorv1beta1.VerifyBaremetalSetScaleUp(
+		r.GetLogger(),
+		planInstance,
+		baremetalHostsList,
+		existingBaremetalHosts,
+	)
+	if err != nil {
+		plan.Feasible = false
+		plan.Message = err.Error()
+		instance.Status.ScalePlan = plan
+
+		return nil
+	}
+
+	//
+	// Assign the hostnames like baremetalHostProvision does, pinned BaremetalHosts get their
+	// hostname, the others the unpinned hostnames with the lowest index
+	//
+	newBmhsNeededCount := plan.Count - len(existingBaremetalHosts.Items)
+	unpinnedHostnames := []string{}
+	for _, hostname := range ospdirectorv1beta1.GetScaleUpHostnames(planInstance, newBmhsNeededCount) {
+		if _, pinned := planInstance.GetHostAssignment(hostname); !pinned {
+			unpinnedHostnames = append(unpinnedHostnames, hostname)
+		}
+	}
+
+	sortScaleUpBaremetalHosts(availableBaremetalHosts, pinnedHostnames)
+	for i := 0; i < len(availableBaremetalHosts) && i < newBmhsNeededCount; i++ {
+		hostname, ok := pinnedHostnames[availableBaremetalHosts[i].Name]
+		if !ok {
+			if len(unpinnedHostnames) == 0 {
+				break
+			}
+			hostname = unpinnedHostnames[0]
+			unpinnedHostnames = unpinnedHostnames[1:]
+		}
+
+		plan.Hosts = append(plan.Hosts, ospdirectorv1beta1.BaremetalSetScalePlanHost{
+			Hostname:    hostname,
+			BmhName:     availableBaremetalHosts[i].Name,
+			IPAddresses: map[string]string{},
+		})
+	}
+
+	if err := r.planIPAddresses(instance, cond, osNetCfg, plan); err != nil {
+		return err
+	}
+
+	instance.Status.ScalePlan = plan
+
+	return nil
+}
+
+// planIPAddresses - get the IPs the hosts of the plan would get, like the OpenStackNetConfig
+// controller assigns them, without creating IP reservations
+func (r *OpenStackBaremetalSetReconciler) planIPAddresses(
+	instance *ospdirectorv1beta1.OpenStackBaremetalSet,
+	cond *shared.Condition,
+	osNetCfg *ospdirectorv1beta1.OpenStackNetConfig,
+	plan *ospdirectorv1beta1.BaremetalSetScalePlan,
+) error {
+	osNets, err := ospdirectorv1beta1.GetOpenStackNetsMapWithLabel(
+		r.GetClient(),
+		instance.Namespace,
+		map[string]string{},
+	)
+	if err != nil {
+		cond.Message = "Error getting OSNets"
+		cond.Reason = shared.CommonCondReasonOSNetError
+		cond.Type = shared.CommonCondTypeError
+		err = common.WrapErrorForObject(cond.Message, instance, err)
+
+		return err
+	}
+
+	for _, netName := range instance.Spec.Networks {
+		osNet, ok := osNets[netName]
+		if !ok {
+			plan.Feasible = false
+			plan.Message = fmt.Sprintf("OpenStackNet %s not found", netName)
+
+			return nil
+		}
+
+		_, cidr, err := net.ParseCIDR(osNet.Spec.Cidr)
+		if err != nil {
+			cond.Message = fmt.Sprintf("Failed to parse CIDR %s", osNet.Spec.Cidr)
+			cond.Reason = shared.CommonCondReasonCIDRParseError
+			cond.Type = shared.CommonCondTypeError
+			err = common.WrapErrorForObject(cond.Message, instance, err)
+
+			return err
+		}
+		cidrSuffix, _ := cidr.Mask.Size()
+
+		staticReservations := []ospdirectorv1beta1.IPReservation{}
+		for nodeName, nodeReservations := range osNetCfg.Spec.Reservations {
+			if ip, ok := nodeReservations.IPReservations[osNet.Spec.NameLower]; ok {
+				staticReservations = append(staticReservations, ospdirectorv1beta1.IPReservation{
+					IP:       ip,
+					Hostname: nodeName,
+				})
+			}
+		}
+
+		allocator, err := common.NewIPAllocator(
+			*cidr,
+			net.ParseIP(osNet.Spec.AllocationStart),
+			net.ParseIP(osNet.Spec.AllocationEnd),
+			osNet.Spec.ExcludeRanges,
+		)
+		if err != nil {
+			plan.Feasible = false
+			plan.Message = fmt.Sprintf("Failed to get the allocation range of network %s: %s", netName, err)
+
+			return nil
+		}
+		for _, reservation := range openstacknet.GetAllIPReservations(
+			&osNet,
+			[]ospdirectorv1beta1.IPReservation{},
+			staticReservations,
+		) {
+			allocator.Reserve(net.ParseIP(reservation.IP))
+		}
+
+		plannedReservations := []ospdirectorv1beta1.IPReservation{}
+		for i, host := range plan.Hosts {
+			//
+			// Hostnames which already exist, or have a static or preserved reservation, re-use their IP
+			//
+			if hostStatus, ok := osNetCfg.Status.Hosts[host.Hostname]; ok && hostStatus.IPAddresses[netName] != "" {
+				plan.Hosts[i].IPAddresses[netName] = hostStatus.IPAddresses[netName]
+				continue
+			}
+			if nodeReservations, ok := osNetCfg.Spec.Reservations[host.Hostname]; ok && nodeReservations.IPReservations[osNet.Spec.NameLower] != "" {
+				plan.Hosts[i].IPAddresses[netName] = fmt.Sprintf("%s/%d", nodeReservations.IPReservations[osNet.Spec.NameLower], cidrSuffix)
+				continue
+			}
+			if reservation, ok := osNet.Status.Reservations[host.Hostname]; ok {
+				plan.Hosts[i].IPAddresses[netName] = fmt.Sprintf("%s/%d", reservation.IP, cidrSuffix)
+				continue
+			}
+
+			var ip net.IP
+			ip, plannedReservations, err = common.AssignIPWithAllocator(allocator, common.AssignIPDetails{
+				RoleReservelist: plannedReservations,
+				Hostname:        host.Hostname,
+			})
+			if err != nil {
+				plan.Feasible = false
+				plan.Message = fmt.Sprintf("Failed to get an IP for %s on network %s: %s", host.Hostname, netName, err)
+
+				return nil
+			}
+
+			plan.Hosts[i].IPAddresses[netName] = fmt.Sprintf("%s/%d", ip.String(), cidrSuffix)
+		}
+	}
+
+	return nil
+}
+
 func (r *OpenStackBaremetalSetReconciler) getBmhHostRefStatus(
 	instance *ospdirectorv1beta1.OpenStackBaremetalSet,
 	cond *shared.Condition,
@@ -859,7 +1218,9 @@ func (r *OpenStackBaremetalSetReconciler) baremetalHostProvision(
 	cond *shared.Condition,
 	osNetCfg *ospdirectorv1beta1.OpenStackNetConfig,
 	bmh *metal3v1.BareMetalHost,
+	hostname string,
 	localImageURL string,
+	reprovision bool,
 	sshSecret string,
 	passwordSecret *corev1.Secret,
 ) error {
@@ -868,12 +1229,15 @@ func (r *OpenStackBaremetalSetReconciler) baremetalHostProvision(
 	//
 	bmhStatus, err := r.getBmhHostRefStatus(instance, cond, bmh.Name)
 	//
-	//  if bmhStatus is not found, get free hostname from instance.Status.baremetalHosts (HostRef == ospdirectorv1beta1.HostRefInitState ("unassigned")) for the new bmh
+	//  if bmhStatus is not found, get free hostname with the lowest index from instance.Status.baremetalHosts (HostRef == ospdirectorv1beta1.HostRefInitState ("unassigned")) for the new bmh.
+	//  If the bmh got selected via a host assignment use its hostname, hostnames with a host assignment are not used for other bmhs.
 	//
 	if err != nil && k8s_errors.IsNotFound(err) {
-		for _, bmhStatus = range instance.Status.BaremetalHosts {
-			if bmhStatus.HostRef == shared.HostRefInitState {
+		for _, unassignedHostname := range ospdirectorv1beta1.GetUnassignedHostnames(instance) {
+			_, pinned := instance.GetHostAssignment(unassignedHostname)
+			if (hostname == "" && !pinned) || unassignedHostname == hostname {
 
+				bmhStatus = instance.Status.BaremetalHosts[unassignedHostname]
 				bmhStatus.HostRef = bmh.Name
 				instance.Status.BaremetalHosts[bmhStatus.Hostname] = bmhStatus
 
@@ -945,9 +1309,10 @@ func (r *OpenStackBaremetalSetReconciler) baremetalHostProvision(
 		)
 
 		//
-		// Ensure the image url is up to date unless already provisioned
+		// Ensure the image url is up to date unless already provisioned.
+		// Changing the image of a provisioned host makes metal3 reprovision it.
 		//
-		if bmh.Status.Provisioning.State != metal3v1.StateProvisioned {
+		if bmh.Status.Provisioning.State != metal3v1.StateProvisioned || reprovision {
 			bmh.Spec.Image = &metal3v1.Image{
 				URL:      localImageURL,
 				Checksum: fmt.Sprintf("%s.md5sum", localImageURL),
@@ -962,6 +1327,13 @@ func (r *OpenStackBaremetalSetReconciler) baremetalHostProvision(
 			bmh.Spec.ConsumerRef = &corev1.ObjectReference{Name: instance.Name, Kind: instance.Kind, Namespace: instance.Namespace}
 			bmh.Spec.UserData = userDataSecretRef
 			bmh.Spec.NetworkData = networkDataSecretRef
+
+			//
+			// Set root device hints, RAID and firmware settings, the original ones get reverted on deprovision
+			//
+			if err := baremetalset.SetHostSettings(instance, bmh); err != nil {
+				return err
+			}
 		}
 
 		return nil
@@ -990,6 +1362,28 @@ func (r *OpenStackBaremetalSetReconciler) baremetalHostProvision(
 	bmhStatus.UserDataSecretName = userDataSecretRef.Name
 	bmhStatus.NetworkDataSecretName = networkDataSecretRef.Name
 	bmhStatus.ProvisioningState = shared.ProvisioningState(bmh.Status.Provisioning.State)
+	bmhStatus.ImageURL = bmh.Status.Provisioning.Image.URL
+
+	if bmh.Status.Provisioning.State == metal3v1.StateProvisioned {
+		bmhStatus.ProvisioningStartTime = nil
+	} else if bmhStatus.ProvisioningStartTime == nil {
+		now := metav1.Now()
+		bmhStatus.ProvisioningStartTime = &now
+	}
+
+	//
+	// Track the progress of the host if the image changed. Without an update strategy
+	// the host keeps its current image and is not reported as pending.
+	//
+	switch {
+	case instance.Spec.UpdateStrategy != nil && bmh.Spec.Image != nil && bmh.Spec.Image.URL != localImageURL:
+		bmhStatus.UpdateState = ospdirectorv1beta1.HostUpdatePending
+	case bmhStatus.UpdateState != "" &&
+		(bmh.Status.Provisioning.State != metal3v1.StateProvisioned || bmhStatus.ImageURL != localImageURL):
+		bmhStatus.UpdateState = ospdirectorv1beta1.HostUpdateInProgress
+	default:
+		bmhStatus.UpdateState = ""
+	}
 
 	actualBMHStatus := instance.Status.BaremetalHosts[bmhStatus.Hostname]
 	if !reflect.DeepEqual(actualBMHStatus, bmhStatus) {
@@ -1007,6 +1401,46 @@ func (r *OpenStackBaremetalSetReconciler) baremetalHostProvision(
 
 }
 
+// getCloudInitUserData - render the userData template and merge the cloud-init parts of the baremetalset
+func (r *OpenStackBaremetalSetReconciler) getCloudInitUserData(
+	ctx context.Context,
+	instance *ospdirectorv1beta1.OpenStackBaremetalSet,
+	cond *shared.Condition,
+	userDataTemplate common.Template,
+) (string, error) {
+	parts, err := common.GetCloudInitParts(ctx, r, instance.Namespace, instance.Spec.CloudInitParts)
+	if err != nil {
+		cond.Message = fmt.Sprintf("Error getting cloud-init parts for the baremetalset %s", instance.Name)
+		cond.Reason = shared.CommonCondReasonCloudInitPartError
+		cond.Type = shared.CommonCondTypeError
+		err = common.WrapErrorForObject(cond.Message, instance, err)
+
+		return "", err
+	}
+
+	renderedData, err := common.GetTemplateData(userDataTemplate)
+	if err != nil {
+		cond.Message = fmt.Sprintf("Error rendering user data for the baremetalset %s", instance.Name)
+		cond.Reason = shared.CommonCondReasonSecretError
+		cond.Type = shared.CommonCondTypeError
+		err = common.WrapErrorForObject(cond.Message, instance, err)
+
+		return "", err
+	}
+
+	userData, err := common.MergeCloudInitUserData(renderedData["userData"], "userData", parts)
+	if err != nil {
+		cond.Message = fmt.Sprintf("Error merging cloud-init parts into the user data for the baremetalset %s", instance.Name)
+		cond.Reason = shared.CommonCondReasonCloudInitPartError
+		cond.Type = shared.CommonCondTypeError
+		err = common.WrapErrorForObject(cond.Message, instance, err)
+
+		return "", err
+	}
+
+	return userData, nil
+}
+
 // Creates/updates user data and network data cloud init secrets for a BMH if
 // those secrets do not already exist or do exist but have our labels on them.
 // If the secrets exist and do not have our labels, then it means the user
@@ -1130,6 +1564,18 @@ func (r *OpenStackBaremetalSetReconciler) cloudInitProvision(ctx context.Context
 			ConfigOptions:      templateParameters,
 		}
 
+		//
+		// merge the site specific cloud-init parts into the rendered userData
+		//
+		if len(instance.Spec.CloudInitParts) > 0 {
+			userData, err := r.getCloudInitUserData(ctx, instance, cond, userDataSt)
+			if err != nil {
+				return nil, nil, err
+			}
+			userDataSt.AdditionalTemplate = nil
+			userDataSt.CustomData = map[string]string{"userData": userData}
+		}
+
 		userDataSecretRef = &corev1.SecretReference{
 			Name:      userDataSecretName,
 			Namespace: "openshift-machine-api",
@@ -1230,6 +1676,11 @@ func (r *OpenStackBaremetalSetReconciler) cloudInitProvision(ctx context.Context
 		templateParameters := make(map[string]interface{})
 		templateParameters["CtlplaneIp"] = ip.String()
 		templateParameters["CtlplaneInterface"] = instance.Spec.CtlplaneInterface
+		common.SetCtlplaneNetworkTemplateParameters(
+			templateParameters,
+			instance.Spec.CtlplaneInterface,
+			instance.Spec.CtlplaneNetworkConfig,
+		)
 		templateParameters["CtlplaneGateway"] = ctlPlaneNetwork.Spec.Gateway
 		templateParameters["CtlplaneNetmask"] = net.IP(netMask).String()
 		if len(instance.Spec.BootstrapDNS) > 0 {
@@ -1244,20 +1695,53 @@ func (r *OpenStackBaremetalSetReconciler) cloudInitProvision(ctx context.Context
 			templateParameters["CtlplaneDnsSearch"] = osNetCfg.Spec.DNSSearchDomains
 		}
 
-		routes := []map[string]string{}
-		for _, route := range ctlPlaneNetwork.Spec.Routes {
-			_, routeNetwork, err := net.ParseCIDR(route.Destination)
+		getRoutes := func(netRoutes []ospdirectorv1beta1.Route) ([]map[string]string, error) {
+			routes := []map[string]string{}
+			for _, route := range netRoutes {
+				_, routeNetwork, err := net.ParseCIDR(route.Destination)
+				if err != nil {
+					cond.Message = fmt.Sprintf("Error parsing route CIDR %v for network %v", route.Destination, netNameLower)
+					cond.Reason = shared.CommonCondReasonOSNetError
+					cond.Type = shared.CommonCondTypeError
+					err = common.WrapErrorForObject(cond.Message, instance, err)
+					return nil, err
+				}
+				routes = append(routes, map[string]string{"network": routeNetwork.IP.String(), "netmask": net.IP(routeNetwork.Mask).String(), "gateway": route.Nexthop})
+			}
+
+			return routes, nil
+		}
+
+		routes, err := getRoutes(ctlPlaneNetwork.Spec.Routes)
+		if err != nil {
+			return nil, nil, err
+		}
+		templateParameters["CtlplaneRoutes"] = routes
+
+		//
+		// on a dual-stack ctlplane network add the IPv6 network
+		//
+		if ctlPlaneNetwork.Spec.IPv6 != nil {
+			ipv6Cidr := instance.Status.BaremetalHosts[hostName].IPAddresses[ospdirectorv1beta1.IPv6AddressKey(netNameLower)]
+			ipv6, ipv6Network, err := net.ParseCIDR(ipv6Cidr)
 			if err != nil {
-				cond.Message = fmt.Sprintf("Error parsing route CIDR %v for network %v", route.Destination, netNameLower)
+				cond.Message = fmt.Sprintf("Error parsing IPv6 CIDR %v for network %v", ipv6Cidr, netNameLower)
 				cond.Reason = shared.CommonCondReasonOSNetError
 				cond.Type = shared.CommonCondTypeError
 				err = common.WrapErrorForObject(cond.Message, instance, err)
 				return nil, nil, err
 			}
-			routes = append(routes, map[string]string{"network": routeNetwork.IP.String(), "netmask": net.IP(routeNetwork.Mask).String(), "gateway": route.Nexthop})
-		}
 
-		templateParameters["CtlplaneRoutes"] = routes
+			ipv6Routes, err := getRoutes(ctlPlaneNetwork.Spec.IPv6.Routes)
+			if err != nil {
+				return nil, nil, err
+			}
+
+			templateParameters["CtlplaneIpv6"] = ipv6.String()
+			templateParameters["CtlplaneIpv6Netmask"] = net.IP(ipv6Network.Mask).String()
+			templateParameters["CtlplaneIpv6Gateway"] = ctlPlaneNetwork.Spec.IPv6.Gateway
+			templateParameters["CtlplaneIpv6Routes"] = ipv6Routes
+		}
 
 		networkDataSecretName := fmt.Sprintf(baremetalset.CloudInitNetworkDataSecretName, instance.Name, bmh.Name)
 
@@ -1322,6 +1806,24 @@ func (r *OpenStackBaremetalSetReconciler) baremetalHostDeprovision(
 		instance,
 	)
 
+	ospHostname, err := r.baremetalHostRelease(ctx, instance, cond, baremetalHost)
+	if err != nil {
+		return ospHostname, err
+	}
+
+	// Set status (remove this BaremetalHost entry)
+	delete(instance.Status.BaremetalHosts, bmh.Hostname)
+
+	return ospHostname, nil
+}
+
+// Release a BaremetalHost from the OpenStackBaremetalSet, which makes metal3 deprovision it
+func (r *OpenStackBaremetalSetReconciler) baremetalHostRelease(
+	ctx context.Context,
+	instance *ospdirectorv1beta1.OpenStackBaremetalSet,
+	cond *shared.Condition,
+	baremetalHost *metal3v1.BareMetalHost,
+) (string, error) {
 	// Clean-up cloud-init secrets.  If they were provided by the user, we only
 	// remove the "must-gather" label from them.  If we auto-generated them during
 	// provisioning (which is indicated by the "clearUserData" and "clearNetworkData"
@@ -1350,6 +1852,16 @@ func (r *OpenStackBaremetalSetReconciler) baremetalHostDeprovision(
 	delete(annotations, shared.HostRemovalAnnotation)
 	baremetalHost.GetObjectMeta().SetAnnotations(annotations)
 
+	// Restore the root device hints, RAID and firmware settings the BMH had before provisioning
+	err = baremetalset.RevertHostSettings(baremetalHost)
+	if err != nil {
+		cond.Message = fmt.Sprintf("Failed to revert %s %s settings", baremetalHost.Kind, baremetalHost.Name)
+		cond.Reason = shared.BaremetalHostCondReasonUpdateError
+		cond.Type = shared.CommonCondTypeError
+
+		return ospHostname, err
+	}
+
 	baremetalHost.Spec.Online = false
 	baremetalHost.Spec.ConsumerRef = nil
 	baremetalHost.Spec.Image = nil
@@ -1375,12 +1887,132 @@ func (r *OpenStackBaremetalSetReconciler) baremetalHostDeprovision(
 	}
 	r.Log.Info(fmt.Sprintf("BaremetalHost deleted: bmh %s - osp name %s", baremetalHost.Name, ospHostname))
 
-	// Set status (remove this BaremetalHost entry)
-	delete(instance.Status.BaremetalHosts, bmh.Hostname)
-
 	return ospHostname, nil
 }
 
+// Replace the BaremetalHosts which failed to provision or did not get provisioned within the
+// provisioning timeout. The failed BaremetalHost gets released and quarantined, its hostname
+// gets unassigned to be picked up by another free matching BaremetalHost.
+// Returns the names of the released BaremetalHosts.
+func (r *OpenStackBaremetalSetReconciler) replaceFailedBaremetalHosts(
+	ctx context.Context,
+	instance *ospdirectorv1beta1.OpenStackBaremetalSet,
+	cond *shared.Condition,
+	existingBaremetalHosts *metal3v1.BareMetalHostList,
+) ([]string, error) {
+	releasedHosts := []string{}
+
+	if instance.Spec.MaxReplacementAttempts == 0 {
+		return releasedHosts, nil
+	}
+
+	for idx := range existingBaremetalHosts.Items {
+		bmh := &existingBaremetalHosts.Items[idx]
+
+		var bmhStatus ospdirectorv1beta1.HostStatus
+		for _, hostStatus := range instance.Status.DeepCopy().BaremetalHosts {
+			if hostStatus.HostRef == bmh.Name {
+				bmhStatus = hostStatus
+			}
+		}
+		if bmhStatus.Hostname == "" {
+			continue
+		}
+
+		if bmh.Status.Provisioning.State == metal3v1.StateProvisioned {
+			// the host might got provisioned after all replacement attempts were exhausted
+			if bmhStatus.Conditions.Find(shared.BaremetalHostCondTypeFailed) != nil {
+				conditions := shared.ConditionList{}
+				for _, c := range bmhStatus.Conditions {
+					if c.Type != shared.BaremetalHostCondTypeFailed {
+						conditions = append(conditions, c)
+					}
+				}
+				bmhStatus.Conditions = conditions
+				instance.Status.BaremetalHosts[bmhStatus.Hostname] = bmhStatus
+			}
+
+			continue
+		}
+
+		var reason shared.ConditionReason
+		var message string
+
+		switch {
+		case bmh.Status.OperationalStatus == metal3v1.OperationalStatusError:
+			reason = shared.BaremetalSetCondReasonProvisioningFailed
+			message = fmt.Sprintf("BaremetalHost %s failed to provision: %s %s",
+				bmh.Name,
+				bmh.Status.ErrorType,
+				bmh.Status.ErrorMessage,
+			)
+		case instance.Spec.ProvisioningTimeout != nil &&
+			bmhStatus.ProvisioningStartTime != nil &&
+			time.Since(bmhStatus.ProvisioningStartTime.Time) > instance.Spec.ProvisioningTimeout.Duration:
+			reason = shared.BaremetalSetCondReasonProvisioningTimeout
+			message = fmt.Sprintf("BaremetalHost %s did not get provisioned within %s",
+				bmh.Name,
+				instance.Spec.ProvisioningTimeout.Duration,
+			)
+		default:
+			continue
+		}
+
+		if len(bmhStatus.FailedHostRefs) >= instance.Spec.MaxReplacementAttempts {
+			message = fmt.Sprintf("%s, %d replacement attempts exhausted", message, len(bmhStatus.FailedHostRefs))
+
+			current := bmhStatus.Conditions.Find(shared.BaremetalHostCondTypeFailed)
+			if current == nil || current.Reason != reason || current.Message != message {
+				bmhStatus.Conditions.Set(shared.BaremetalHostCondTypeFailed, corev1.ConditionTrue, reason, message)
+				instance.Status.BaremetalHosts[bmhStatus.Hostname] = bmhStatus
+
+				common.LogForObject(r, message, instance)
+			}
+
+			continue
+		}
+
+		common.LogForObject(
+			r,
+			fmt.Sprintf("%s, replacing it for %s", message, bmhStatus.Hostname),
+			instance,
+		)
+
+		//
+		// Quarantine the BaremetalHost so it does not get selected again
+		//
+		bmh.SetLabels(shared.MergeStringMaps(
+			bmh.GetLabels(),
+			map[string]string{shared.BaremetalHostQuarantineLabel: "true"},
+		))
+		bmh.SetAnnotations(shared.MergeStringMaps(
+			bmh.GetAnnotations(),
+			map[string]string{shared.BaremetalHostQuarantineReasonAnnotation: message},
+		))
+
+		_, err := r.baremetalHostRelease(ctx, instance, cond, bmh)
+		if err != nil {
+			return releasedHosts, err
+		}
+		releasedHosts = append(releasedHosts, bmh.Name)
+
+		//
+		// Keep hostname and IPs, the host gets assigned to a new BaremetalHost
+		//
+		bmhStatus.HostRef = shared.HostRefInitState
+		bmhStatus.FailedHostRefs = append(bmhStatus.FailedHostRefs, bmh.Name)
+		bmhStatus.ProvisioningStartTime = nil
+		bmhStatus.UserDataSecretName = ""
+		bmhStatus.NetworkDataSecretName = ""
+		bmhStatus.ImageURL = ""
+		bmhStatus.UpdateState = ""
+		bmhStatus.Conditions.Set(shared.BaremetalHostCondTypeReplaced, corev1.ConditionTrue, reason, message)
+		instance.Status.BaremetalHosts[bmhStatus.Hostname] = bmhStatus
+	}
+
+	return releasedHosts, nil
+}
+
 // Deletes user data and network data cloud init secrets for a BMH if those secrets
 // have our labels on them.  If the secrets do not have our labels, then it means
 // the user created them manually and that we should therefore leave them alone apart
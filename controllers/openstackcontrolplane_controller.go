@@ -328,6 +328,7 @@ func (r *OpenStackControlPlaneReconciler) Reconcile(ctx context.Context, req ctr
 	}
 
 	instance.Status.ProvisioningStatus.DesiredCount = len(instance.Spec.VirtualMachineRoles)
+	instance.Status.Health = vmset.GetControlPlaneHealthStatus(vmSets)
 	instance.Status.ProvisioningStatus.ReadyCount =
 		vmSetStateCounts[shared.ProvisioningState(shared.VMSetCondTypeProvisioned)] +
 			vmSetStateCounts[shared.ProvisioningState(shared.VMSetCondTypeEmpty)]
@@ -766,6 +767,7 @@ func (r *OpenStackControlPlaneReconciler) createOrUpdateVMSets(
 			vmSet.Spec.AdditionalDisks = vmRole.AdditionalDisks
 			vmSet.Spec.DeploymentSSHSecret = deploymentSecret.Name
 			vmSet.Spec.CtlplaneInterface = vmRole.CtlplaneInterface
+			vmSet.Spec.CtlplaneNetworkConfig = vmRole.CtlplaneNetworkConfig
 			vmSet.Spec.Networks = vmRole.Networks
 			vmSet.Spec.RoleName = vmRole.RoleName
 			vmSet.Spec.IsTripleoRole = vmRole.IsTripleoRole
@@ -775,6 +777,14 @@ func (r *OpenStackControlPlaneReconciler) createOrUpdateVMSets(
 			vmSet.Spec.NodeSelector = vmRole.NodeSelector
 			vmSet.Spec.EvictionStrategy = vmRole.EvictionStrategy
 			vmSet.Spec.RunStrategy = vmRole.RunStrategy
+			vmSet.Spec.ScaleDownPolicy = vmRole.ScaleDownPolicy
+			vmSet.Spec.Placement = vmRole.Placement
+			vmSet.Spec.UpdateStrategy = vmRole.UpdateStrategy
+			vmSet.Spec.Performance = vmRole.Performance
+			vmSet.Spec.Firmware = vmRole.Firmware
+			vmSet.Spec.CloudInitParts = vmRole.CloudInitParts
+			vmSet.Spec.Watchdog = vmRole.Watchdog
+			vmSet.Spec.FencingCheck = vmRole.FencingCheck
 
 			err := controllerutil.SetControllerReference(instance, vmSet, r.Scheme)
 			if err != nil {
@@ -825,7 +835,7 @@ func (r *OpenStackControlPlaneReconciler) createOrUpdateOpenStackClient(
 ) (*ospdirectorv1beta1.OpenStackClient, error) {
 	osc := &ospdirectorv1beta1.OpenStackClient{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      "openstackclient",
+			Name:      openstackclient.Name,
 			Namespace: instance.Namespace,
 		},
 	}
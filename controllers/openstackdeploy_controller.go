@@ -19,6 +19,8 @@ package controllers
 import (
 	"context"
 	"fmt"
+	"sort"
+	"strings"
 	"time"
 
 	"k8s.io/apimachinery/pkg/api/equality"
@@ -27,6 +29,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes"
+	snapshotv1 "kubevirt.io/api/snapshot/v1beta1"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
@@ -38,6 +41,7 @@ import (
 	ospdirectorv1beta2 "github.com/openstack-k8s-operators/osp-director-operator/api/v1beta2"
 	common "github.com/openstack-k8s-operators/osp-director-operator/pkg/common"
 	"github.com/openstack-k8s-operators/osp-director-operator/pkg/openstackdeploy"
+	vmset "github.com/openstack-k8s-operators/osp-director-operator/pkg/vmset"
 )
 
 // OpenStackDeployReconciler reconciles a OpenStackDeploy object
@@ -72,6 +76,8 @@ func (r *OpenStackDeployReconciler) GetScheme() *runtime.Scheme {
 // +kubebuilder:rbac:groups=osp-director.openstack.org,resources=openstackdeploys/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=osp-director.openstack.org,resources=openstackdeploys/finalizers,verbs=update
 // +kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;list;create;update;delete;watch
+// +kubebuilder:rbac:groups=osp-director.openstack.org,resources=openstackvmsets,verbs=get;list;watch
+// +kubebuilder:rbac:groups=snapshot.kubevirt.io,namespace=openstack,resources=virtualmachinesnapshots,verbs=create;delete;get;list;patch;update;watch
 
 // Reconcile - OpenStackDeploy
 func (r *OpenStackDeployReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
@@ -187,6 +193,18 @@ func (r *OpenStackDeployReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 	// Should changing ansible settings restart a job
 	// Need to make interrupting a job safe
 	if instance.Status.ConfigVersion != instance.Spec.ConfigVersion {
+		//
+		// create a restore point of the control plane VMs before the deploy job starts, a snapshot
+		// set of a previous config version is no restore point of this deploy
+		//
+		instance.Status.SnapshotSet = openstackdeploy.GetCurrentSnapshotSet(instance)
+		if instance.Spec.SnapshotBeforeDeploy && instance.Status.SnapshotSet == "" {
+			ctrlResult, err := r.snapshotVMs(ctx, instance, cond)
+			if (err != nil) || (ctrlResult != ctrl.Result{}) {
+				return ctrlResult, err
+			}
+		}
+
 		// Define a new Job object
 		job := openstackdeploy.DeployJob(
 			instance,
@@ -281,6 +299,116 @@ func (r *OpenStackDeployReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 	return ctrl.Result{}, nil
 }
 
+// snapshotVMs - create a VirtualMachineSnapshot of each VM of the TripleO role VMSets matched by the
+// ansible limit of the deploy and wait for them to be ready
+func (r *OpenStackDeployReconciler) snapshotVMs(
+	ctx context.Context,
+	instance *ospdirectorv1beta1.OpenStackDeploy,
+	cond *shared.Condition,
+) (ctrl.Result, error) {
+	vmSetList := &ospdirectorv1beta2.OpenStackVMSetList{}
+	if err := r.List(ctx, vmSetList, client.InNamespace(instance.Namespace)); err != nil {
+		cond.Message = "Failed to list OpenStackVMSets"
+		cond.Reason = shared.DeployCondReasonSnapshotError
+		cond.Type = shared.DeployCondTypeError
+		err = common.WrapErrorForObject(cond.Message, instance, err)
+
+		return ctrl.Result{}, err
+	}
+
+	snapshotSet := openstackdeploy.GetSnapshotSetName(instance.Name, instance.Spec.ConfigVersion)
+	notReady := []string{}
+	for idx := range vmSetList.Items {
+		vmSet := &vmSetList.Items[idx]
+		if !vmSet.Spec.IsTripleoRole {
+			continue
+		}
+
+		hostnames := []string{}
+		for hostname, host := range vmSet.Status.VMHosts {
+			if hostname != "" && !host.AnnotatedForDeletion {
+				hostnames = append(hostnames, hostname)
+			}
+		}
+		sort.Strings(hostnames)
+
+		// only the VMSets of the roles and hosts the deploy runs on
+		if !openstackdeploy.LimitMatches(instance.Spec.AdvancedSettings.Limit, append([]string{vmSet.Spec.RoleName}, hostnames...)...) {
+			continue
+		}
+
+		for _, hostname := range hostnames {
+			snapshot := &snapshotv1.VirtualMachineSnapshot{}
+			name := fmt.Sprintf("%s-%s", snapshotSet, hostname)
+			err := r.Get(ctx, types.NamespacedName{Name: name, Namespace: instance.Namespace}, snapshot)
+			if err != nil && !k8s_errors.IsNotFound(err) {
+				cond.Message = fmt.Sprintf("Failed to get VirtualMachineSnapshot %s", name)
+				cond.Reason = shared.DeployCondReasonSnapshotError
+				cond.Type = shared.DeployCondTypeError
+				err = common.WrapErrorForObject(cond.Message, instance, err)
+
+				return ctrl.Result{}, err
+			}
+
+			if k8s_errors.IsNotFound(err) {
+				// the snapshots belong to the VMSet and are kept when the OpenStackDeploy gets deleted
+				snapshot = vmset.VirtualMachineSnapshot(
+					name,
+					instance.Namespace,
+					hostname,
+					common.GetLabels(vmSet, vmset.AppLabel, map[string]string{
+						vmset.SnapshotSetLabelSelector: snapshotSet,
+					}),
+				)
+				if err := controllerutil.SetOwnerReference(vmSet, snapshot, r.Scheme); err != nil {
+					cond.Message = fmt.Sprintf("Error set owner reference for %s", name)
+					cond.Reason = shared.CommonCondReasonControllerReferenceError
+					cond.Type = shared.DeployCondTypeError
+					err = common.WrapErrorForObject(cond.Message, instance, err)
+
+					return ctrl.Result{}, err
+				}
+
+				if err := r.Create(ctx, snapshot); err != nil {
+					cond.Message = fmt.Sprintf("Failed to create VirtualMachineSnapshot %s", name)
+					cond.Reason = shared.DeployCondReasonSnapshotError
+					cond.Type = shared.DeployCondTypeError
+					err = common.WrapErrorForObject(cond.Message, instance, err)
+
+					return ctrl.Result{}, err
+				}
+				common.LogForObject(r, fmt.Sprintf("VirtualMachineSnapshot %s created", name), instance)
+			}
+
+			if vmset.IsSnapshotFailed(snapshot) {
+				cond.Message = fmt.Sprintf("VirtualMachineSnapshot %s failed, check its status and events", name)
+				cond.Reason = shared.DeployCondReasonSnapshotError
+				cond.Type = shared.DeployCondTypeError
+
+				return ctrl.Result{}, fmt.Errorf("%s", cond.Message)
+			}
+
+			if !vmset.IsSnapshotReady(snapshot) {
+				notReady = append(notReady, name)
+			}
+		}
+	}
+
+	if len(notReady) > 0 {
+		cond.Message = fmt.Sprintf("Waiting on VirtualMachineSnapshots %s to be ready", strings.Join(notReady, ","))
+		cond.Reason = shared.DeployCondReasonSnapshotCreated
+		cond.Type = shared.DeployCondTypeInitializing
+		common.LogForObject(r, cond.Message, instance)
+
+		return ctrl.Result{RequeueAfter: time.Second * 10}, nil
+	}
+
+	instance.Status.SnapshotSet = snapshotSet
+	common.LogForObject(r, fmt.Sprintf("VirtualMachineSnapshot set %s ready", snapshotSet), instance)
+
+	return ctrl.Result{}, nil
+}
+
 func (r *OpenStackDeployReconciler) getNormalizedStatus(status *ospdirectorv1beta1.OpenStackDeployStatus) *ospdirectorv1beta1.OpenStackDeployStatus {
 
 	//
@@ -225,8 +225,12 @@ func (r *OpenStackNetReconciler) Reconcile(ctx context.Context, req ctrl.Request
 	for _, roleReservation := range instance.Spec.RoleReservations {
 		for _, reservation := range roleReservation.Reservations {
 			reservedIPCount++
+			if reservation.IPv6 != "" {
+				reservedIPCount++
+			}
 			reservations[reservation.Hostname] = ospdirectorv1beta1.NodeIPReservation{
 				IP:      reservation.IP,
+				IPv6:    reservation.IPv6,
 				Deleted: reservation.Deleted,
 			}
 		}
@@ -235,6 +239,14 @@ func (r *OpenStackNetReconciler) Reconcile(ctx context.Context, req ctrl.Request
 	instance.Status.ReservedIPCount = reservedIPCount
 	instance.Status.Reservations = reservations
 
+	excludedIPCount, err := openstacknet.GetExcludedIPCount(instance)
+	if err != nil {
+		cond.Message = fmt.Sprintf("OpenStackNet %s has invalid exclude ranges", instance.Name)
+		cond.Type = shared.NetError
+		return ctrl.Result{}, err
+	}
+	instance.Status.ExcludedIPCount = excludedIPCount
+
 	// If we get this far, we assume the NAD been successfully created (NAD does not
 	// have a status block we can examine)
 	cond.Message = fmt.Sprintf("OpenStackNet %s has been successfully configured on targeted node(s)", instance.Name)
@@ -28,7 +28,9 @@ import (
 
 	"k8s.io/apimachinery/pkg/api/equality"
 	k8s_errors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
@@ -84,6 +86,8 @@ func (r *OpenStackNetConfigReconciler) GetScheme() *runtime.Scheme {
 //+kubebuilder:rbac:groups=osp-director.openstack.org,resources=openstacknetconfigs/finalizers,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=osp-director.openstack.org,resources=openstacknetattachments,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=osp-director.openstack.org,resources=openstacknets,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=core,resources=configmaps,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=externaldns.k8s.io,resources=dnsendpoints,verbs=get;list;watch;create;update;patch;delete
 
 // Reconcile -
 func (r *OpenStackNetConfigReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
@@ -300,6 +304,8 @@ func (r *OpenStackNetConfigReconciler) Reconcile(ctx context.Context, req ctrl.R
 	instance.Status.ProvisioningStatus.NetReadyCount = 0
 
 	ctlplaneReservations := map[string]int{}
+	// domain per key of the host IPAddresses, used for the DNS records
+	domains := map[string]string{}
 	for _, net := range instance.Spec.Networks {
 
 		// TODO: (mschuppert) cleanup single removed netConfig in list
@@ -336,6 +342,9 @@ func (r *OpenStackNetConfigReconciler) Reconcile(ctx context.Context, req ctrl.R
 				ctlplaneReservations[osNet.Spec.NameLower] = osNet.Status.ReservedIPCount
 			}
 
+			domains[osNet.Spec.NameLower] = osNet.Spec.DomainName
+			domains[ospdirectorv1beta1.IPv6AddressKey(osNet.Spec.NameLower)] = osNet.Spec.DomainName
+
 			instance.Status.ProvisioningStatus.NetReadyCount++
 		}
 	}
@@ -354,7 +363,20 @@ func (r *OpenStackNetConfigReconciler) Reconcile(ctx context.Context, req ctrl.R
 	}
 
 	//
-	// 3) Create or update the MACAddress CR object
+	// 3) Create or update the DNS records of the hosts
+	//
+	err = r.ensureDNSRecords(
+		ctx,
+		instance,
+		cond,
+		domains,
+	)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	//
+	// 4) Create or update the MACAddress CR object
 	//
 	instance.Status.ProvisioningStatus.PhysNetDesiredCount = len(instance.Spec.OVNBridgeMacMappings.PhysNetworks)
 	instance.Status.ProvisioningStatus.PhysNetReadyCount = 0
@@ -654,12 +676,22 @@ func (r *OpenStackNetConfigReconciler) applyNetConfig(
 			osNet.Spec.Cidr = subnet.IPv4.Cidr
 			osNet.Spec.Gateway = subnet.IPv4.Gateway
 			osNet.Spec.Routes = subnet.IPv4.Routes
+			osNet.Spec.ExcludeRanges = subnet.IPv4.ExcludeRanges
 		} else {
 			osNet.Spec.AllocationEnd = subnet.IPv6.AllocationEnd
 			osNet.Spec.AllocationStart = subnet.IPv6.AllocationStart
 			osNet.Spec.Cidr = subnet.IPv6.Cidr
 			osNet.Spec.Gateway = subnet.IPv6.Gateway
 			osNet.Spec.Routes = subnet.IPv6.Routes
+			osNet.Spec.ExcludeRanges = subnet.IPv6.ExcludeRanges
+		}
+
+		// on a dual-stack subnet the IPv4 details are the primary ones
+		if subnet.IPv4.Cidr != "" && subnet.IPv6.Cidr != "" {
+			ipv6 := subnet.IPv6
+			osNet.Spec.IPv6 = &ipv6
+		} else {
+			osNet.Spec.IPv6 = nil
 		}
 
 		osNet.Spec.RoleReservations = reservations
@@ -734,6 +766,15 @@ func (r *OpenStackNetConfigReconciler) getNetStatus(
 		return ctrl.Result{}, err
 	}
 
+	cidrIPv6Suffix := 0
+	if osNet.Spec.IPv6 != nil {
+		_, cidrIPv6Suffix, err = common.GetCidrParts(osNet.Spec.IPv6.Cidr)
+		if err != nil {
+			// TODO set cond
+			return ctrl.Result{}, err
+		}
+	}
+
 	for _, roleNetStatus := range osNet.Spec.RoleReservations {
 		for _, roleReservation := range roleNetStatus.Reservations {
 			//
@@ -758,6 +799,14 @@ func (r *OpenStackNetConfigReconciler) getNetStatus(
 					hostStatus.OVNBridgeMacAdresses = map[string]string{}
 				}
 				hostStatus.IPAddresses[osNet.Spec.NameLower] = fmt.Sprintf("%s/%d", nodeReservation.IP, cidrSuffix)
+
+				// on a dual-stack network also add the IPv6 address
+				ipv6Key := ospdirectorv1beta1.IPv6AddressKey(osNet.Spec.NameLower)
+				if osNet.Spec.IPv6 != nil && nodeReservation.IPv6 != "" {
+					hostStatus.IPAddresses[ipv6Key] = fmt.Sprintf("%s/%d", nodeReservation.IPv6, cidrIPv6Suffix)
+				} else {
+					delete(hostStatus.IPAddresses, ipv6Key)
+				}
 				instance.Status.Hosts[roleReservation.Hostname] = hostStatus
 			} else {
 				delete(instance.Status.Hosts, roleReservation.Hostname)
@@ -1303,13 +1352,26 @@ func (r *OpenStackNetConfigReconciler) ensureIPs(
 	if len(instance.Spec.Reservations) > 0 {
 		for nodeName, nodeReservations := range instance.Spec.Reservations {
 			if nodeNetIPReservation, ok := nodeReservations.IPReservations[osNet.Spec.NameLower]; ok {
+				//
+				// on a dual-stack network keep the assigned IPv6 address, if there is no static one
+				//
+				nodeNetIPv6Reservation := ""
+				if osNet.Spec.IPv6 != nil {
+					nodeNetIPv6Reservation = currentReservations[nodeName].IPv6
+					if staticIPv6, ok := nodeReservations.IPReservations[ospdirectorv1beta1.IPv6AddressKey(osNet.Spec.NameLower)]; ok {
+						nodeNetIPv6Reservation = staticIPv6
+					}
+				}
+
 				currentReservations[nodeName] = ospdirectorv1beta1.NodeIPReservation{
-					IP: nodeNetIPReservation,
+					IP:   nodeNetIPReservation,
+					IPv6: nodeNetIPv6Reservation,
 				}
 				staticReservations = append(
 					staticReservations,
 					ospdirectorv1beta1.IPReservation{
 						IP:         nodeNetIPReservation,
+						IPv6:       nodeNetIPv6Reservation,
 						Hostname:   nodeName,
 						ServiceVIP: serviceVIP,
 						VIP:        vip,
@@ -1319,18 +1381,43 @@ func (r *OpenStackNetConfigReconciler) ensureIPs(
 		}
 	}
 
-	_, cidr, err := net.ParseCIDR(osNet.Spec.Cidr)
+	//
+	// Get allocators which know all reservations of the network and keep track
+	// of the IPs assigned to new hosts
+	//
+	allReservations := openstacknet.GetAllIPReservations(
+		osNet,
+		[]ospdirectorv1beta1.IPReservation{},
+		staticReservations,
+	)
+	allocator, err := r.getIPAllocator(
+		instance,
+		cond,
+		osNet.Spec.Cidr,
+		osNet.Spec.AllocationStart,
+		osNet.Spec.AllocationEnd,
+		osNet.Spec.ExcludeRanges,
+		allReservations,
+	)
 	if err != nil {
-		cond.Message = fmt.Sprintf("Failed to parse CIDR %s", osNet.Spec.Cidr)
-		cond.Reason = shared.CommonCondReasonCIDRParseError
-		cond.Type = shared.NetConfigError
-		err = common.WrapErrorForObject(cond.Message, instance, err)
-
 		return err
 	}
 
-	start := net.ParseIP(osNet.Spec.AllocationStart)
-	end := net.ParseIP(osNet.Spec.AllocationEnd)
+	var ipv6Allocator *common.IPAllocator
+	if osNet.Spec.IPv6 != nil {
+		ipv6Allocator, err = r.getIPAllocator(
+			instance,
+			cond,
+			osNet.Spec.IPv6.Cidr,
+			osNet.Spec.IPv6.AllocationStart,
+			osNet.Spec.IPv6.AllocationEnd,
+			osNet.Spec.IPv6.ExcludeRanges,
+			openstacknet.GetAllIPv6Reservations(allReservations),
+		)
+		if err != nil {
+			return err
+		}
+	}
 
 	for _, host := range allRoleHosts {
 
@@ -1349,6 +1436,20 @@ func (r *OpenStackNetConfigReconciler) ensureIPs(
 				Deleted:    false,
 			}
 
+			//
+			// on a dual-stack network the host also needs an IPv6 address, e.g. if the
+			// network became dual-stack after the IPv4 reservation
+			//
+			if osNet.Spec.IPv6 != nil {
+				nodeReservation.IPv6 = reservation.IPv6
+				if nodeReservation.IPv6 == "" {
+					nodeReservation.IPv6, err = r.assignIPv6(instance, cond, ipv6Allocator, osNet, hostname)
+					if err != nil {
+						return err
+					}
+				}
+			}
+
 			found := false
 			for _, res := range reservations {
 				if res.Hostname == hostname {
@@ -1373,21 +1474,12 @@ func (r *OpenStackNetConfigReconciler) ensureIPs(
 			//
 			// No reservation found, so create a new one
 			//
-			var ip net.IPNet
-			ip, reservations, err = common.AssignIP(common.AssignIPDetails{
-				IPnet:           *cidr,
-				RangeStart:      start,
-				RangeEnd:        end,
+			var ip net.IP
+			ip, reservations, err = common.AssignIPWithAllocator(allocator, common.AssignIPDetails{
 				RoleReservelist: reservations,
-				Reservelist: openstacknet.GetAllIPReservations(
-					osNet,
-					reservations,
-					staticReservations,
-				),
-				ExcludeRanges: []string{},
-				Hostname:      hostname,
-				VIP:           vip,
-				Deleted:       false,
+				Hostname:        hostname,
+				VIP:             vip,
+				Deleted:         false,
 			})
 			if err != nil {
 				cond.Message = fmt.Sprintf("Failed to do ip reservation: %s", hostname)
@@ -1398,6 +1490,17 @@ func (r *OpenStackNetConfigReconciler) ensureIPs(
 				return err
 			}
 
+			//
+			// on a dual-stack network add the IPv6 address to the just created reservation
+			//
+			if osNet.Spec.IPv6 != nil {
+				ipv6, err := r.assignIPv6(instance, cond, ipv6Allocator, osNet, hostname)
+				if err != nil {
+					return err
+				}
+				reservations[len(reservations)-1].IPv6 = ipv6
+			}
+
 			common.LogForObject(
 				r,
 				fmt.Sprintf("Created new reservation for host %s in network %s with IP %s",
@@ -1449,6 +1552,181 @@ func (r *OpenStackNetConfigReconciler) ensureIPs(
 	return nil
 }
 
+// getIPAllocator - get an allocator for the allocation range of the network without the exclude ranges and
+// with the reservations marked as used
+func (r *OpenStackNetConfigReconciler) getIPAllocator(
+	instance *ospdirectorv1beta1.OpenStackNetConfig,
+	cond *shared.Condition,
+	cidr string,
+	allocationStart string,
+	allocationEnd string,
+	excludeRanges []string,
+	reservations []ospdirectorv1beta1.IPReservation,
+) (*common.IPAllocator, error) {
+	_, ipnet, err := net.ParseCIDR(cidr)
+	if err != nil {
+		cond.Message = fmt.Sprintf("Failed to parse CIDR %s", cidr)
+		cond.Reason = shared.CommonCondReasonCIDRParseError
+		cond.Type = shared.NetConfigError
+		err = common.WrapErrorForObject(cond.Message, instance, err)
+
+		return nil, err
+	}
+
+	allocator, err := common.NewIPAllocator(
+		*ipnet,
+		net.ParseIP(allocationStart),
+		net.ParseIP(allocationEnd),
+		excludeRanges,
+	)
+	if err != nil {
+		cond.Message = fmt.Sprintf("Failed to get the allocation range of CIDR %s", cidr)
+		cond.Reason = shared.NetConfigCondReasonIPReservationError
+		cond.Type = shared.NetConfigError
+		err = common.WrapErrorForObject(cond.Message, instance, err)
+
+		return nil, err
+	}
+
+	for _, reservation := range reservations {
+		allocator.Reserve(net.ParseIP(reservation.IP))
+	}
+
+	return allocator, nil
+}
+
+// assignIPv6 - get the next free IPv6 address for the host on a dual-stack network
+func (r *OpenStackNetConfigReconciler) assignIPv6(
+	instance *ospdirectorv1beta1.OpenStackNetConfig,
+	cond *shared.Condition,
+	allocator *common.IPAllocator,
+	osNet *ospdirectorv1beta1.OpenStackNet,
+	hostname string,
+) (string, error) {
+	ip, err := allocator.Allocate()
+	if err != nil {
+		cond.Message = fmt.Sprintf("Failed to do IPv6 reservation: %s", hostname)
+		cond.Reason = shared.NetConfigCondReasonIPReservationError
+		cond.Type = shared.NetConfigError
+		err = common.WrapErrorForObject(cond.Message, instance, err)
+
+		return "", err
+	}
+
+	common.LogForObject(
+		r,
+		fmt.Sprintf("Created new IPv6 reservation for host %s in network %s with IP %s",
+			hostname,
+			osNet.Spec.NameLower,
+			ip.String()),
+		instance,
+	)
+
+	return ip.String(), nil
+}
+
+// ensureDNSRecords - create or update the DNS records ConfigMap, and the external-dns DNSEndpoint
+// if enabled, from the IPs of the hosts. Records of removed hosts get dropped with the next update.
+func (r *OpenStackNetConfigReconciler) ensureDNSRecords(
+	ctx context.Context,
+	instance *ospdirectorv1beta1.OpenStackNetConfig,
+	cond *shared.Condition,
+	domains map[string]string,
+) error {
+	name := instance.Name + openstacknetconfig.DNSRecordsConfigMapSuffix
+	ttl := instance.Spec.DNSRecords.TTL
+	if ttl == 0 {
+		ttl = ospdirectorv1beta1.DefaultDNSRecordTTL
+	}
+
+	records := openstacknetconfig.GetDNSRecords(instance.Status.Hosts, domains)
+
+	cms := []common.Template{
+		{
+			Name:         name,
+			Namespace:    instance.Namespace,
+			Type:         common.TemplateTypeNone,
+			InstanceType: instance.Kind,
+			CustomData:   openstacknetconfig.GetDNSRecordsConfigMapData(records, ttl),
+			Labels:       common.GetLabels(instance, openstacknetconfig.AppLabel, map[string]string{}),
+		},
+	}
+
+	err := common.EnsureConfigMaps(ctx, r, instance, cms, &map[string]common.EnvSetter{})
+	if err != nil {
+		cond.Message = fmt.Sprintf("Error creating/updating DNS records config map for %s %s", instance.Kind, instance.Name)
+		cond.Reason = shared.NetConfigCondReasonDNSRecordsError
+		cond.Type = shared.NetConfigError
+		err = common.WrapErrorForObject(cond.Message, instance, err)
+
+		return err
+	}
+
+	dnsEndpoint := &unstructured.Unstructured{}
+	dnsEndpoint.SetGroupVersionKind(openstacknetconfig.DNSEndpointGVK)
+	dnsEndpoint.SetName(name)
+	dnsEndpoint.SetNamespace(instance.Namespace)
+
+	//
+	// remove the DNSEndpoint if external-dns got disabled, or its CRD is not installed
+	//
+	if !instance.Spec.DNSRecords.ExternalDNS {
+		err := r.Get(ctx, types.NamespacedName{Name: name, Namespace: instance.Namespace}, dnsEndpoint)
+		if err != nil {
+			if k8s_errors.IsNotFound(err) || meta.IsNoMatchError(err) {
+				return nil
+			}
+			cond.Message = fmt.Sprintf("Error getting DNSEndpoint %s", name)
+			cond.Reason = shared.NetConfigCondReasonDNSRecordsError
+			cond.Type = shared.NetConfigError
+			err = common.WrapErrorForObject(cond.Message, instance, err)
+
+			return err
+		}
+
+		err = r.Delete(ctx, dnsEndpoint)
+		if err != nil && !k8s_errors.IsNotFound(err) {
+			cond.Message = fmt.Sprintf("Error deleting DNSEndpoint %s", name)
+			cond.Reason = shared.NetConfigCondReasonDNSRecordsError
+			cond.Type = shared.NetConfigError
+			err = common.WrapErrorForObject(cond.Message, instance, err)
+
+			return err
+		}
+		common.LogForObject(r, fmt.Sprintf("DNSEndpoint %s deleted", name), instance)
+
+		return nil
+	}
+
+	op, err := controllerutil.CreateOrUpdate(ctx, r.GetClient(), dnsEndpoint, func() error {
+		dnsEndpoint.SetLabels(labels.Merge(dnsEndpoint.GetLabels(), common.GetLabels(instance, openstacknetconfig.AppLabel, map[string]string{})))
+
+		err := unstructured.SetNestedSlice(dnsEndpoint.Object, openstacknetconfig.GetDNSEndpoints(records, ttl), "spec", "endpoints")
+		if err != nil {
+			return err
+		}
+
+		return controllerutil.SetControllerReference(instance, dnsEndpoint, r.Scheme)
+	})
+	if err != nil {
+		cond.Message = fmt.Sprintf("Error creating/updating DNSEndpoint %s", name)
+		cond.Reason = shared.NetConfigCondReasonDNSRecordsError
+		cond.Type = shared.NetConfigError
+		err = common.WrapErrorForObject(cond.Message, instance, err)
+
+		return err
+	}
+	if op != controllerutil.OperationResultNone {
+		common.LogForObject(
+			r,
+			fmt.Sprintf("DNSEndpoint %s successfully reconciled - operation: %s", name, string(op)),
+			instance,
+		)
+	}
+
+	return nil
+}
+
 // getNetDesiredCount - get the total of all networks subnets
 func (r *OpenStackNetConfigReconciler) getNetDesiredCount(
 	networks []ospdirectorv1beta1.Network,
@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
@@ -28,7 +29,6 @@ import (
 	"github.com/go-logr/logr"
 	"k8s.io/apimachinery/pkg/api/equality"
 	k8s_errors "k8s.io/apimachinery/pkg/api/errors"
-	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes"
@@ -38,19 +38,25 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/config"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	networkv1 "github.com/k8snetworkplumbingwg/network-attachment-definition-client/pkg/apis/k8s.cni.cncf.io/v1"
 	"github.com/openstack-k8s-operators/osp-director-operator/api/shared"
 	ospdirectorv1beta1 "github.com/openstack-k8s-operators/osp-director-operator/api/v1beta1"
 	ospdirectorv1beta2 "github.com/openstack-k8s-operators/osp-director-operator/api/v1beta2"
 	"github.com/openstack-k8s-operators/osp-director-operator/pkg/common"
+	openstackclient "github.com/openstack-k8s-operators/osp-director-operator/pkg/openstackclient"
 	openstackipset "github.com/openstack-k8s-operators/osp-director-operator/pkg/openstackipset"
 	openstacknet "github.com/openstack-k8s-operators/osp-director-operator/pkg/openstacknet"
 	vmset "github.com/openstack-k8s-operators/osp-director-operator/pkg/vmset"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	virtv1 "kubevirt.io/api/core/v1"
 	"kubevirt.io/client-go/kubecli"
+	cdiv1 "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
 	// cdiv1 "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
 	//	virtctl "kubevirt.io/kubevirt/pkg/virtctl/vm"
 )
@@ -101,9 +107,14 @@ func (r *OpenStackVMSetReconciler) GetScheme() *runtime.Scheme {
 // FIXME: Cluster-scope required below for now, as the operator watches openshift-machine-api namespace as well
 // +kubebuilder:rbac:groups=k8s.cni.cncf.io,resources=network-attachment-definitions,verbs=get;list
 // +kubebuilder:rbac:groups=kubevirt.io,namespace=openstack,resources=virtualmachines,verbs=create;delete;get;list;patch;update;watch
-// +kubebuilder:rbac:groups=subresources.kubevirt.io,namespace=openstack,resources=virtualmachines/start,verbs=update
+// +kubebuilder:rbac:groups=subresources.kubevirt.io,namespace=openstack,resources=virtualmachines/start;virtualmachines/restart;virtualmachines/migrate;virtualmachines/addvolume;virtualmachines/removevolume,verbs=update
 // FIXME: Is there a way to scope the following RBAC annotation to just the "openshift-machine-api" namespace?
 // +kubebuilder:rbac:groups=kubevirt.io,resources=virtualmachines,verbs=list;watch
+// +kubebuilder:rbac:groups=kubevirt.io,resources=virtualmachineinstances,verbs=get;list;watch
+// +kubebuilder:rbac:groups=core,resources=nodes,verbs=get;list;watch
+// +kubebuilder:rbac:groups=storage.k8s.io,resources=storageclasses,verbs=get;list;watch
+// +kubebuilder:rbac:groups=batch,namespace=openstack,resources=jobs,verbs=create;delete;get;list;patch;update;watch
+// +kubebuilder:rbac:groups=osp-director.openstack.org,resources=openstackclients,verbs=get;list;watch
 // +kubebuilder:rbac:groups=nmstate.io,resources=nodenetworkconfigurationpolicies,verbs=get;list
 // +kubebuilder:rbac:groups=osp-director.openstack.org,resources=openstacknets,verbs=get;list
 // FIXME: Is there a way to scope the following RBAC annotation to just the "openshift-sriov-network-operator" namespace?
@@ -505,7 +516,521 @@ func (r *OpenStackVMSetReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 		return ctrlResult, err
 	}
 
-	return ctrl.Result{}, nil
+	//
+	//   Expand the disks of the VMs
+	//
+	if err := r.expandDisks(ctx, instance, cond); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	//
+	//   Check the placement of the running VMs
+	//
+	if err := r.checkPlacement(ctx, instance, cond); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	//
+	//   Update the guest-level state of the VMs
+	//
+	if err := r.updateVMStatus(ctx, instance, cond); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	//
+	//   Restart or live migrate the VMs to pick up changes of cores and memory
+	//
+	ctrlResult, err = r.updateVMs(ctx, instance, cond)
+	if (err != nil) || (ctrlResult != ctrl.Result{}) {
+		return ctrlResult, err
+	}
+
+	//
+	//   Verify the fencing credentials can get the status of the VMs
+	//
+	return r.checkFencing(ctx, instance, cond)
+}
+
+// checkFencing - run a job from the openstackclient image which gets the status of each VM with the
+// fencing kubeconfig, like the fence_kubevirt agent of pacemaker does, and set the FencingVerified
+// condition from the result. The check runs once the VMs are provisioned, then every interval and
+// whenever the VMs of the set change.
+func (r *OpenStackVMSetReconciler) checkFencing(
+	ctx context.Context,
+	instance *ospdirectorv1beta2.OpenStackVMSet,
+	cond *shared.Condition,
+) (ctrl.Result, error) {
+	if instance.Spec.FencingCheck == nil {
+		instance.Status.FencingStatus = nil
+		return ctrl.Result{}, nil
+	}
+	if cond.Type != shared.VMSetCondTypeProvisioned {
+		return ctrl.Result{}, nil
+	}
+
+	if instance.Status.FencingStatus == nil {
+		instance.Status.FencingStatus = &ospdirectorv1beta2.OpenStackVMSetFencingStatus{}
+		instance.Status.FencingStatus.Conditions.Set(
+			shared.VMSetCondTypeFencingVerified,
+			corev1.ConditionUnknown,
+			shared.VMSetCondReasonFencingCheckPending,
+			"Fencing health check did not run yet",
+		)
+	}
+	fencingStatus := instance.Status.FencingStatus
+
+	hostnames := []string{}
+	for hostname, host := range instance.Status.VMHosts {
+		if hostname != "" && !host.AnnotatedForDeletion {
+			hostnames = append(hostnames, hostname)
+		}
+	}
+	sort.Strings(hostnames)
+
+	job := &batchv1.Job{}
+	err := r.Get(ctx, types.NamespacedName{Name: fmt.Sprintf("%s-fencing-check", instance.Name), Namespace: instance.Namespace}, job)
+	if err != nil && !k8s_errors.IsNotFound(err) {
+		cond.Message = fmt.Sprintf("Failed to get fencing check job %s-fencing-check", instance.Name)
+		cond.Reason = shared.VMSetCondReasonFencingCheckError
+		cond.Type = shared.CommonCondTypeError
+		err = common.WrapErrorForObject(cond.Message, instance, err)
+
+		return ctrl.Result{}, err
+	}
+
+	//
+	// no check running, start one if it is due
+	//
+	if k8s_errors.IsNotFound(err) {
+		if requeue := vmset.GetFencingCheckRequeue(fencingStatus, hostnames, instance.Spec.FencingCheck.Interval.Duration, time.Now()); requeue > 0 {
+			return ctrl.Result{RequeueAfter: requeue}, nil
+		}
+		if len(hostnames) == 0 {
+			return ctrl.Result{}, nil
+		}
+
+		osc := &ospdirectorv1beta1.OpenStackClient{}
+		if err := r.Get(ctx, types.NamespacedName{Name: openstackclient.Name, Namespace: instance.Namespace}, osc); err != nil {
+			if k8s_errors.IsNotFound(err) {
+				fencingStatus.Conditions.Set(
+					shared.VMSetCondTypeFencingVerified,
+					corev1.ConditionUnknown,
+					shared.VMSetCondReasonFencingCheckPending,
+					"Waiting for the openstackclient to run the fencing health check",
+				)
+
+				return ctrl.Result{RequeueAfter: 60 * time.Second}, nil
+			}
+
+			cond.Message = "Failed to get the openstackclient to run the fencing health check"
+			cond.Reason = shared.VMSetCondReasonFencingCheckError
+			cond.Type = shared.CommonCondTypeError
+			err = common.WrapErrorForObject(cond.Message, instance, err)
+
+			return ctrl.Result{}, err
+		}
+
+		job = vmset.FencingCheckJob(
+			fmt.Sprintf("%s-fencing-check", instance.Name),
+			instance.Namespace,
+			common.GetLabels(instance, vmset.AppLabel, map[string]string{}),
+			osc.Spec.ImageURL,
+			int64(openstackclient.CloudAdminUID),
+			int64(openstackclient.CloudAdminGID),
+			hostnames,
+			instance.Spec.FencingCheck.InsecureSkipTLSVerify,
+		)
+		if err := controllerutil.SetControllerReference(instance, job, r.Scheme); err != nil {
+			cond.Message = fmt.Sprintf("Error set controller reference for %s", job.Name)
+			cond.Reason = shared.CommonCondReasonControllerReferenceError
+			cond.Type = shared.CommonCondTypeError
+			err = common.WrapErrorForObject(cond.Message, instance, err)
+
+			return ctrl.Result{}, err
+		}
+		if err := r.Create(ctx, job); err != nil {
+			cond.Message = fmt.Sprintf("Failed to create fencing check job %s", job.Name)
+			cond.Reason = shared.VMSetCondReasonFencingCheckError
+			cond.Type = shared.CommonCondTypeError
+			err = common.WrapErrorForObject(cond.Message, instance, err)
+
+			return ctrl.Result{}, err
+		}
+		common.LogForObject(r, fmt.Sprintf("Fencing check job %s created", job.Name), instance)
+
+		return ctrl.Result{RequeueAfter: 20 * time.Second}, nil
+	}
+
+	//
+	// check running, wait for the result
+	//
+	requeue, err := common.WaitOnJob(ctx, job, r.Client, r.Log)
+	if err == nil && requeue {
+		return ctrl.Result{RequeueAfter: 20 * time.Second}, nil
+	}
+
+	now := metav1.Now()
+	fencingStatus.LastCheckTime = &now
+	fencingStatus.CheckedVMs = vmset.GetFencingCheckHostnames(job)
+	fencingStatus.FailedVMs = nil
+	if err != nil {
+		pods, listErr := r.Kclient.CoreV1().Pods(instance.Namespace).List(ctx, metav1.ListOptions{
+			LabelSelector: fmt.Sprintf("job-name=%s", job.Name),
+		})
+		if listErr != nil {
+			cond.Message = fmt.Sprintf("Failed to get the pods of fencing check job %s", job.Name)
+			cond.Reason = shared.VMSetCondReasonFencingCheckError
+			cond.Type = shared.CommonCondTypeError
+			listErr = common.WrapErrorForObject(cond.Message, instance, listErr)
+
+			return ctrl.Result{}, listErr
+		}
+		fencingStatus.FailedVMs = vmset.GetFencingCheckFailedVMs(pods.Items)
+
+		message := fmt.Sprintf("Fencing credentials can not get the status of VMs %s", strings.Join(fencingStatus.FailedVMs, ","))
+		if len(fencingStatus.FailedVMs) == 0 {
+			message = fmt.Sprintf("Fencing health check job %s failed, check the logs of the job", job.Name)
+		}
+		fencingStatus.Conditions.Set(
+			shared.VMSetCondTypeFencingVerified,
+			corev1.ConditionFalse,
+			shared.VMSetCondReasonFencingCheckFailed,
+			message,
+		)
+		common.LogForObject(r, message, instance)
+	} else {
+		fencingStatus.Conditions.Set(
+			shared.VMSetCondTypeFencingVerified,
+			corev1.ConditionTrue,
+			shared.VMSetCondReasonFencingVerified,
+			fmt.Sprintf("Fencing credentials can get the status of VMs %s", strings.Join(fencingStatus.CheckedVMs, ",")),
+		)
+	}
+
+	if _, err := common.DeleteJob(ctx, job, r.Kclient, r.Log); err != nil {
+		cond.Message = fmt.Sprintf("Failed to delete fencing check job %s", job.Name)
+		cond.Reason = shared.VMSetCondReasonFencingCheckError
+		cond.Type = shared.CommonCondTypeError
+		err = common.WrapErrorForObject(cond.Message, instance, err)
+
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{RequeueAfter: instance.Spec.FencingCheck.Interval.Duration}, nil
+}
+
+// updateVMs - restart or live migrate the running VMs one at a time until all run with
+// the requested cores and memory. Before moving on to the next VM, all VMIs of the set
+// must be running with the guest agent connected.
+func (r *OpenStackVMSetReconciler) updateVMs(
+	ctx context.Context,
+	instance *ospdirectorv1beta2.OpenStackVMSet,
+	cond *shared.Condition,
+) (ctrl.Result, error) {
+	virtualMachineInstanceList, err := common.GetVirtualMachineInstances(
+		ctx,
+		r,
+		instance.Namespace,
+		map[string]string{
+			common.OwnerNameLabelSelector: instance.Name,
+		},
+	)
+	if err != nil {
+		cond.Message = "Failed to get list of VirtualMachineInstances"
+		cond.Reason = shared.VMSetCondReasonKubevirtError
+		cond.Type = shared.CommonCondTypeError
+		err = common.WrapErrorForObject(cond.Message, instance, err)
+
+		return ctrl.Result{}, err
+	}
+
+	vmis := map[string]*virtv1.VirtualMachineInstance{}
+	for idx := range virtualMachineInstanceList.Items {
+		vmis[virtualMachineInstanceList.Items[idx].Name] = &virtualMachineInstanceList.Items[idx]
+	}
+
+	hostnames := []string{}
+	for hostname, host := range instance.Status.VMHosts {
+		if hostname != "" && !host.AnnotatedForDeletion {
+			hostnames = append(hostnames, hostname)
+		}
+	}
+	sort.Strings(hostnames)
+
+	updateStatus := &instance.Status.UpdateStatus
+	updateStatus.UpdatedVMs = nil
+	updateStatus.PendingVMs = nil
+	settled := true
+	for _, hostname := range hostnames {
+		vmi, ok := vmis[hostname]
+		if !ok || !vmset.IsVMISettled(vmi) {
+			settled = false
+		}
+
+		if ok && vmset.IsVMIUpToDate(vmi, instance.Spec.Cores, instance.Spec.Memory, instance.Spec.Firmware, instance.Spec.Performance, instance.Spec.Watchdog) {
+			updateStatus.UpdatedVMs = append(updateStatus.UpdatedVMs, hostname)
+		} else {
+			updateStatus.PendingVMs = append(updateStatus.PendingVMs, hostname)
+		}
+	}
+
+	strategy := vmset.GetUpdateStrategyType(instance.Spec.UpdateStrategy)
+	if strategy == ospdirectorv1beta2.VMUpdateStrategyManual ||
+		(len(updateStatus.PendingVMs) == 0 && updateStatus.CurrentVM == "") ||
+		(updateStatus.CurrentVM != "" && !slices.Contains(hostnames, updateStatus.CurrentVM)) {
+		updateStatus.CurrentVM = ""
+		updateStatus.CurrentAction = ""
+		updateStatus.CurrentActionRef = ""
+
+		return ctrl.Result{}, nil
+	}
+
+	// wait for VMs getting provisioned, or the restarted VM getting ready again
+	if instance.Status.ProvisioningStatus.ReadyCount != instance.Spec.VMCount {
+		return ctrl.Result{}, nil
+	}
+
+	timeout := 20
+
+	//
+	// check if the action on the current VM finished
+	//
+	if updateStatus.CurrentVM != "" {
+		vmi, ok := vmis[updateStatus.CurrentVM]
+
+		finished := false
+		if ok && vmset.IsVMISettled(vmi) {
+			switch updateStatus.CurrentAction {
+			case ospdirectorv1beta2.VMUpdateStrategyRestart:
+				finished = string(vmi.UID) != updateStatus.CurrentActionRef
+			case ospdirectorv1beta2.VMUpdateStrategyLiveMigrate:
+				finished = vmset.GetVMIMigrationUID(vmi) != updateStatus.CurrentActionRef
+			default:
+				finished = true
+			}
+		}
+
+		if !finished {
+			cond.Message = fmt.Sprintf("%s of VirtualMachine %s in progress, waiting for it to be running with guest agent connected",
+				updateStatus.CurrentAction,
+				updateStatus.CurrentVM,
+			)
+			cond.Reason = shared.VMSetCondReasonVirtualMachineUpdating
+			cond.Type = shared.VMSetCondTypeProvisioning
+
+			return ctrl.Result{RequeueAfter: time.Duration(timeout) * time.Second}, nil
+		}
+
+		if !vmset.IsVMIUpToDate(vmi, instance.Spec.Cores, instance.Spec.Memory, instance.Spec.Firmware, instance.Spec.Performance, instance.Spec.Watchdog) &&
+			updateStatus.CurrentAction == ospdirectorv1beta2.VMUpdateStrategyLiveMigrate {
+			common.LogForObject(
+				r,
+				fmt.Sprintf("VirtualMachine %s still runs with outdated cores/memory/firmware/performance/watchdog settings after live migration, restarting it", vmi.Name),
+				instance,
+			)
+
+			return r.vmUpdateAction(ctx, instance, cond, vmi, ospdirectorv1beta2.VMUpdateStrategyRestart)
+		}
+
+		common.LogForObject(
+			r,
+			fmt.Sprintf("VirtualMachine %s %s finished", updateStatus.CurrentVM, updateStatus.CurrentAction),
+			instance,
+		)
+		updateStatus.CurrentVM = ""
+		updateStatus.CurrentAction = ""
+		updateStatus.CurrentActionRef = ""
+	}
+
+	if len(updateStatus.PendingVMs) == 0 {
+		return ctrl.Result{}, nil
+	}
+
+	//
+	// keep the quorum of the VMs in the set, only continue when all of them are up
+	//
+	if !settled {
+		cond.Message = fmt.Sprintf("Waiting for all VirtualMachines to be running with guest agent connected to update %s",
+			strings.Join(updateStatus.PendingVMs, ","),
+		)
+		cond.Reason = shared.VMSetCondReasonVirtualMachineUpdating
+		cond.Type = shared.VMSetCondTypeProvisioning
+
+		return ctrl.Result{RequeueAfter: time.Duration(timeout) * time.Second}, nil
+	}
+
+	vmi := vmis[updateStatus.PendingVMs[0]]
+	action := strategy
+	if action == ospdirectorv1beta2.VMUpdateStrategyLiveMigrate {
+		vm := &virtv1.VirtualMachine{}
+		if err := r.Get(ctx, types.NamespacedName{Name: vmi.Name, Namespace: vmi.Namespace}, vm); err != nil {
+			cond.Message = fmt.Sprintf("Failed to get VirtualMachine %s", vmi.Name)
+			cond.Reason = shared.VMSetCondReasonVirtualMachineGetError
+			cond.Type = shared.CommonCondTypeError
+			err = common.WrapErrorForObject(cond.Message, instance, err)
+
+			return ctrl.Result{}, err
+		}
+
+		if !vmset.IsVMILiveMigratable(vmi) || vmset.IsVMRestartRequired(vm) {
+			common.LogForObject(
+				r,
+				fmt.Sprintf("VirtualMachine %s can not pick up the changes with a live migration, restarting it", vmi.Name),
+				instance,
+			)
+			action = ospdirectorv1beta2.VMUpdateStrategyRestart
+		}
+	}
+
+	return r.vmUpdateAction(ctx, instance, cond, vmi, action)
+}
+
+// vmUpdateAction - restart or live migrate a VM to pick up changes of cores, memory and firmware
+func (r *OpenStackVMSetReconciler) vmUpdateAction(
+	ctx context.Context,
+	instance *ospdirectorv1beta2.OpenStackVMSet,
+	cond *shared.Condition,
+	vmi *virtv1.VirtualMachineInstance,
+	action ospdirectorv1beta2.VMUpdateStrategyType,
+) (ctrl.Result, error) {
+	var err error
+	var ref string
+	switch action {
+	case ospdirectorv1beta2.VMUpdateStrategyLiveMigrate:
+		ref = vmset.GetVMIMigrationUID(vmi)
+		err = r.KubevirtClient.VirtualMachine(vmi.Namespace).Migrate(ctx, vmi.Name, &virtv1.MigrateOptions{})
+	default:
+		ref = string(vmi.UID)
+		err = r.KubevirtClient.VirtualMachine(vmi.Namespace).Restart(ctx, vmi.Name, &virtv1.RestartOptions{})
+	}
+	if err != nil {
+		cond.Message = fmt.Sprintf("Failed to %s VirtualMachine %s", action, vmi.Name)
+		cond.Reason = shared.VMSetCondReasonKubevirtError
+		cond.Type = shared.CommonCondTypeError
+		err = common.WrapErrorForObject(cond.Message, instance, err)
+
+		return ctrl.Result{}, err
+	}
+
+	instance.Status.UpdateStatus.CurrentVM = vmi.Name
+	instance.Status.UpdateStatus.CurrentAction = action
+	instance.Status.UpdateStatus.CurrentActionRef = ref
+
+	cond.Message = fmt.Sprintf("%s of VirtualMachine %s to pick up cores %d, memory %dGB and firmware settings",
+		action,
+		vmi.Name,
+		instance.Spec.Cores,
+		instance.Spec.Memory,
+	)
+	cond.Reason = shared.VMSetCondReasonVirtualMachineUpdating
+	cond.Type = shared.VMSetCondTypeProvisioning
+	common.LogForObject(r, cond.Message, instance)
+
+	return ctrl.Result{RequeueAfter: 20 * time.Second}, nil
+}
+
+// checkPlacement - update the status with the running VMs which break the placement of the set
+func (r *OpenStackVMSetReconciler) checkPlacement(
+	ctx context.Context,
+	instance *ospdirectorv1beta2.OpenStackVMSet,
+	cond *shared.Condition,
+) error {
+	virtualMachineInstanceList, err := common.GetVirtualMachineInstances(
+		ctx,
+		r,
+		instance.Namespace,
+		map[string]string{
+			common.OwnerNameLabelSelector: instance.Name,
+		},
+	)
+	if err != nil {
+		cond.Message = "Failed to get list of VirtualMachineInstances"
+		cond.Reason = shared.VMSetCondReasonKubevirtError
+		cond.Type = shared.CommonCondTypeError
+		err = common.WrapErrorForObject(cond.Message, instance, err)
+
+		return err
+	}
+
+	nodeList := &corev1.NodeList{}
+	if err := r.GetClient().List(ctx, nodeList); err != nil {
+		cond.Message = "Failed to get list of Nodes"
+		cond.Reason = shared.VMSetCondReasonKubevirtError
+		cond.Type = shared.CommonCondTypeError
+		err = common.WrapErrorForObject(cond.Message, instance, err)
+
+		return err
+	}
+
+	warnings := vmset.GetPlacementWarnings(
+		instance.Spec.Placement,
+		instance.Spec.NodeSelector,
+		virtualMachineInstanceList.Items,
+		nodeList.Items,
+	)
+	if len(warnings) == 0 {
+		warnings = nil
+	}
+
+	if !reflect.DeepEqual(instance.Status.PlacementWarnings, warnings) {
+		for _, warning := range warnings {
+			common.LogForObject(r, fmt.Sprintf("Placement of OpenStackVMSet %s violated: %s", instance.Name, warning), instance)
+		}
+		instance.Status.PlacementWarnings = warnings
+	}
+
+	return nil
+}
+
+// updateVMStatus - update the status with the guest-level state of the VMs from their VMIs
+func (r *OpenStackVMSetReconciler) updateVMStatus(
+	ctx context.Context,
+	instance *ospdirectorv1beta2.OpenStackVMSet,
+	cond *shared.Condition,
+) error {
+	virtualMachineInstanceList, err := common.GetVirtualMachineInstances(
+		ctx,
+		r,
+		instance.Namespace,
+		map[string]string{
+			common.OwnerNameLabelSelector: instance.Name,
+		},
+	)
+	if err != nil {
+		cond.Message = "Failed to get list of VirtualMachineInstances"
+		cond.Reason = shared.VMSetCondReasonKubevirtError
+		cond.Type = shared.CommonCondTypeError
+		err = common.WrapErrorForObject(cond.Message, instance, err)
+
+		return err
+	}
+
+	vmis := map[string]*virtv1.VirtualMachineInstance{}
+	for idx := range virtualMachineInstanceList.Items {
+		vmis[virtualMachineInstanceList.Items[idx].Name] = &virtualMachineInstanceList.Items[idx]
+	}
+
+	vmStatus := map[string]ospdirectorv1beta2.OpenStackVMSetVMStatus{}
+	for hostname := range instance.Status.VMHosts {
+		if hostname == "" {
+			continue
+		}
+		status := vmset.GetVMStatus(vmis[hostname])
+
+		previous, ok := instance.Status.VMStatus[hostname]
+		if !ok || previous.Health != status.Health {
+			common.LogForObject(r, fmt.Sprintf("VM %s health: %s", hostname, status.Health), instance)
+		}
+		vmStatus[hostname] = status
+	}
+	if len(vmStatus) == 0 {
+		vmStatus = nil
+	}
+
+	instance.Status.VMStatus = vmStatus
+	instance.Status.Health = vmset.GetHealthStatus(vmStatus)
+
+	return nil
 }
 
 func (r *OpenStackVMSetReconciler) getNormalizedStatus(status *ospdirectorv1beta2.OpenStackVMSetStatus) *ospdirectorv1beta2.OpenStackVMSetStatus {
@@ -519,6 +1044,12 @@ func (r *OpenStackVMSetReconciler) getNormalizedStatus(status *ospdirectorv1beta
 		s.Conditions[idx].LastHeartbeatTime = metav1.Time{}
 		s.Conditions[idx].LastTransitionTime = metav1.Time{}
 	}
+	if s.FencingStatus != nil {
+		for idx := range s.FencingStatus.Conditions {
+			s.FencingStatus.Conditions[idx].LastHeartbeatTime = metav1.Time{}
+			s.FencingStatus.Conditions[idx].LastTransitionTime = metav1.Time{}
+		}
+	}
 
 	return s
 }
@@ -631,6 +1162,11 @@ func (r *OpenStackVMSetReconciler) generateVirtualMachineNetworkData(
 ) error {
 	templateParameters["ControllerIP"] = host.IPAddress
 	templateParameters["CtlplaneInterface"] = instance.Spec.CtlplaneInterface
+	common.SetCtlplaneNetworkTemplateParameters(
+		templateParameters,
+		instance.Spec.CtlplaneInterface,
+		instance.Spec.CtlplaneNetworkConfig,
+	)
 
 	if len(instance.Spec.BootstrapDNS) > 0 {
 		templateParameters["CtlplaneDns"] = instance.Spec.BootstrapDNS
@@ -697,6 +1233,21 @@ outer:
 	for _, route := range ctlPlaneNetwork.Spec.Routes {
 		routes = append(routes, map[string]string{"to": route.Destination, "via": route.Nexthop})
 	}
+
+	//
+	// on a dual-stack ctlplane network add the IPv6 address, gateway and routes
+	//
+	templateParameters["ControllerIPv6"] = ""
+	templateParameters["Gateway6"] = ""
+	if ctlPlaneNetwork.Spec.IPv6 != nil {
+		templateParameters["ControllerIPv6"] = instance.Status.VMHosts[host.Hostname].IPAddresses[ospdirectorv1beta1.IPv6AddressKey(netNameLower)]
+		if ctlPlaneNetwork.Spec.IPv6.Gateway != "" {
+			templateParameters["Gateway6"] = fmt.Sprintf("gateway6: %s", ctlPlaneNetwork.Spec.IPv6.Gateway)
+		}
+		for _, route := range ctlPlaneNetwork.Spec.IPv6.Routes {
+			routes = append(routes, map[string]string{"to": route.Destination, "via": route.Nexthop})
+		}
+	}
 	templateParameters["CtlplaneRoutes"] = routes
 
 	networkdata := []common.Template{
@@ -763,6 +1314,28 @@ func (r *OpenStackVMSetReconciler) virtualMachineFinalizerCleanup(
 
 // SetupWithManager -
 func (r *OpenStackVMSetReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	// the VMIs are owned by the VMs, watch them to pick up changes of the guest-level state
+	vmiWatcher := handler.EnqueueRequestsFromMapFunc(func(_ context.Context, obj client.Object) []reconcile.Request {
+		labels := obj.GetLabels()
+		if labels[common.OwnerControllerNameLabelSelector] != vmset.AppLabel {
+			return nil
+		}
+
+		ownerName, ok := labels[common.OwnerNameLabelSelector]
+		if !ok {
+			return nil
+		}
+
+		return []reconcile.Request{
+			{
+				NamespacedName: types.NamespacedName{
+					Namespace: obj.GetNamespace(),
+					Name:      ownerName,
+				},
+			},
+		}
+	})
+
 	// TODO: Myabe use filtering functions here since some resource permissions
 	// are now cluster-scoped?
 	return ctrl.NewControllerManagedBy(mgr).
@@ -772,6 +1345,7 @@ func (r *OpenStackVMSetReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		Owns(&corev1.PersistentVolumeClaim{}).
 		Owns(&virtv1.VirtualMachine{}).
 		Owns(&ospdirectorv1beta1.OpenStackIPSet{}).
+		Watches(&virtv1.VirtualMachineInstance{}, vmiWatcher).
 		Complete(r)
 }
 
@@ -890,6 +1464,31 @@ func (r *OpenStackVMSetReconciler) vmCreateInstance(
 		}
 	}
 
+	//
+	// KubeVirt rejects changes to the VM while a snapshot or restore is in progress, skip it until finished
+	//
+	if !vm.CreationTimestamp.IsZero() && vmset.IsSnapshotOrRestoreInProgress(vm) {
+		common.LogForObject(r, fmt.Sprintf("VirtualMachine %s snapshot or restore in progress, skipping update", vm.Name), instance)
+
+		return nil
+	}
+
+	// a restore from a snapshot replaces the DataVolume of the root disk
+	rootDataVolumeName := vmset.GetVolumeDataVolumeName(vm, "rootdisk", ctl.DomainNameUniq)
+
+	//
+	// hot plug new additional disks to, and hot unplug removed disks from, an existing VM.
+	// The VM read from the cache does not yet have the volume requests of the disks hot plugged
+	// in this reconcile, remember them to not also add them to the VM template.
+	//
+	hotplugged := map[string]bool{}
+	if !vm.CreationTimestamp.IsZero() {
+		hotplugged, err = r.hotplugDisks(ctx, instance, cond, vm, ctl)
+		if err != nil {
+			return err
+		}
+	}
+
 	op, err := controllerutil.CreateOrPatch(ctx, r.Client, vm, func() error {
 		vm.Labels = shared.MergeStringMaps(
 			vm.GetLabels(),
@@ -905,13 +1504,11 @@ func (r *OpenStackVMSetReconciler) vmCreateInstance(
 		// run on the same worker node. This still allows to
 		// manually migrate instances and they run on the same node.
 		// On the next migration action they get again distributed.
-		vm.Spec.Template.Spec.Affinity = common.DistributePods(
-			common.OwnerNameLabelSelector,
-			[]string{
-				instance.Name,
-			},
-			corev1.LabelHostname,
-		)
+		// With a required anti-affinity in the placement they never
+		// run on the same worker node.
+		vm.Spec.Template.Spec.Affinity = vmset.Affinity(instance.Spec.Placement, instance.Name)
+		vm.Spec.Template.Spec.TopologySpreadConstraints = vmset.TopologySpreadConstraints(instance.Spec.Placement, instance.Name)
+		vm.Spec.Template.Spec.Tolerations = vmset.Tolerations(instance.Spec.Placement)
 
 		if len(instance.Spec.BootstrapDNS) != 0 {
 			vm.Spec.Template.Spec.DNSPolicy = corev1.DNSNone
@@ -923,12 +1520,15 @@ func (r *OpenStackVMSetReconciler) vmCreateInstance(
 			}
 		}
 
-		vm.Spec.Template.Spec.Domain.CPU = &virtv1.CPU{
-			Cores: instance.Spec.Cores,
-		}
+		vm.Spec.Template.Spec.Domain.CPU = vmset.CPU(instance.Spec.Cores, instance.Spec.Performance)
+		vm.Spec.Template.Spec.Domain.Memory = vmset.Memory(vm.Spec.Template.Spec.Domain.Memory, instance.Spec.Performance)
+		vm.Spec.Template.Spec.Domain.Firmware = vmset.Firmware(vm.Spec.Template.Spec.Domain.Firmware, instance.Spec.Firmware)
+		vm.Spec.Template.Spec.Domain.Features = vmset.Features(vm.Spec.Template.Spec.Domain.Features, instance.Spec.Firmware)
+		vm.Spec.Template.Spec.Domain.Devices.TPM = vmset.TPM(instance.Spec.Firmware)
+		vm.Spec.Template.Spec.Domain.Devices.Watchdog = vmset.Watchdog(instance.Spec.Watchdog)
 		vm.Spec.Template.Spec.Domain.Resources = virtv1.ResourceRequirements{
 			Requests: corev1.ResourceList{
-				corev1.ResourceMemory: resource.MustParse(fmt.Sprintf("%dGi", instance.Spec.Memory)),
+				corev1.ResourceMemory: vmset.MemoryRequest(instance.Spec.Memory),
 			},
 		}
 
@@ -1007,8 +1607,8 @@ func (r *OpenStackVMSetReconciler) vmCreateInstance(
 		vm.Spec.DataVolumeTemplates = vmset.MergeVMDataVolumes(
 			vm.Spec.DataVolumeTemplates,
 			vmset.DataVolumeSetterMap{
-				ctl.DomainNameUniq: vmset.DataVolume(
-					ctl.DomainNameUniq,
+				rootDataVolumeName: vmset.DataVolume(
+					rootDataVolumeName,
 					instance.Namespace,
 					instance.Spec.RootDisk.StorageAccessMode,
 					instance.Spec.RootDisk.DiskSize,
@@ -1029,6 +1629,10 @@ func (r *OpenStackVMSetReconciler) vmCreateInstance(
 			common.LogForObject(r, fmt.Sprintf("root disk has StorageAccessMode: %s, setting EvictionStrategy None", instance.Spec.RootDisk.StorageAccessMode), vm)
 			vm.Spec.Template.Spec.EvictionStrategy = ptr.To(virtv1.EvictionStrategyNone)
 		}
+		if !vmset.IsLiveMigratable(instance.Spec.Performance) {
+			common.LogForObject(r, "NUMA guest mapping passthrough enabled, setting EvictionStrategy None", vm)
+			vm.Spec.Template.Spec.EvictionStrategy = ptr.To(virtv1.EvictionStrategyNone)
+		}
 
 		bootDevice := uint(1)
 		vm.Spec.Template.Spec.Domain.Devices.Disks = vmset.MergeVMDisks(
@@ -1063,7 +1667,7 @@ func (r *OpenStackVMSetReconciler) vmCreateInstance(
 			vmset.VolumeSetterMap{
 				"rootdisk": vmset.VolumeSourceDataVolume(
 					"rootdisk",
-					ctl.DomainNameUniq,
+					rootDataVolumeName,
 				),
 				"cloudinitdisk": vmset.VolumeSourceCloudInitNoCloud(
 					"cloudinitdisk",
@@ -1079,13 +1683,24 @@ func (r *OpenStackVMSetReconciler) vmCreateInstance(
 
 		// merge additional disks
 		for _, disk := range instance.Spec.AdditionalDisks {
-			name := strings.ToLower(fmt.Sprintf("%s-%s", ctl.DomainNameUniq, disk.Name))
+			name := vmset.AdditionalDiskName(ctl.DomainNameUniq, disk.Name)
 
+			if disk.StorageAccessMode == "ReadWriteOnce" {
+				common.LogForObject(r, fmt.Sprintf("disk %s has StorageAccessMode: %s, setting EvictionStrategy None", disk.Name, disk.StorageAccessMode), vm)
+				vm.Spec.Template.Spec.EvictionStrategy = ptr.To(virtv1.EvictionStrategyNone)
+			}
+
+			// hot plugged disks are managed by kubevirt
+			if hotplugged[name] || vmset.IsHotpluggedVolume(vm, name) {
+				continue
+			}
+
+			dataVolumeName := vmset.GetVolumeDataVolumeName(vm, name, name)
 			vm.Spec.DataVolumeTemplates = vmset.MergeVMDataVolumes(
 				vm.Spec.DataVolumeTemplates,
 				vmset.DataVolumeSetterMap{
-					name: vmset.DataVolume(
-						name,
+					dataVolumeName: vmset.DataVolume(
+						dataVolumeName,
 						instance.Namespace,
 						disk.StorageAccessMode,
 						disk.DiskSize,
@@ -1115,66 +1730,270 @@ func (r *OpenStackVMSetReconciler) vmCreateInstance(
 				vmset.VolumeSetterMap{
 					name: vmset.VolumeSourceDataVolume(
 						name,
-						name,
+						dataVolumeName,
 					),
 				},
 			)
+		}
 
-			if disk.StorageAccessMode == "ReadWriteOnce" {
-				common.LogForObject(r, fmt.Sprintf("disk %s has StorageAccessMode: %s, setting EvictionStrategy None", disk.Name, disk.StorageAccessMode), vm)
-				vm.Spec.Template.Spec.EvictionStrategy = ptr.To(virtv1.EvictionStrategyNone)
+		// detach removed disks which are not hot plugged, the change applies on the next restart of the VM.
+		// The DataVolume of the disk is kept until the VM gets deleted.
+		for _, name := range vmset.GetRemovedAdditionalDisks(vm, ctl.DomainNameUniq, instance.Spec.AdditionalDisks) {
+			if !vmset.IsHotpluggedVolume(vm, name) {
+				common.LogForObject(r, fmt.Sprintf("disk %s removed from VirtualMachine %s, detached on next restart", name, vm.Name), instance)
+				vmset.RemoveVMDisk(vm, name)
+			}
+		}
+
+		err := controllerutil.SetControllerReference(instance, vm, r.Scheme)
+		if err != nil {
+			cond.Message = fmt.Sprintf("Error set controller reference for %s", vm.Name)
+			cond.Reason = shared.CommonCondReasonControllerReferenceError
+			cond.Type = shared.CommonCondTypeError
+			err = common.WrapErrorForObject(cond.Message, instance, err)
+
+			return err
+		}
+
+		return nil
+	})
+	if err != nil {
+		cond.Message = fmt.Sprintf("Error create/update %s VM %s", vm.Kind, vm.Name)
+		cond.Reason = shared.VMSetCondReasonKubevirtError
+		cond.Type = shared.CommonCondTypeError
+		err = common.WrapErrorForObject(cond.Message, instance, err)
+		common.LogForObject(r, fmt.Sprintf("%s: %+v", cond.Message, vm), vm)
+
+		return err
+	}
+
+	if op != controllerutil.OperationResultNone {
+		if op == controllerutil.OperationResultCreated {
+			// start VM once when it gets created
+			if err := r.KubevirtClient.VirtualMachine(instance.Namespace).Start(ctx, vm.Name, &virtv1.StartOptions{}); err != nil {
+				return common.WrapErrorForObject(
+					fmt.Sprintf("VirtualMachine %s ERROR start after initial create: %s", vm.Name, err.Error()),
+					instance,
+					err,
+				)
+			}
+		}
+		common.LogForObject(
+			r,
+			fmt.Sprintf("VirtualMachine %s successfully reconciled - operation: %s", vm.Name, string(op)),
+			instance,
+		)
+	}
+
+	hostStatus := instance.Status.VMHosts[ctl.Hostname]
+
+	if vm.Status.Ready {
+		hostStatus.ProvisioningState = shared.ProvisioningState(shared.VMSetCondTypeProvisioned)
+	} else if vm.Status.Created {
+		hostStatus.ProvisioningState = shared.ProvisioningState(shared.VMSetCondTypeProvisioning)
+	}
+
+	instance.Status.VMHosts[ctl.Hostname] = hostStatus
+
+	return nil
+}
+
+// hotplugDisks - hot plug new additional disks to a running VM and hot unplug removed hot plugged disks.
+// New disks which can not be hot plugged get added to the VM template and are attached on the next restart.
+// Returns the names of the disks hot plugged by this call.
+func (r *OpenStackVMSetReconciler) hotplugDisks(
+	ctx context.Context,
+	instance *ospdirectorv1beta2.OpenStackVMSet,
+	cond *shared.Condition,
+	vm *virtv1.VirtualMachine,
+	ctl *ospdirectorv1beta1.Host,
+) (map[string]bool, error) {
+	hotplugged := map[string]bool{}
+
+	if vm.Status.PrintableStatus == virtv1.VirtualMachineStatusRunning {
+		for _, disk := range instance.Spec.AdditionalDisks {
+			name := vmset.AdditionalDiskName(ctl.DomainNameUniq, disk.Name)
+			if vmset.HasVolume(vm, name) || vmset.IsHotpluggedVolume(vm, name) {
+				continue
+			}
+
+			pvc, _, err := common.CreateOrUpdatePvc(ctx, r, vm, &common.Pvc{
+				Name:         name,
+				Namespace:    vm.Namespace,
+				Size:         fmt.Sprintf("%dGi", disk.DiskSize),
+				Labels:       common.GetLabels(instance, vmset.AppLabel, map[string]string{}),
+				StorageClass: disk.StorageClass,
+				AccessMode: []corev1.PersistentVolumeAccessMode{
+					corev1.PersistentVolumeAccessMode(disk.StorageAccessMode),
+				},
+				VolumeMode: disk.StorageVolumeMode,
+			})
+			if err != nil {
+				cond.Message = fmt.Sprintf("Failed to create persitent volume claim %s", name)
+				cond.Reason = shared.VMSetCondReasonPersitentVolumeClaimError
+				cond.Type = shared.CommonCondTypeError
+				err = common.WrapErrorForObject(cond.Message, instance, err)
+
+				return hotplugged, err
+			}
+
+			err = r.KubevirtClient.VirtualMachine(vm.Namespace).AddVolume(ctx, vm.Name, vmset.HotplugVolumeOptions(name))
+			if err != nil {
+				common.LogForObject(
+					r,
+					fmt.Sprintf("Hot plug of disk %s to VirtualMachine %s failed, it gets attached on the next restart: %s", name, vm.Name, err.Error()),
+					instance,
+				)
+
+				// the disk gets added to the VM template, which creates its DataVolume
+				if err := r.Delete(ctx, pvc); err != nil && !k8s_errors.IsNotFound(err) {
+					cond.Message = fmt.Sprintf("Failed to delete persitent volume claim %s", name)
+					cond.Reason = shared.VMSetCondReasonPersitentVolumeClaimError
+					cond.Type = shared.CommonCondTypeError
+					err = common.WrapErrorForObject(cond.Message, instance, err)
+
+					return hotplugged, err
+				}
+
+				continue
 			}
+
+			hotplugged[name] = true
+			common.LogForObject(r, fmt.Sprintf("Disk %s hot plugged to VirtualMachine %s", name, vm.Name), instance)
+		}
+	}
+
+	for _, name := range vmset.GetRemovedAdditionalDisks(vm, ctl.DomainNameUniq, instance.Spec.AdditionalDisks) {
+		if !vmset.IsHotpluggedVolume(vm, name) || vmset.IsHotunplugRequested(vm, name) {
+			continue
+		}
+
+		err := r.KubevirtClient.VirtualMachine(vm.Namespace).RemoveVolume(ctx, vm.Name, &virtv1.RemoveVolumeOptions{Name: name})
+		if err != nil {
+			cond.Message = fmt.Sprintf("Failed to hot unplug disk %s from VirtualMachine %s", name, vm.Name)
+			cond.Reason = shared.VMSetCondReasonKubevirtError
+			cond.Type = shared.CommonCondTypeError
+			err = common.WrapErrorForObject(cond.Message, instance, err)
+
+			return hotplugged, err
 		}
 
-		err := controllerutil.SetControllerReference(instance, vm, r.Scheme)
-		if err != nil {
-			cond.Message = fmt.Sprintf("Error set controller reference for %s", vm.Name)
-			cond.Reason = shared.CommonCondReasonControllerReferenceError
-			cond.Type = shared.CommonCondTypeError
-			err = common.WrapErrorForObject(cond.Message, instance, err)
+		common.LogForObject(r, fmt.Sprintf("Disk %s hot unplugged from VirtualMachine %s", name, vm.Name), instance)
+	}
+
+	return hotplugged, nil
+}
+
+// expandDisks - expand the PVCs of the VM disks to the requested disk size and update the disk status
+func (r *OpenStackVMSetReconciler) expandDisks(
+	ctx context.Context,
+	instance *ospdirectorv1beta2.OpenStackVMSet,
+	cond *shared.Condition,
+) error {
+	allowVolumeExpansion := map[string]bool{}
+	diskStatus := map[string][]ospdirectorv1beta2.OpenStackVMSetDiskStatus{}
+
+	for hostname, host := range instance.Status.VMHosts {
+		if hostname == "" || host.AnnotatedForDeletion {
+			continue
+		}
+
+		vm := &virtv1.VirtualMachine{}
+		if err := r.Get(ctx, types.NamespacedName{Name: hostname, Namespace: instance.Namespace}, vm); err != nil {
+			if k8s_errors.IsNotFound(err) {
+				continue
+			}
+			cond.Message = fmt.Sprintf("Failed to get VirtualMachine %s", hostname)
+			cond.Reason = shared.VMSetCondReasonVirtualMachineGetError
+			cond.Type = shared.CommonCondTypeError
+			err = common.WrapErrorForObject(cond.Message, instance, err)
+
+			return err
+		}
+
+		domainNameUniq := fmt.Sprintf("%s-%s", hostname, instance.UID[0:4])
+		disks := map[string]uint32{
+			"rootdisk": instance.Spec.RootDisk.DiskSize,
+		}
+		pvcNames := map[string]string{
+			"rootdisk": vmset.GetVolumeDataVolumeName(vm, "rootdisk", domainNameUniq),
+		}
+		for _, disk := range instance.Spec.AdditionalDisks {
+			name := vmset.AdditionalDiskName(domainNameUniq, disk.Name)
+			disks[disk.Name] = disk.DiskSize
+			pvcNames[disk.Name] = vmset.GetVolumeDataVolumeName(vm, name, name)
+		}
+
+		for diskName, diskSize := range disks {
+			pvc := &corev1.PersistentVolumeClaim{}
+			err := r.Get(ctx, types.NamespacedName{Name: pvcNames[diskName], Namespace: instance.Namespace}, pvc)
+			if err != nil {
+				if k8s_errors.IsNotFound(err) {
+					continue
+				}
+				cond.Message = fmt.Sprintf("Failed to get persitent volume claim %s", pvcNames[diskName])
+				cond.Reason = shared.VMSetCondReasonPersitentVolumeClaimError
+				cond.Type = shared.CommonCondTypeError
+				err = common.WrapErrorForObject(cond.Message, instance, err)
+
+				return err
+			}
 
-			return err
-		}
+			requested := vmset.DiskSize(diskSize)
+			request := pvc.Spec.Resources.Requests[corev1.ResourceStorage]
 
-		return nil
-	})
-	if err != nil {
-		cond.Message = fmt.Sprintf("Error create/update %s VM %s", vm.Kind, vm.Name)
-		cond.Reason = shared.VMSetCondReasonKubevirtError
-		cond.Type = shared.CommonCondTypeError
-		err = common.WrapErrorForObject(cond.Message, instance, err)
-		common.LogForObject(r, fmt.Sprintf("%s: %+v", cond.Message, vm), vm)
+			storageClassName := ""
+			if pvc.Spec.StorageClassName != nil {
+				storageClassName = *pvc.Spec.StorageClassName
+			}
+			if _, ok := allowVolumeExpansion[storageClassName]; !ok && request.Cmp(requested) < 0 {
+				storageClass := &storagev1.StorageClass{}
+				err := r.Get(ctx, types.NamespacedName{Name: storageClassName}, storageClass)
+				if err != nil && !k8s_errors.IsNotFound(err) {
+					cond.Message = fmt.Sprintf("Failed to get storage class %s", storageClassName)
+					cond.Reason = shared.VMSetCondReasonPersitentVolumeClaimError
+					cond.Type = shared.CommonCondTypeError
+					err = common.WrapErrorForObject(cond.Message, instance, err)
+
+					return err
+				}
+				allowVolumeExpansion[storageClassName] = storageClass.AllowVolumeExpansion != nil && *storageClass.AllowVolumeExpansion
+			}
 
-		return err
-	}
+			if request.Cmp(requested) < 0 && allowVolumeExpansion[storageClassName] {
+				patch := client.MergeFrom(pvc.DeepCopy())
+				pvc.Spec.Resources.Requests[corev1.ResourceStorage] = requested
+				if err := r.Patch(ctx, pvc, patch); err != nil {
+					cond.Message = fmt.Sprintf("Failed to expand persitent volume claim %s to %s", pvc.Name, requested.String())
+					cond.Reason = shared.VMSetCondReasonPersitentVolumeClaimError
+					cond.Type = shared.CommonCondTypeError
+					err = common.WrapErrorForObject(cond.Message, instance, err)
 
-	if op != controllerutil.OperationResultNone {
-		if op == controllerutil.OperationResultCreated {
-			// start VM once when it gets created
-			if err := r.KubevirtClient.VirtualMachine(instance.Namespace).Start(ctx, vm.Name, &virtv1.StartOptions{}); err != nil {
-				return common.WrapErrorForObject(
-					fmt.Sprintf("VirtualMachine %s ERROR start after initial create: %s", vm.Name, err.Error()),
-					instance,
-					err,
-				)
+					return err
+				}
+				common.LogForObject(r, fmt.Sprintf("Expanding persitent volume claim %s to %s", pvc.Name, requested.String()), instance)
 			}
-		}
-		common.LogForObject(
-			r,
-			fmt.Sprintf("VirtualMachine %s successfully reconciled - operation: %s", vm.Name, string(op)),
-			instance,
-		)
-	}
 
-	hostStatus := instance.Status.VMHosts[ctl.Hostname]
+			capacity := pvc.Status.Capacity[corev1.ResourceStorage]
+			diskStatus[hostname] = append(diskStatus[hostname], ospdirectorv1beta2.OpenStackVMSetDiskStatus{
+				Name:                  diskName,
+				PersistentVolumeClaim: pvc.Name,
+				RequestedSize:         requested.String(),
+				Capacity:              capacity.String(),
+				ExpansionState:        vmset.GetDiskExpansionState(pvc, requested, allowVolumeExpansion[storageClassName]),
+				Hotplugged:            vmset.IsHotpluggedVolume(vm, pvc.Name),
+			})
+		}
 
-	if vm.Status.Ready {
-		hostStatus.ProvisioningState = shared.ProvisioningState(shared.VMSetCondTypeProvisioned)
-	} else if vm.Status.Created {
-		hostStatus.ProvisioningState = shared.ProvisioningState(shared.VMSetCondTypeProvisioning)
+		sort.Slice(diskStatus[hostname], func(i, j int) bool {
+			return diskStatus[hostname][i].Name < diskStatus[hostname][j].Name
+		})
 	}
 
-	instance.Status.VMHosts[ctl.Hostname] = hostStatus
+	if len(diskStatus) == 0 {
+		diskStatus = nil
+	}
+	instance.Status.DiskStatus = diskStatus
 
 	return nil
 }
@@ -1237,6 +2056,18 @@ func (r *OpenStackVMSetReconciler) createCloudInitSecret(
 		},
 	}
 
+	//
+	// merge the site specific cloud-init parts into the rendered userdata
+	//
+	if len(instance.Spec.CloudInitParts) > 0 {
+		userData, err := r.getCloudInitUserData(ctx, instance, cond, cloudinit[0])
+		if err != nil {
+			return err
+		}
+		cloudinit[0].AdditionalTemplate = nil
+		cloudinit[0].CustomData = map[string]string{"userdata": userData}
+	}
+
 	err := common.EnsureSecrets(ctx, r, instance, cloudinit, &envVars)
 	if err != nil {
 		cond.Message = fmt.Sprintf("Error creating CloudInitSecret secret for the vmset %s", instance.Name)
@@ -1249,6 +2080,46 @@ func (r *OpenStackVMSetReconciler) createCloudInitSecret(
 	return nil
 }
 
+// getCloudInitUserData - render the userdata template and merge the cloud-init parts of the vmset
+func (r *OpenStackVMSetReconciler) getCloudInitUserData(
+	ctx context.Context,
+	instance *ospdirectorv1beta2.OpenStackVMSet,
+	cond *shared.Condition,
+	userDataTemplate common.Template,
+) (string, error) {
+	parts, err := common.GetCloudInitParts(ctx, r, instance.Namespace, instance.Spec.CloudInitParts)
+	if err != nil {
+		cond.Message = fmt.Sprintf("Error getting cloud-init parts for the vmset %s", instance.Name)
+		cond.Reason = shared.CommonCondReasonCloudInitPartError
+		cond.Type = shared.CommonCondTypeError
+		err = common.WrapErrorForObject(cond.Message, instance, err)
+
+		return "", err
+	}
+
+	renderedData, err := common.GetTemplateData(userDataTemplate)
+	if err != nil {
+		cond.Message = fmt.Sprintf("Error rendering CloudInitSecret userdata for the vmset %s", instance.Name)
+		cond.Reason = shared.VMSetCondReasonCloudInitSecretError
+		cond.Type = shared.CommonCondTypeError
+		err = common.WrapErrorForObject(cond.Message, instance, err)
+
+		return "", err
+	}
+
+	userData, err := common.MergeCloudInitUserData(renderedData["userdata"], "userdata", parts)
+	if err != nil {
+		cond.Message = fmt.Sprintf("Error merging cloud-init parts into the userdata for the vmset %s", instance.Name)
+		cond.Reason = shared.CommonCondReasonCloudInitPartError
+		cond.Type = shared.CommonCondTypeError
+		err = common.WrapErrorForObject(cond.Message, instance, err)
+
+		return "", err
+	}
+
+	return userData, nil
+}
+
 // NetworkAttachmentDefinition, SriovNetwork and SriovNetworkNodePolicy
 func (r *OpenStackVMSetReconciler) verifyNetworkAttachments(
 	ctx context.Context,
@@ -1449,6 +2320,16 @@ func (r *OpenStackVMSetReconciler) createBaseImage(
 		baseImageName = instance.Spec.RootDisk.BaseImageVolumeName
 	}
 
+	//
+	// create the base image DataVolume from the base image source
+	//
+	if instance.Spec.RootDisk.BaseImageSource != nil {
+		ctrlResult, err := r.createBaseImageDataVolume(ctx, instance, cond, baseImageName)
+
+		return baseImageName, ctrlResult, err
+	}
+	instance.Status.BaseImage = nil
+
 	// wait for the base image conversion job to be finished before we create the VMs
 	// we check the pvc for the base image:
 	// - import in progress
@@ -1491,6 +2372,291 @@ func (r *OpenStackVMSetReconciler) createBaseImage(
 	return baseImageName, ctrl.Result{}, nil
 }
 
+// Create the BaseImage DataVolume from the base image source of the rootdisk
+func (r *OpenStackVMSetReconciler) createBaseImageDataVolume(
+	ctx context.Context,
+	instance *ospdirectorv1beta2.OpenStackVMSet,
+	cond *shared.Condition,
+	baseImageName string,
+) (ctrl.Result, error) {
+	source := instance.Spec.RootDisk.BaseImageSource
+
+	sourceHash, err := common.ObjectHash(source)
+	if err != nil {
+		cond.Message = fmt.Sprintf("Failed to calculate the hash of the base image source of %s", baseImageName)
+		cond.Reason = shared.VMSetCondReasonBaseImageError
+		cond.Type = shared.CommonCondTypeError
+		err = common.WrapErrorForObject(cond.Message, instance, err)
+
+		return ctrl.Result{}, err
+	}
+
+	if instance.Status.BaseImage == nil || instance.Status.BaseImage.DataVolume != baseImageName {
+		instance.Status.BaseImage = &ospdirectorv1beta2.OpenStackVMSetBaseImageStatus{
+			DataVolume: baseImageName,
+			SourceHash: sourceHash,
+		}
+	}
+	baseImageStatus := instance.Status.BaseImage
+	if baseImageStatus.SourceHash == "" {
+		baseImageStatus.SourceHash = sourceHash
+	}
+
+	//
+	// the base image source can change until the base image is ready, start over with the new source
+	//
+	if baseImageStatus.SourceHash != sourceHash && !baseImageStatus.Ready {
+		return r.resetBaseImageDataVolume(ctx, instance, cond, baseImageName, sourceHash)
+	}
+
+	dataVolume := &cdiv1.DataVolume{}
+	err = r.Get(ctx, types.NamespacedName{Name: baseImageName, Namespace: instance.Namespace}, dataVolume)
+	if err != nil && !k8s_errors.IsNotFound(err) {
+		cond.Message = fmt.Sprintf("Failed to get base image DataVolume %s", baseImageName)
+		cond.Reason = shared.VMSetCondReasonBaseImageError
+		cond.Type = shared.CommonCondTypeError
+		err = common.WrapErrorForObject(cond.Message, instance, err)
+
+		return ctrl.Result{}, err
+	}
+
+	if err == nil && !dataVolume.DeletionTimestamp.IsZero() {
+		cond.Message = fmt.Sprintf("Waiting on the deletion of the base image DataVolume %s", baseImageName)
+		cond.Reason = shared.VMSetCondReasonBaseImageNotReady
+		cond.Type = shared.CommonCondTypeWaiting
+
+		return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
+	}
+
+	if k8s_errors.IsNotFound(err) {
+		//
+		// verify the checksum of the image before the import
+		//
+		if source.HTTP != nil && source.HTTP.Checksum != "" && !baseImageStatus.ChecksumVerified {
+			ctrlResult, err := r.verifyBaseImageChecksum(ctx, instance, cond, baseImageName)
+			if (err != nil) || (ctrlResult != ctrl.Result{}) {
+				return ctrlResult, err
+			}
+			baseImageStatus.ChecksumVerified = true
+		}
+
+		dataVolume = &cdiv1.DataVolume{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      baseImageName,
+				Namespace: instance.Namespace,
+				Labels:    common.GetLabels(instance, vmset.AppLabel, map[string]string{}),
+			},
+			Spec: vmset.BaseImageDataVolumeSpec(source, instance.Namespace, instance.Spec.RootDisk),
+		}
+
+		if err := controllerutil.SetControllerReference(instance, dataVolume, r.Scheme); err != nil {
+			cond.Message = fmt.Sprintf("Error set controller reference for %s", dataVolume.Name)
+			cond.Reason = shared.CommonCondReasonControllerReferenceError
+			cond.Type = shared.CommonCondTypeError
+			err = common.WrapErrorForObject(cond.Message, instance, err)
+
+			return ctrl.Result{}, err
+		}
+
+		if err := r.Create(ctx, dataVolume); err != nil {
+			cond.Message = fmt.Sprintf("Failed to create base image DataVolume %s", baseImageName)
+			cond.Reason = shared.VMSetCondReasonBaseImageError
+			cond.Type = shared.CommonCondTypeError
+			err = common.WrapErrorForObject(cond.Message, instance, err)
+
+			return ctrl.Result{}, err
+		}
+		common.LogForObject(r, fmt.Sprintf("Base image DataVolume %s created", baseImageName), instance)
+	}
+
+	baseImageStatus.Phase = string(dataVolume.Status.Phase)
+	baseImageStatus.Progress = string(dataVolume.Status.Progress)
+	baseImageStatus.Ready = dataVolume.Status.Phase == cdiv1.Succeeded
+	instance.Status.BaseImageDVReady = baseImageStatus.Ready
+
+	switch dataVolume.Status.Phase {
+	case cdiv1.Succeeded:
+		return ctrl.Result{}, nil
+	case cdiv1.Failed:
+		//
+		// recreate the failed DataVolume with a backoff, it is kept until then to check its events
+		//
+		if baseImageStatus.LastFailureTime == nil {
+			now := metav1.Now()
+			baseImageStatus.LastFailureTime = &now
+			baseImageStatus.Failures++
+		}
+
+		retryDelay := vmset.GetBaseImageRetryDelay(baseImageStatus.Failures)
+		if wait := time.Until(baseImageStatus.LastFailureTime.Add(retryDelay)); wait > 0 {
+			cond.Message = fmt.Sprintf("Base image DataVolume %s failed %d times, check the events of the DataVolume. Recreating it in %s",
+				baseImageName,
+				baseImageStatus.Failures,
+				wait.Round(time.Second),
+			)
+			cond.Reason = shared.VMSetCondReasonBaseImageError
+			cond.Type = shared.CommonCondTypeError
+
+			return ctrl.Result{RequeueAfter: wait}, nil
+		}
+
+		if err := r.deleteBaseImageDataVolume(ctx, instance, cond, baseImageName); err != nil {
+			return ctrl.Result{}, err
+		}
+		baseImageStatus.LastFailureTime = nil
+
+		cond.Message = fmt.Sprintf("Base image DataVolume %s failed, recreating it", baseImageName)
+		cond.Reason = shared.VMSetCondReasonBaseImageNotReady
+		cond.Type = shared.CommonCondTypeWaiting
+		common.LogForObject(r, cond.Message, instance)
+
+		return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
+	}
+
+	cond.Message = fmt.Sprintf("Base image DataVolume %s not ready - phase: %s progress: %s, reconcile in 30s",
+		baseImageName,
+		baseImageStatus.Phase,
+		baseImageStatus.Progress,
+	)
+	cond.Reason = shared.VMSetCondReasonBaseImageNotReady
+	cond.Type = shared.CommonCondTypeWaiting
+
+	return ctrl.Result{RequeueAfter: 30 * time.Second}, nil
+}
+
+// resetBaseImageDataVolume - delete the base image DataVolume and checksum job created from a previous base image source
+func (r *OpenStackVMSetReconciler) resetBaseImageDataVolume(
+	ctx context.Context,
+	instance *ospdirectorv1beta2.OpenStackVMSet,
+	cond *shared.Condition,
+	baseImageName string,
+	sourceHash string,
+) (ctrl.Result, error) {
+	if err := r.deleteBaseImageDataVolume(ctx, instance, cond, baseImageName); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("%s-checksum", baseImageName),
+			Namespace: instance.Namespace,
+		},
+	}
+	if _, err := common.DeleteJob(ctx, job, r.Kclient, r.Log); err != nil {
+		cond.Message = fmt.Sprintf("Failed to delete base image checksum job %s", job.Name)
+		cond.Reason = shared.VMSetCondReasonBaseImageError
+		cond.Type = shared.CommonCondTypeError
+		err = common.WrapErrorForObject(cond.Message, instance, err)
+
+		return ctrl.Result{}, err
+	}
+
+	instance.Status.BaseImage = &ospdirectorv1beta2.OpenStackVMSetBaseImageStatus{
+		DataVolume: baseImageName,
+		SourceHash: sourceHash,
+	}
+	instance.Status.BaseImageDVReady = false
+
+	cond.Message = fmt.Sprintf("Base image source of %s changed, recreating the base image DataVolume", baseImageName)
+	cond.Reason = shared.VMSetCondReasonBaseImageNotReady
+	cond.Type = shared.CommonCondTypeWaiting
+	common.LogForObject(r, cond.Message, instance)
+
+	return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
+}
+
+// deleteBaseImageDataVolume - delete the base image DataVolume, if it exists
+func (r *OpenStackVMSetReconciler) deleteBaseImageDataVolume(
+	ctx context.Context,
+	instance *ospdirectorv1beta2.OpenStackVMSet,
+	cond *shared.Condition,
+	baseImageName string,
+) error {
+	dataVolume := &cdiv1.DataVolume{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      baseImageName,
+			Namespace: instance.Namespace,
+		},
+	}
+	if err := r.Delete(ctx, dataVolume); err != nil && !k8s_errors.IsNotFound(err) {
+		cond.Message = fmt.Sprintf("Failed to delete base image DataVolume %s", baseImageName)
+		cond.Reason = shared.VMSetCondReasonBaseImageError
+		cond.Type = shared.CommonCondTypeError
+		err = common.WrapErrorForObject(cond.Message, instance, err)
+
+		return err
+	}
+
+	return nil
+}
+
+// verifyBaseImageChecksum - run a job which verifies the checksum of the image at the http URL of the base image source
+func (r *OpenStackVMSetReconciler) verifyBaseImageChecksum(
+	ctx context.Context,
+	instance *ospdirectorv1beta2.OpenStackVMSet,
+	cond *shared.Condition,
+	baseImageName string,
+) (ctrl.Result, error) {
+	job := vmset.BaseImageChecksumJob(
+		fmt.Sprintf("%s-checksum", baseImageName),
+		instance.Namespace,
+		common.GetLabels(instance, vmset.AppLabel, map[string]string{}),
+		instance.Spec.RootDisk.BaseImageSource.HTTP,
+	)
+
+	op, err := controllerutil.CreateOrPatch(ctx, r.Client, job, func() error {
+		return controllerutil.SetControllerReference(instance, job, r.Scheme)
+	})
+	if err != nil {
+		cond.Message = fmt.Sprintf("Failed to create base image checksum job %s", job.Name)
+		cond.Reason = shared.VMSetCondReasonBaseImageError
+		cond.Type = shared.CommonCondTypeError
+		err = common.WrapErrorForObject(cond.Message, instance, err)
+
+		return ctrl.Result{}, err
+	}
+	if op == controllerutil.OperationResultCreated {
+		cond.Message = fmt.Sprintf("Base image checksum job %s created", job.Name)
+		cond.Reason = shared.VMSetCondReasonBaseImageNotReady
+		cond.Type = shared.CommonCondTypeWaiting
+		common.LogForObject(r, cond.Message, instance)
+
+		return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
+	}
+
+	requeue, err := common.WaitOnJob(ctx, job, r.Client, r.Log)
+	if err != nil {
+		cond.Message = fmt.Sprintf("Checksum verification of base image %s failed, check the logs of job %s",
+			instance.Spec.RootDisk.BaseImageSource.HTTP.URL,
+			job.Name,
+		)
+		cond.Reason = shared.VMSetCondReasonBaseImageChecksumError
+		cond.Type = shared.CommonCondTypeError
+		err = common.WrapErrorForObject(cond.Message, instance, err)
+
+		return ctrl.Result{}, err
+	} else if requeue {
+		cond.Message = fmt.Sprintf("Waiting on base image checksum job %s", job.Name)
+		cond.Reason = shared.VMSetCondReasonBaseImageNotReady
+		cond.Type = shared.CommonCondTypeWaiting
+
+		return ctrl.Result{RequeueAfter: 20 * time.Second}, nil
+	}
+
+	common.LogForObject(r, fmt.Sprintf("Checksum of base image %s verified", instance.Spec.RootDisk.BaseImageSource.HTTP.URL), instance)
+
+	if _, err := common.DeleteJob(ctx, job, r.Kclient, r.Log); err != nil {
+		cond.Message = fmt.Sprintf("Failed to delete base image checksum job %s", job.Name)
+		cond.Reason = shared.VMSetCondReasonBaseImageError
+		cond.Type = shared.CommonCondTypeError
+		err = common.WrapErrorForObject(cond.Message, instance, err)
+
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{}, nil
+}
+
 func (r *OpenStackVMSetReconciler) doVMDelete(
 	ctx context.Context,
 	instance *ospdirectorv1beta2.OpenStackVMSet,
@@ -1513,6 +2679,38 @@ func (r *OpenStackVMSetReconciler) doVMDelete(
 	// How many VirtualMachine de-allocations do we need (if any)?
 	oldVmsToRemoveCount := len(existingVirtualMachines) - instance.Spec.VMCount
 
+	// With a scale-down policy, VirtualMachines which are not annotated for removal get
+	// selected from the policy hostnames list first and then by the policy type
+	if instance.Spec.ScaleDownPolicy != nil && oldVmsToRemoveCount > len(removalAnnotatedVirtualMachines) {
+		annotatedVirtualMachines := []string{}
+		for _, virtualMachine := range removalAnnotatedVirtualMachines {
+			annotatedVirtualMachines = append(annotatedVirtualMachines, virtualMachine.Name)
+		}
+
+		candidates := []common.ScaleDownCandidate{}
+		for _, virtualMachine := range virtualMachineList.Items {
+			candidates = append(candidates, common.ScaleDownCandidate{
+				Name:        virtualMachine.Name,
+				Hostname:    virtualMachine.Spec.Template.Spec.Hostname,
+				Provisioned: virtualMachine.CreationTimestamp,
+				Ready:       virtualMachine.Status.Ready,
+			})
+		}
+
+		for _, name := range common.GetScaleDownHosts(
+			instance.Spec.ScaleDownPolicy,
+			candidates,
+			annotatedVirtualMachines,
+			oldVmsToRemoveCount-len(annotatedVirtualMachines),
+		) {
+			for _, virtualMachine := range virtualMachineList.Items {
+				if virtualMachine.Name == name {
+					removalAnnotatedVirtualMachines = append(removalAnnotatedVirtualMachines, virtualMachine)
+				}
+			}
+		}
+	}
+
 	if oldVmsToRemoveCount > 0 {
 		if len(removalAnnotatedVirtualMachines) > 0 && len(removalAnnotatedVirtualMachines) == oldVmsToRemoveCount {
 			for i := 0; i < oldVmsToRemoveCount; i++ {
@@ -0,0 +1,470 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	k8s_errors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	virtv1 "kubevirt.io/api/core/v1"
+	snapshotv1 "kubevirt.io/api/snapshot/v1beta1"
+	"kubevirt.io/client-go/kubecli"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/go-logr/logr"
+	"github.com/openstack-k8s-operators/osp-director-operator/api/shared"
+	ospdirectorv1beta1 "github.com/openstack-k8s-operators/osp-director-operator/api/v1beta1"
+	ospdirectorv1beta2 "github.com/openstack-k8s-operators/osp-director-operator/api/v1beta2"
+	common "github.com/openstack-k8s-operators/osp-director-operator/pkg/common"
+	vmset "github.com/openstack-k8s-operators/osp-director-operator/pkg/vmset"
+)
+
+// OpenStackVMSetRestoreRequestReconciler reconciles a OpenStackVMSetRestoreRequest object
+type OpenStackVMSetRestoreRequestReconciler struct {
+	client.Client
+	Kclient        kubernetes.Interface
+	Log            logr.Logger
+	Scheme         *runtime.Scheme
+	KubevirtClient kubecli.KubevirtClient
+}
+
+// GetClient -
+func (r *OpenStackVMSetRestoreRequestReconciler) GetClient() client.Client {
+	return r.Client
+}
+
+// GetKClient -
+func (r *OpenStackVMSetRestoreRequestReconciler) GetKClient() kubernetes.Interface {
+	return r.Kclient
+}
+
+// GetVirtClient -
+func (r *OpenStackVMSetRestoreRequestReconciler) GetVirtClient() kubecli.KubevirtClient {
+	return r.KubevirtClient
+}
+
+// GetLogger -
+func (r *OpenStackVMSetRestoreRequestReconciler) GetLogger() logr.Logger {
+	return r.Log
+}
+
+// GetScheme -
+func (r *OpenStackVMSetRestoreRequestReconciler) GetScheme() *runtime.Scheme {
+	return r.Scheme
+}
+
+// +kubebuilder:rbac:groups=osp-director.openstack.org,resources=openstackvmsetrestorerequests,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=osp-director.openstack.org,resources=openstackvmsetrestorerequests/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=osp-director.openstack.org,resources=openstackvmsetrestorerequests/finalizers,verbs=update
+// +kubebuilder:rbac:groups=osp-director.openstack.org,resources=openstackvmsets,verbs=get;list;watch
+// +kubebuilder:rbac:groups=kubevirt.io,namespace=openstack,resources=virtualmachines,verbs=get;list;watch
+// +kubebuilder:rbac:groups=subresources.kubevirt.io,namespace=openstack,resources=virtualmachines/start;virtualmachines/stop,verbs=update
+// +kubebuilder:rbac:groups=snapshot.kubevirt.io,namespace=openstack,resources=virtualmachinesnapshots,verbs=get;list;watch
+// +kubebuilder:rbac:groups=snapshot.kubevirt.io,namespace=openstack,resources=virtualmachinerestores,verbs=create;delete;get;list;patch;update;watch
+
+// Reconcile - OpenStackVMSetRestoreRequest
+func (r *OpenStackVMSetRestoreRequestReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	_ = log.FromContext(ctx)
+
+	// Fetch the OpenStackVMSetRestoreRequest instance
+	instance := &ospdirectorv1beta1.OpenStackVMSetRestoreRequest{}
+	err := r.Get(ctx, req.NamespacedName, instance)
+	if err != nil {
+		if k8s_errors.IsNotFound(err) {
+			// Request object not found, could have been deleted after reconcile request.
+			// Owned objects are automatically garbage collected.
+			// For additional cleanup logic use finalizers. Return and don't requeue.
+			return ctrl.Result{}, nil
+		}
+		// Error reading the object - requeue the request.
+		return ctrl.Result{}, err
+	}
+
+	//
+	// initialize condition
+	//
+	cond := &shared.Condition{}
+
+	//
+	// Used in comparisons below to determine whether a status update is actually needed
+	//
+	currentStatus := instance.Status.DeepCopy()
+	statusChanged := func() bool {
+		return !equality.Semantic.DeepEqual(
+			r.getNormalizedStatus(&instance.Status),
+			r.getNormalizedStatus(currentStatus),
+		)
+	}
+
+	defer func(cond *shared.Condition) {
+		//
+		// Update object conditions
+		//
+		instance.Status.CurrentState = shared.ProvisioningState(cond.Type)
+		instance.Status.CurrentReason = cond.Reason
+
+		instance.Status.Conditions.UpdateCurrentCondition(
+			cond.Type,
+			cond.Reason,
+			cond.Message,
+		)
+
+		if statusChanged() {
+			if updateErr := r.Status().Update(context.Background(), instance); updateErr != nil {
+				common.LogErrorForObject(r, updateErr, "Update status", instance)
+			}
+		}
+	}(cond)
+
+	//
+	// a finished restore request is not processed again
+	//
+	if instance.Status.CurrentState == shared.ProvisioningState(shared.VMSetRestoreCondTypeFinished) {
+		cond.Message = fmt.Sprintf("OpenStackVMSet %s restored from snapshot set %s", instance.Spec.VMSet, instance.Spec.SnapshotSet)
+		cond.Reason = shared.VMSetRestoreCondReasonRestored
+		cond.Type = shared.VMSetRestoreCondTypeFinished
+
+		return ctrl.Result{}, nil
+	}
+
+	vmSet := &ospdirectorv1beta2.OpenStackVMSet{}
+	if err := r.Get(ctx, types.NamespacedName{Name: instance.Spec.VMSet, Namespace: instance.Namespace}, vmSet); err != nil {
+		cond.Message = fmt.Sprintf("Failed to get OpenStackVMSet %s", instance.Spec.VMSet)
+		cond.Reason = shared.VMSetRestoreCondReasonVMSetNotFound
+		cond.Type = shared.VMSetRestoreCondTypeError
+		err = common.WrapErrorForObject(cond.Message, instance, err)
+
+		return ctrl.Result{}, err
+	}
+
+	// the VMSet controller resets the run strategy of the VMs, with Always or RerunOnFailure
+	// KubeVirt would start the VMs again while they get restored
+	if vmSet.Spec.RunStrategy == virtv1.RunStrategyAlways || vmSet.Spec.RunStrategy == virtv1.RunStrategyRerunOnFailure {
+		cond.Message = fmt.Sprintf("OpenStackVMSet %s has runStrategy %s, restore requires %s or %s",
+			vmSet.Name,
+			vmSet.Spec.RunStrategy,
+			virtv1.RunStrategyManual,
+			virtv1.RunStrategyHalted,
+		)
+		cond.Reason = shared.VMSetRestoreCondReasonRunStrategyNotSupported
+		cond.Type = shared.VMSetRestoreCondTypeError
+
+		return ctrl.Result{}, fmt.Errorf("%s", cond.Message)
+	}
+
+	hostnames := []string{}
+	for hostname, host := range vmSet.Status.VMHosts {
+		if hostname != "" && !host.AnnotatedForDeletion {
+			hostnames = append(hostnames, hostname)
+		}
+	}
+	sort.Strings(hostnames)
+
+	//
+	// get the snapshot of each VM of the VMSet
+	//
+	snapshots, ctrlResult, err := r.getSnapshots(ctx, instance, cond, vmSet, hostnames)
+	if (err != nil) || (ctrlResult != ctrl.Result{}) {
+		return ctrlResult, err
+	}
+
+	//
+	// stop the VMs, KubeVirt only restores stopped VMs
+	//
+	if instance.Status.Restores == nil {
+		ctrlResult, err := r.stopVMs(ctx, instance, cond, hostnames)
+		if (err != nil) || (ctrlResult != ctrl.Result{}) {
+			return ctrlResult, err
+		}
+	}
+
+	//
+	// restore the VMs from the snapshots
+	//
+	ctrlResult, err = r.restoreVMs(ctx, instance, cond, hostnames, snapshots)
+	if (err != nil) || (ctrlResult != ctrl.Result{}) {
+		return ctrlResult, err
+	}
+
+	//
+	// start the VMs which got stopped for the restore
+	//
+	for _, hostname := range instance.Status.StoppedVMs {
+		vm := &virtv1.VirtualMachine{}
+		if err := r.Get(ctx, types.NamespacedName{Name: hostname, Namespace: instance.Namespace}, vm); err != nil {
+			cond.Message = fmt.Sprintf("Failed to get VirtualMachine %s", hostname)
+			cond.Reason = shared.VMSetRestoreCondReasonKubevirtError
+			cond.Type = shared.VMSetRestoreCondTypeError
+			err = common.WrapErrorForObject(cond.Message, instance, err)
+
+			return ctrl.Result{}, err
+		}
+
+		if vm.Status.PrintableStatus != virtv1.VirtualMachineStatusStopped {
+			continue
+		}
+
+		if err := r.KubevirtClient.VirtualMachine(instance.Namespace).Start(ctx, hostname, &virtv1.StartOptions{}); err != nil {
+			cond.Message = fmt.Sprintf("Failed to start VirtualMachine %s", hostname)
+			cond.Reason = shared.VMSetRestoreCondReasonKubevirtError
+			cond.Type = shared.VMSetRestoreCondTypeError
+			err = common.WrapErrorForObject(cond.Message, instance, err)
+
+			return ctrl.Result{}, err
+		}
+		common.LogForObject(r, fmt.Sprintf("VirtualMachine %s started", hostname), instance)
+	}
+
+	instance.Status.CompletionTimestamp = metav1.Now()
+	cond.Message = fmt.Sprintf("OpenStackVMSet %s restored from snapshot set %s", instance.Spec.VMSet, instance.Spec.SnapshotSet)
+	cond.Reason = shared.VMSetRestoreCondReasonRestored
+	cond.Type = shared.VMSetRestoreCondTypeFinished
+	common.LogForObject(r, cond.Message, instance)
+
+	return ctrl.Result{}, nil
+}
+
+// getSnapshots - get the ready to use snapshot of the snapshot set for each VM
+func (r *OpenStackVMSetRestoreRequestReconciler) getSnapshots(
+	ctx context.Context,
+	instance *ospdirectorv1beta1.OpenStackVMSetRestoreRequest,
+	cond *shared.Condition,
+	vmSet *ospdirectorv1beta2.OpenStackVMSet,
+	hostnames []string,
+) (map[string]string, ctrl.Result, error) {
+	snapshotList := &snapshotv1.VirtualMachineSnapshotList{}
+	listOpts := []client.ListOption{
+		client.InNamespace(instance.Namespace),
+		client.MatchingLabels{
+			vmset.SnapshotSetLabelSelector: instance.Spec.SnapshotSet,
+			common.OwnerNameLabelSelector:  vmSet.Name,
+		},
+	}
+	if err := r.List(ctx, snapshotList, listOpts...); err != nil {
+		cond.Message = fmt.Sprintf("Failed to list VirtualMachineSnapshots of snapshot set %s", instance.Spec.SnapshotSet)
+		cond.Reason = shared.VMSetRestoreCondReasonSnapshotNotFound
+		cond.Type = shared.VMSetRestoreCondTypeError
+		err = common.WrapErrorForObject(cond.Message, instance, err)
+
+		return nil, ctrl.Result{}, err
+	}
+
+	snapshots := map[string]string{}
+	notReady := []string{}
+	for idx := range snapshotList.Items {
+		snapshot := &snapshotList.Items[idx]
+		snapshots[snapshot.Spec.Source.Name] = snapshot.Name
+		if !vmset.IsSnapshotReady(snapshot) {
+			notReady = append(notReady, snapshot.Name)
+		}
+	}
+
+	missing := []string{}
+	for _, hostname := range hostnames {
+		if _, ok := snapshots[hostname]; !ok {
+			missing = append(missing, hostname)
+		}
+	}
+	if len(missing) > 0 {
+		cond.Message = fmt.Sprintf("Snapshot set %s has no VirtualMachineSnapshot of %s",
+			instance.Spec.SnapshotSet,
+			strings.Join(missing, ","),
+		)
+		cond.Reason = shared.VMSetRestoreCondReasonSnapshotNotFound
+		cond.Type = shared.VMSetRestoreCondTypeError
+
+		return nil, ctrl.Result{}, fmt.Errorf("%s", cond.Message)
+	}
+
+	if len(notReady) > 0 {
+		sort.Strings(notReady)
+		cond.Message = fmt.Sprintf("Waiting on VirtualMachineSnapshots %s to be ready", strings.Join(notReady, ","))
+		cond.Reason = shared.VMSetRestoreCondReasonSnapshotNotReady
+		cond.Type = shared.VMSetRestoreCondTypeWaiting
+		common.LogForObject(r, cond.Message, instance)
+
+		return nil, ctrl.Result{RequeueAfter: time.Second * 10}, nil
+	}
+
+	return snapshots, ctrl.Result{}, nil
+}
+
+// stopVMs - stop the running VMs and wait for all of them to be stopped
+func (r *OpenStackVMSetRestoreRequestReconciler) stopVMs(
+	ctx context.Context,
+	instance *ospdirectorv1beta1.OpenStackVMSetRestoreRequest,
+	cond *shared.Condition,
+	hostnames []string,
+) (ctrl.Result, error) {
+	notStopped := []string{}
+	for _, hostname := range hostnames {
+		vm := &virtv1.VirtualMachine{}
+		if err := r.Get(ctx, types.NamespacedName{Name: hostname, Namespace: instance.Namespace}, vm); err != nil {
+			cond.Message = fmt.Sprintf("Failed to get VirtualMachine %s", hostname)
+			cond.Reason = shared.VMSetRestoreCondReasonKubevirtError
+			cond.Type = shared.VMSetRestoreCondTypeError
+			err = common.WrapErrorForObject(cond.Message, instance, err)
+
+			return ctrl.Result{}, err
+		}
+
+		if vm.Status.PrintableStatus == virtv1.VirtualMachineStatusStopped {
+			continue
+		}
+		notStopped = append(notStopped, hostname)
+
+		if slices.Contains(instance.Status.StoppedVMs, hostname) {
+			continue
+		}
+
+		if err := r.KubevirtClient.VirtualMachine(instance.Namespace).Stop(ctx, hostname, &virtv1.StopOptions{}); err != nil {
+			cond.Message = fmt.Sprintf("Failed to stop VirtualMachine %s", hostname)
+			cond.Reason = shared.VMSetRestoreCondReasonKubevirtError
+			cond.Type = shared.VMSetRestoreCondTypeError
+			err = common.WrapErrorForObject(cond.Message, instance, err)
+
+			return ctrl.Result{}, err
+		}
+		instance.Status.StoppedVMs = append(instance.Status.StoppedVMs, hostname)
+		common.LogForObject(r, fmt.Sprintf("VirtualMachine %s stopped for restore", hostname), instance)
+	}
+
+	if len(notStopped) > 0 {
+		cond.Message = fmt.Sprintf("Waiting on VirtualMachines %s to be stopped", strings.Join(notStopped, ","))
+		cond.Reason = shared.VMSetRestoreCondReasonVirtualMachineStopping
+		cond.Type = shared.VMSetRestoreCondTypeStopping
+
+		return ctrl.Result{RequeueAfter: time.Second * 10}, nil
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// restoreVMs - create a VirtualMachineRestore for each VM and wait for all of them to complete
+func (r *OpenStackVMSetRestoreRequestReconciler) restoreVMs(
+	ctx context.Context,
+	instance *ospdirectorv1beta1.OpenStackVMSetRestoreRequest,
+	cond *shared.Condition,
+	hostnames []string,
+	snapshots map[string]string,
+) (ctrl.Result, error) {
+	if instance.Status.Restores == nil {
+		instance.Status.Restores = map[string]string{}
+	}
+
+	pending := []string{}
+	for _, hostname := range hostnames {
+		name := fmt.Sprintf("%s-%s", instance.Name, hostname)
+		restore := &snapshotv1.VirtualMachineRestore{}
+		err := r.Get(ctx, types.NamespacedName{Name: name, Namespace: instance.Namespace}, restore)
+		if err != nil && !k8s_errors.IsNotFound(err) {
+			cond.Message = fmt.Sprintf("Failed to get VirtualMachineRestore %s", name)
+			cond.Reason = shared.VMSetRestoreCondReasonRestoreError
+			cond.Type = shared.VMSetRestoreCondTypeError
+			err = common.WrapErrorForObject(cond.Message, instance, err)
+
+			return ctrl.Result{}, err
+		}
+
+		if k8s_errors.IsNotFound(err) {
+			restore = vmset.VirtualMachineRestore(
+				name,
+				instance.Namespace,
+				hostname,
+				snapshots[hostname],
+				common.GetLabels(instance, vmset.AppLabel, map[string]string{}),
+			)
+			if err := controllerutil.SetControllerReference(instance, restore, r.Scheme); err != nil {
+				cond.Message = fmt.Sprintf("Error set controller reference for %s", name)
+				cond.Reason = shared.CommonCondReasonControllerReferenceError
+				cond.Type = shared.VMSetRestoreCondTypeError
+				err = common.WrapErrorForObject(cond.Message, instance, err)
+
+				return ctrl.Result{}, err
+			}
+
+			if err := r.Create(ctx, restore); err != nil {
+				cond.Message = fmt.Sprintf("Failed to create VirtualMachineRestore %s", name)
+				cond.Reason = shared.VMSetRestoreCondReasonRestoreError
+				cond.Type = shared.VMSetRestoreCondTypeError
+				err = common.WrapErrorForObject(cond.Message, instance, err)
+
+				return ctrl.Result{}, err
+			}
+			common.LogForObject(r, fmt.Sprintf("VirtualMachineRestore %s created", name), instance)
+		}
+		instance.Status.Restores[hostname] = name
+
+		if msg := vmset.GetRestoreFailure(restore); msg != "" {
+			cond.Message = fmt.Sprintf("VirtualMachineRestore %s failed: %s", name, msg)
+			cond.Reason = shared.VMSetRestoreCondReasonRestoreError
+			cond.Type = shared.VMSetRestoreCondTypeError
+
+			return ctrl.Result{}, fmt.Errorf("%s", cond.Message)
+		}
+
+		if !vmset.IsRestoreComplete(restore) {
+			pending = append(pending, name)
+		}
+	}
+
+	if len(pending) > 0 {
+		cond.Message = fmt.Sprintf("Waiting on VirtualMachineRestores %s to complete", strings.Join(pending, ","))
+		cond.Reason = shared.VMSetRestoreCondReasonVirtualMachineRestoring
+		cond.Type = shared.VMSetRestoreCondTypeRestoring
+
+		return ctrl.Result{RequeueAfter: time.Second * 10}, nil
+	}
+
+	return ctrl.Result{}, nil
+}
+
+func (r *OpenStackVMSetRestoreRequestReconciler) getNormalizedStatus(status *ospdirectorv1beta1.OpenStackVMSetRestoreRequestStatus) *ospdirectorv1beta1.OpenStackVMSetRestoreRequestStatus {
+
+	//
+	// set LastHeartbeatTime and LastTransitionTime to a default value as those
+	// need to be ignored to compare if conditions changed.
+	//
+	s := status.DeepCopy()
+	for idx := range s.Conditions {
+		s.Conditions[idx].LastHeartbeatTime = metav1.Time{}
+		s.Conditions[idx].LastTransitionTime = metav1.Time{}
+	}
+
+	return s
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *OpenStackVMSetRestoreRequestReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&ospdirectorv1beta1.OpenStackVMSetRestoreRequest{}).
+		Owns(&snapshotv1.VirtualMachineRestore{}).
+		Complete(r)
+}
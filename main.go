@@ -45,6 +45,7 @@ import (
 	networkv1 "github.com/k8snetworkplumbingwg/network-attachment-definition-client/pkg/apis/k8s.cni.cncf.io/v1"
 	nmstatev1 "github.com/nmstate/kubernetes-nmstate/api/v1beta1"
 	virtv1 "kubevirt.io/api/core/v1"
+	snapshotv1 "kubevirt.io/api/snapshot/v1beta1"
 
 	sriovnetworkv1 "github.com/k8snetworkplumbingwg/sriov-network-operator/api/v1"
 	metal3v1 "github.com/metal3-io/baremetal-operator/apis/metal3.io/v1alpha1"
@@ -58,7 +59,7 @@ import (
 	ospdirectorv1beta2 "github.com/openstack-k8s-operators/osp-director-operator/api/v1beta2"
 
 	"github.com/openstack-k8s-operators/osp-director-operator/controllers"
-	//cdiv1 "kubevirt.io/containerized-data-importer/pkg/apis/core/v1alpha1"
+	cdiv1 "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
 	//templatev1 "github.com/openshift/api/template/v1"
 	// +kubebuilder:scaffold:imports
 )
@@ -85,9 +86,10 @@ func init() {
 	utilruntime.Must(ospdirectorv1beta2.AddToScheme(scheme))
 	//utilruntime.Must(templatev1.AddToScheme(scheme))
 	utilruntime.Must(virtv1.AddToScheme(scheme))
+	utilruntime.Must(snapshotv1.AddToScheme(scheme))
 	utilruntime.Must(nmstatev1.AddToScheme(scheme))
 	utilruntime.Must(networkv1.AddToScheme(scheme))
-	//utilruntime.Must(cdiv1.AddToScheme(scheme))
+	utilruntime.Must(cdiv1.AddToScheme(scheme))
 	utilruntime.Must(metal3v1.AddToScheme(scheme))
 	utilruntime.Must(machinev1beta1.AddToScheme(scheme))
 	utilruntime.Must(sriovnetworkv1.AddToScheme(scheme))
@@ -349,6 +351,17 @@ func main() {
 		os.Exit(1)
 	}
 
+	if err = (&controllers.OpenStackVMSetRestoreRequestReconciler{
+		Client:         mgr.GetClient(),
+		Kclient:        kclient,
+		Log:            ctrl.Log.WithName("controllers").WithName("OpenStackVMSetRestoreRequest"),
+		Scheme:         mgr.GetScheme(),
+		KubevirtClient: kubevirtClient,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "OpenStackVMSetRestoreRequest")
+		os.Exit(1)
+	}
+
 	if err = (&controllers.OpenStackDeployReconciler{
 		Client:  mgr.GetClient(),
 		Kclient: kclient,
@@ -398,6 +411,10 @@ func main() {
 			AgentImageURL: os.Getenv("AGENT_IMAGE_URL_DEFAULT"),
 		}
 
+		openstackVMSetDefaults := ospdirectorv1beta2.OpenStackVMSetDefaults{
+			ChecksumImageURL: os.Getenv("DOWNLOADER_IMAGE_URL_DEFAULT"),
+		}
+
 		openstackConfigGeneratorDefaults := ospdirectorv1beta1.OpenStackConfigGeneratorDefaults{
 			ImageURL: os.Getenv("OPENSTACKCLIENT_IMAGE_URL_DEFAULT"),
 		}
@@ -425,7 +442,7 @@ func main() {
 			os.Exit(1)
 		}
 
-		if err = (&ospdirectorv1beta2.OpenStackVMSet{}).SetupWebhookWithManager(mgr); err != nil {
+		if err = (&ospdirectorv1beta2.OpenStackVMSet{}).SetupWebhookWithManager(mgr, openstackVMSetDefaults); err != nil {
 			setupLog.Error(err, "unable to create webhook", "webhook", "OpenStackVMSet")
 			os.Exit(1)
 		}
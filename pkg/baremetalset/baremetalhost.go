@@ -0,0 +1,106 @@
+/*
+Copyright 2022 Red Hat
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package baremetalset
+
+import (
+	"encoding/json"
+
+	metal3v1 "github.com/metal3-io/baremetal-operator/apis/metal3.io/v1alpha1"
+	ospdirectorv1beta1 "github.com/openstack-k8s-operators/osp-director-operator/api/v1beta1"
+)
+
+// HostSettings - BaremetalHost settings which can be managed by the OpenStackBaremetalSet
+type HostSettings struct {
+	RootDeviceHints *metal3v1.RootDeviceHints `json:"rootDeviceHints,omitempty"`
+	RAID            *metal3v1.RAIDConfig      `json:"raid,omitempty"`
+	Firmware        *metal3v1.FirmwareConfig  `json:"firmware,omitempty"`
+}
+
+// SetHostSettings - set the root device hints, RAID and firmware settings requested in the
+// OpenStackBaremetalSet on the BaremetalHost. Settings not requested in the OpenStackBaremetalSet
+// are left untouched. The original BaremetalHost settings are stored in the HostSettingsAnnotation
+// to be restored on deprovision.
+func SetHostSettings(
+	instance *ospdirectorv1beta1.OpenStackBaremetalSet,
+	bmh *metal3v1.BareMetalHost,
+) error {
+	if instance.Spec.RootDeviceHints == nil &&
+		instance.Spec.RAID == nil &&
+		instance.Spec.Firmware == nil {
+		return nil
+	}
+
+	//
+	// only store the original settings once, the BMH might already carry ours
+	//
+	if _, ok := bmh.GetAnnotations()[HostSettingsAnnotation]; !ok {
+		original, err := json.Marshal(HostSettings{
+			RootDeviceHints: bmh.Spec.RootDeviceHints,
+			RAID:            bmh.Spec.RAID,
+			Firmware:        bmh.Spec.Firmware,
+		})
+		if err != nil {
+			return err
+		}
+
+		annotations := bmh.GetAnnotations()
+		if annotations == nil {
+			annotations = map[string]string{}
+		}
+		annotations[HostSettingsAnnotation] = string(original)
+		bmh.SetAnnotations(annotations)
+	}
+
+	if instance.Spec.RootDeviceHints != nil {
+		bmh.Spec.RootDeviceHints = instance.Spec.RootDeviceHints.DeepCopy()
+	}
+	if instance.Spec.RAID != nil {
+		bmh.Spec.RAID = instance.Spec.RAID.DeepCopy()
+	}
+	if instance.Spec.Firmware != nil {
+		bmh.Spec.Firmware = instance.Spec.Firmware.DeepCopy()
+	}
+
+	return nil
+}
+
+// RevertHostSettings - restore the root device hints, RAID and firmware settings the BaremetalHost
+// had before SetHostSettings was called and remove the HostSettingsAnnotation
+func RevertHostSettings(
+	bmh *metal3v1.BareMetalHost,
+) error {
+	annotations := bmh.GetAnnotations()
+
+	val, ok := annotations[HostSettingsAnnotation]
+	if !ok {
+		return nil
+	}
+
+	original := HostSettings{}
+	if err := json.Unmarshal([]byte(val), &original); err != nil {
+		return err
+	}
+
+	bmh.Spec.RootDeviceHints = original.RootDeviceHints
+	bmh.Spec.RAID = original.RAID
+	bmh.Spec.Firmware = original.Firmware
+
+	delete(annotations, HostSettingsAnnotation)
+	bmh.SetAnnotations(annotations)
+
+	return nil
+}
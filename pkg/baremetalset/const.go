@@ -28,4 +28,8 @@ const (
 
 	// FinalizerName -
 	FinalizerName = "baremetalsets.osp-director.openstack.org"
+
+	// HostSettingsAnnotation - annotation on the BaremetalHost holding its root device hints, RAID and firmware
+	// settings from before they got replaced by the ones of the OpenStackBaremetalSet
+	HostSettingsAnnotation = "osp-director.openstack.org/original-host-settings"
 )
@@ -54,3 +54,30 @@ func DistributePods(
 		},
 	}
 }
+
+// RequirePodsDistributed - returns rule to ensure that two replicas of the same selector
+// never run on the same topology domain
+func RequirePodsDistributed(
+	selectorKey string,
+	selectorValues []string,
+	topologyKey string,
+) *corev1.Affinity {
+	return &corev1.Affinity{
+		PodAntiAffinity: &corev1.PodAntiAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution: []corev1.PodAffinityTerm{
+				{
+					LabelSelector: &metav1.LabelSelector{
+						MatchExpressions: []metav1.LabelSelectorRequirement{
+							{
+								Key:      selectorKey,
+								Operator: metav1.LabelSelectorOpIn,
+								Values:   selectorValues,
+							},
+						},
+					},
+					TopologyKey: topologyKey,
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,148 @@
+/*
+Copyright 2022 Red Hat
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"mime/multipart"
+	"net/textproto"
+	"strings"
+
+	"github.com/openstack-k8s-operators/osp-director-operator/api/shared"
+)
+
+const (
+	// cloudInitBoundary - fixed MIME boundary, so the rendered user-data only changes if a part changes
+	cloudInitBoundary = "===============osp-director-operator-cloudinit=="
+	// cloudInitMergeType - append to the lists of the rendered cloud-config, e.g. runcmd,
+	// instead of replacing them, which is the cloud-init default
+	cloudInitMergeType = "list(append)+dict(no_replace,recurse_list)+str()"
+	// cloudInitJinjaHeader - header of user-data which is a jinja template
+	cloudInitJinjaHeader = "## template: jinja"
+)
+
+// CloudInitPartData - the data of a cloud-init part, read from the inline content or the referenced secret
+type CloudInitPartData struct {
+	Name        string
+	ContentType shared.CloudInitPartContentType
+	Data        string
+}
+
+// GetCloudInitParts - get and validate the data of the cloud-init parts
+func GetCloudInitParts(
+	ctx context.Context,
+	r ReconcilerCommon,
+	namespace string,
+	parts []shared.CloudInitPart,
+) ([]CloudInitPartData, error) {
+	partsData := []CloudInitPartData{}
+
+	for _, part := range parts {
+		data := part.Content
+		if part.SecretRef != nil {
+			secret, _, err := GetSecret(ctx, r, part.SecretRef.Name, namespace)
+			if err != nil {
+				return nil, fmt.Errorf("cloudInitPart %s: failed to get secret %s: %w", part.Name, part.SecretRef.Name, err)
+			}
+
+			value, ok := secret.Data[part.SecretRef.Key]
+			if !ok {
+				return nil, fmt.Errorf("cloudInitPart %s: key %s not found in secret %s", part.Name, part.SecretRef.Key, part.SecretRef.Name)
+			}
+			data = string(value)
+		}
+
+		if err := shared.ValidateCloudInitPartData(part.ContentType, data); err != nil {
+			return nil, fmt.Errorf("cloudInitPart %s: %w", part.Name, err)
+		}
+
+		partsData = append(partsData, CloudInitPartData{
+			Name:        part.Name,
+			ContentType: part.ContentType,
+			Data:        data,
+		})
+	}
+
+	return partsData, nil
+}
+
+// MergeCloudInitUserData - merge the rendered user-data and the cloud-init parts into a multipart MIME
+// message. The cloud-config parts get appended to the lists of the rendered cloud-config. Without parts
+// the rendered user-data is returned unchanged.
+func MergeCloudInitUserData(userData string, filename string, parts []CloudInitPartData) (string, error) {
+	if len(parts) == 0 {
+		return userData, nil
+	}
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	if err := writer.SetBoundary(cloudInitBoundary); err != nil {
+		return "", err
+	}
+
+	// user-data rendered from a jinja template needs the jinja2 content type within the MIME message
+	userDataType := string(shared.CloudInitPartCloudConfig)
+	if strings.HasPrefix(userData, cloudInitJinjaHeader) {
+		userDataType = "text/jinja2"
+	}
+
+	if err := writeCloudInitPart(writer, filename, userDataType, userData, false); err != nil {
+		return "", err
+	}
+
+	for _, part := range parts {
+		contentType := string(part.ContentType)
+		if contentType == "" {
+			contentType = string(shared.CloudInitPartCloudConfig)
+		}
+
+		merge := part.ContentType != shared.CloudInitPartShellScript
+		if err := writeCloudInitPart(writer, part.Name, contentType, part.Data, merge); err != nil {
+			return "", err
+		}
+	}
+
+	if err := writer.Close(); err != nil {
+		return "", err
+	}
+
+	header := fmt.Sprintf("Content-Type: multipart/mixed; boundary=\"%s\"\nMIME-Version: 1.0\n\n", cloudInitBoundary)
+
+	return header + body.String(), nil
+}
+
+// writeCloudInitPart - add a part to the multipart MIME user-data
+func writeCloudInitPart(writer *multipart.Writer, filename string, contentType string, data string, merge bool) error {
+	header := textproto.MIMEHeader{}
+	header.Set("Content-Type", fmt.Sprintf("%s; charset=\"utf-8\"", contentType))
+	header.Set("MIME-Version", "1.0")
+	header.Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", filename))
+	if merge {
+		header.Set("Merge-Type", cloudInitMergeType)
+	}
+
+	part, err := writer.CreatePart(header)
+	if err != nil {
+		return err
+	}
+
+	_, err = part.Write([]byte(data))
+
+	return err
+}
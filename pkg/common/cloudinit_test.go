@@ -0,0 +1,77 @@
+package common //revive:disable:var-naming
+
+import (
+	"io"
+	"mime"
+	"mime/multipart"
+	"strings"
+	"testing"
+
+	. "github.com/onsi/gomega" //revive:disable:dot-imports
+	"github.com/openstack-k8s-operators/osp-director-operator/api/shared"
+)
+
+func TestMergeCloudInitUserData(t *testing.T) {
+	userData := "## template: jinja\n#cloud-config\nfqdn: {{ v1.local_hostname }}\n"
+
+	t.Run("no parts", func(t *testing.T) {
+		g := NewWithT(t)
+
+		merged, err := MergeCloudInitUserData(userData, "userdata", nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(merged).To(Equal(userData))
+	})
+
+	t.Run("parts", func(t *testing.T) {
+		g := NewWithT(t)
+
+		parts := []CloudInitPartData{
+			{Name: "ntp", ContentType: shared.CloudInitPartCloudConfig, Data: "#cloud-config\nntp:\n  servers: [clock.example.com]\n"},
+			{Name: "proxy", ContentType: shared.CloudInitPartShellScript, Data: "#!/bin/bash\necho proxy\n"},
+		}
+
+		merged, err := MergeCloudInitUserData(userData, "userdata", parts)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		// the output is stable, so the user-data secret only changes if a part changes
+		again, err := MergeCloudInitUserData(userData, "userdata", parts)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(again).To(Equal(merged))
+
+		header, body, found := strings.Cut(merged, "\n\n")
+		g.Expect(found).To(BeTrue())
+		mediaType, params, err := mime.ParseMediaType(strings.TrimPrefix(strings.Split(header, "\n")[0], "Content-Type: "))
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(mediaType).To(Equal("multipart/mixed"))
+
+		type mimePart struct {
+			filename    string
+			contentType string
+			mergeType   string
+			data        string
+		}
+		got := []mimePart{}
+		reader := multipart.NewReader(strings.NewReader(body), params["boundary"])
+		for {
+			part, err := reader.NextPart()
+			if err == io.EOF {
+				break
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			data, err := io.ReadAll(part)
+			g.Expect(err).ToNot(HaveOccurred())
+			got = append(got, mimePart{
+				filename:    part.FileName(),
+				contentType: part.Header.Get("Content-Type"),
+				mergeType:   part.Header.Get("Merge-Type"),
+				data:        string(data),
+			})
+		}
+
+		g.Expect(got).To(Equal([]mimePart{
+			{filename: "userdata", contentType: "text/jinja2; charset=\"utf-8\"", data: userData},
+			{filename: "ntp", contentType: "text/cloud-config; charset=\"utf-8\"", mergeType: cloudInitMergeType, data: parts[0].Data},
+			{filename: "proxy", contentType: "text/x-shellscript; charset=\"utf-8\"", data: parts[1].Data},
+		}))
+	})
+}
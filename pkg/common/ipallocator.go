@@ -0,0 +1,309 @@
+/*
+Copyright 2022 Red Hat
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common //revive:disable:var-naming
+
+import (
+	"math"
+	"math/big"
+	"math/bits"
+	"net"
+	"sort"
+
+	"github.com/openstack-k8s-operators/osp-director-operator/api/shared"
+)
+
+const (
+	// ipBlockWords - number of 64 bit words of a bitmap block
+	ipBlockWords = 64
+	// ipBlockSize - number of addresses of a bitmap block
+	ipBlockSize = ipBlockWords * 64
+)
+
+// ipBlock - bitmap of the used addresses of a block of the range
+type ipBlock struct {
+	words [ipBlockWords]uint64
+	used  int
+}
+
+// ipInterval - excluded offsets of the range, including start and end
+type ipInterval struct {
+	start uint64
+	end   uint64
+}
+
+// IPAllocator - assigns the free IPs of an allocation range in ascending order. The used addresses
+// are stored in a sparse bitmap of their offset within the range, so the cost of an assignment
+// depends on the number of used addresses and not on the size of the range, e.g. a /64.
+//
+// Like the previous whereabouts based assignment, addresses with a last byte of 0 are skipped.
+// Ranges with more than 2^64 addresses are limited to their first 2^64 addresses.
+type IPAllocator struct {
+	ipnet    net.IPNet
+	first    *big.Int
+	last     *big.Int
+	size     uint64
+	blocks   map[uint64]*ipBlock
+	excluded []ipInterval
+	// next - lowest offset which might be free
+	next uint64
+}
+
+// NewIPAllocator - create an allocator for the range of the ipnet. Without rangeEnd the range ends
+// before the broadcast address of the ipnet.
+func NewIPAllocator(
+	ipnet net.IPNet,
+	rangeStart net.IP,
+	rangeEnd net.IP,
+	excludeRanges []string,
+) (*IPAllocator, error) {
+	firstip := rangeStart
+	lastip := rangeEnd
+	if rangeEnd == nil {
+		var err error
+		firstip, lastip, err = GetIPRange(rangeStart, ipnet)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	a := &IPAllocator{
+		ipnet:  ipnet,
+		first:  IPToBigInt(firstip.To16()),
+		last:   IPToBigInt(lastip.To16()),
+		blocks: map[uint64]*ipBlock{},
+	}
+
+	if a.last.Cmp(a.first) < 0 {
+		return nil, AssignmentError{firstip, lastip, ipnet}
+	}
+
+	// size - 1 of the range, limited to the uint64 offsets
+	sizeMinusOne := new(big.Int).Sub(a.last, a.first)
+	if !sizeMinusOne.IsUint64() {
+		sizeMinusOne.SetUint64(math.MaxUint64)
+		a.last.Add(a.first, sizeMinusOne)
+	}
+	a.size = sizeMinusOne.Uint64()
+
+	for _, excludeRange := range excludeRanges {
+		start, end, err := shared.ParseExcludeRange(excludeRange)
+		if err != nil {
+			return nil, err
+		}
+		a.exclude(IPToBigInt(start.To16()), IPToBigInt(end.To16()))
+	}
+	a.mergeExcluded()
+
+	return a, nil
+}
+
+// Reserve - mark the IP as used, IPs outside of the range get ignored
+func (a *IPAllocator) Reserve(ip net.IP) {
+	offset, ok := a.offset(ip)
+	if !ok {
+		return
+	}
+
+	block, ok := a.blocks[offset/ipBlockSize]
+	if !ok {
+		block = &ipBlock{}
+		a.blocks[offset/ipBlockSize] = block
+	}
+
+	index := offset % ipBlockSize
+	mask := uint64(1) << (index % 64)
+	if block.words[index/64]&mask == 0 {
+		block.words[index/64] |= mask
+		block.used++
+	}
+}
+
+// IsReserved - check if the IP is marked as used
+func (a *IPAllocator) IsReserved(ip net.IP) bool {
+	offset, ok := a.offset(ip)
+	if !ok {
+		return false
+	}
+
+	return a.isUsed(offset)
+}
+
+// Allocate - get the lowest free IP of the range and mark it as used
+func (a *IPAllocator) Allocate() (net.IP, error) {
+	offset := a.next
+	for {
+		free, ok := a.nextFree(offset)
+		if !ok {
+			return nil, AssignmentError{a.ip(0), a.ip(a.size), a.ipnet}
+		}
+
+		// skip excluded addresses
+		if interval, excluded := a.excludedInterval(free); excluded {
+			if interval.end >= a.size {
+				return nil, AssignmentError{a.ip(0), a.ip(a.size), a.ipnet}
+			}
+			offset = interval.end + 1
+			continue
+		}
+
+		// skip addresses with a last byte of 0
+		ip := a.ip(free)
+		if ip[net.IPv6len-1] == 0 {
+			if free >= a.size {
+				return nil, AssignmentError{a.ip(0), a.ip(a.size), a.ipnet}
+			}
+			offset = free + 1
+			continue
+		}
+
+		a.Reserve(ip)
+		// all addresses below are either used or skipped
+		a.next = free
+
+		return ip, nil
+	}
+}
+
+// nextFree - get the lowest offset which is not marked as used, starting at offset
+func (a *IPAllocator) nextFree(offset uint64) (uint64, bool) {
+	for {
+		if offset > a.size {
+			return 0, false
+		}
+
+		blockIndex := offset / ipBlockSize
+		block, ok := a.blocks[blockIndex]
+		if !ok {
+			return offset, true
+		}
+
+		if block.used < ipBlockSize {
+			for index := offset % ipBlockSize; index < ipBlockSize; {
+				word := ^block.words[index/64] >> (index % 64)
+				if word != 0 {
+					free := blockIndex*ipBlockSize + index + uint64(bits.TrailingZeros64(word))
+					if free > a.size {
+						return 0, false
+					}
+					return free, true
+				}
+				index = (index/64 + 1) * 64
+			}
+		}
+
+		// block is full, continue with the next one
+		if blockIndex == math.MaxUint64/ipBlockSize {
+			return 0, false
+		}
+		offset = (blockIndex + 1) * ipBlockSize
+	}
+}
+
+// isUsed - check if the offset is marked as used
+func (a *IPAllocator) isUsed(offset uint64) bool {
+	block, ok := a.blocks[offset/ipBlockSize]
+	if !ok {
+		return false
+	}
+	index := offset % ipBlockSize
+
+	return block.words[index/64]&(uint64(1)<<(index%64)) != 0
+}
+
+// exclude - add the addresses from start to end to the excluded intervals
+func (a *IPAllocator) exclude(start *big.Int, end *big.Int) {
+	if end.Cmp(a.first) < 0 || start.Cmp(a.last) > 0 {
+		return
+	}
+
+	startOffset := uint64(0)
+	if start.Cmp(a.first) > 0 {
+		startOffset = new(big.Int).Sub(start, a.first).Uint64()
+	}
+	endOffset := a.size
+	if end.Cmp(a.last) < 0 {
+		endOffset = new(big.Int).Sub(end, a.first).Uint64()
+	}
+
+	a.excluded = append(a.excluded, ipInterval{start: startOffset, end: endOffset})
+}
+
+// ExcludedCount - get the number of excluded addresses within the range, limited to the max uint64
+func (a *IPAllocator) ExcludedCount() uint64 {
+	count := uint64(0)
+	for _, interval := range a.excluded {
+		n := interval.end - interval.start
+		if n == math.MaxUint64 || count > math.MaxUint64-n-1 {
+			return math.MaxUint64
+		}
+		count += n + 1
+	}
+
+	return count
+}
+
+// mergeExcluded - sort and merge the overlapping excluded intervals
+func (a *IPAllocator) mergeExcluded() {
+	sort.Slice(a.excluded, func(i, j int) bool {
+		return a.excluded[i].start < a.excluded[j].start
+	})
+
+	merged := []ipInterval{}
+	for _, interval := range a.excluded {
+		last := len(merged) - 1
+		if last >= 0 && (merged[last].end == math.MaxUint64 || interval.start <= merged[last].end+1) {
+			merged[last].end = max(merged[last].end, interval.end)
+			continue
+		}
+		merged = append(merged, interval)
+	}
+	a.excluded = merged
+}
+
+// excludedInterval - get the excluded interval which contains the offset
+func (a *IPAllocator) excludedInterval(offset uint64) (ipInterval, bool) {
+	i := sort.Search(len(a.excluded), func(i int) bool {
+		return a.excluded[i].end >= offset
+	})
+	if i < len(a.excluded) && a.excluded[i].start <= offset {
+		return a.excluded[i], true
+	}
+
+	return ipInterval{}, false
+}
+
+// offset - get the offset of the IP within the range
+func (a *IPAllocator) offset(ip net.IP) (uint64, bool) {
+	if ip == nil || ip.To16() == nil {
+		return 0, false
+	}
+
+	ipInt := IPToBigInt(ip.To16())
+	if ipInt.Cmp(a.first) < 0 || ipInt.Cmp(a.last) > 0 {
+		return 0, false
+	}
+
+	return ipInt.Sub(ipInt, a.first).Uint64(), true
+}
+
+// ip - get the IP at the offset of the range
+func (a *IPAllocator) ip(offset uint64) net.IP {
+	ipInt := new(big.Int).SetUint64(offset)
+	ipInt.Add(ipInt, a.first)
+
+	return ipInt.FillBytes(make([]byte, net.IPv6len))
+}
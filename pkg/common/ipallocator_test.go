@@ -0,0 +1,339 @@
+package common //revive:disable:var-naming
+
+import (
+	"fmt"
+	"math/big"
+	"math/rand"
+	"net"
+	"testing"
+
+	. "github.com/onsi/gomega" //revive:disable:dot-imports
+	ospdirectorv1beta1 "github.com/openstack-k8s-operators/osp-director-operator/api/v1beta1"
+)
+
+// linearAssignment - the previous whereabouts based assignment, which checks every address of the range
+// against the reservations, as reference for the IPAllocator
+func linearAssignment(assignIPDetails AssignIPDetails) (net.IP, error) {
+	firstip := assignIPDetails.RangeStart
+	lastip := assignIPDetails.RangeEnd
+	if lastip == nil {
+		var err error
+		firstip, lastip, err = GetIPRange(assignIPDetails.RangeStart, assignIPDetails.IPnet)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	reserved := map[string]bool{}
+	for _, r := range assignIPDetails.Reservelist {
+		reserved[net.ParseIP(r.IP).String()] = true
+	}
+
+	excluded := []*net.IPNet{}
+	for _, v := range assignIPDetails.ExcludeRanges {
+		_, subnet, _ := net.ParseCIDR(v)
+		excluded = append(excluded, subnet)
+	}
+
+MAINITERATION:
+	for i := IPToBigInt(firstip); IPToBigInt(lastip).Cmp(i) >= 0; i.Add(i, big.NewInt(1)) {
+		ip := net.IP(i.FillBytes(make([]byte, net.IPv6len)))
+		if reserved[ip.String()] || ip[net.IPv6len-1] == 0 {
+			continue
+		}
+		for _, subnet := range excluded {
+			if subnet.Contains(ip) {
+				continue MAINITERATION
+			}
+		}
+
+		return ip, nil
+	}
+
+	return nil, fmt.Errorf("no free IP")
+}
+
+func TestIPAllocator(t *testing.T) {
+	tests := []struct {
+		name          string
+		cidr          string
+		start         string
+		end           string
+		excludeRanges []string
+		reserved      []string
+		want          []string
+	}{
+		{
+			name:  "IPv4 empty range",
+			cidr:  "172.17.0.0/24",
+			start: "172.17.0.10",
+			end:   "172.17.0.250",
+			want:  []string{"172.17.0.10", "172.17.0.11"},
+		},
+		{
+			name:     "IPv4 reserved addresses",
+			cidr:     "172.17.0.0/24",
+			start:    "172.17.0.10",
+			end:      "172.17.0.250",
+			reserved: []string{"172.17.0.10", "172.17.0.12", "172.17.0.5"},
+			want:     []string{"172.17.0.11", "172.17.0.13"},
+		},
+		{
+			name:  "IPv4 skip last byte 0",
+			cidr:  "172.17.0.0/16",
+			start: "172.17.0.254",
+			end:   "172.17.1.250",
+			want:  []string{"172.17.0.254", "172.17.0.255", "172.17.1.1"},
+		},
+		{
+			name:          "IPv4 exclude ranges",
+			cidr:          "172.17.0.0/24",
+			start:         "172.17.0.10",
+			end:           "172.17.0.250",
+			excludeRanges: []string{"172.17.0.8/30", "172.17.0.12/31"},
+			reserved:      []string{"172.17.0.14"},
+			want:          []string{"172.17.0.15", "172.17.0.16"},
+		},
+		{
+			name:          "IPv4 exclude start-end pairs",
+			cidr:          "172.17.0.0/24",
+			start:         "172.17.0.10",
+			end:           "172.17.0.250",
+			excludeRanges: []string{"172.17.0.10-172.17.0.20", "172.17.0.15-172.17.0.22"},
+			want:          []string{"172.17.0.23", "172.17.0.24"},
+		},
+		{
+			name:  "IPv4 range without end",
+			cidr:  "172.17.0.0/24",
+			start: "172.17.0.252",
+			want:  []string{"172.17.0.252", "172.17.0.253", "172.17.0.254"},
+		},
+		{
+			name:     "IPv4 range exhausted",
+			cidr:     "172.17.0.0/24",
+			start:    "172.17.0.10",
+			end:      "172.17.0.11",
+			reserved: []string{"172.17.0.10"},
+			want:     []string{"172.17.0.11"},
+		},
+		{
+			name:     "IPv6 reserved addresses",
+			cidr:     "fd00:fd00:fd00:2000::/64",
+			start:    "fd00:fd00:fd00:2000::fe",
+			end:      "fd00:fd00:fd00:2000:ffff:ffff:ffff:fffe",
+			reserved: []string{"fd00:fd00:fd00:2000::fe", "fd00:fd00:fd00:2000:0:0:0:101"},
+			want:     []string{"fd00:fd00:fd00:2000::ff", "fd00:fd00:fd00:2000::102"},
+		},
+		{
+			name:          "IPv6 exclude ranges",
+			cidr:          "fd00:fd00:fd00:2000::/64",
+			start:         "fd00:fd00:fd00:2000::10",
+			end:           "fd00:fd00:fd00:2000:ffff:ffff:ffff:fffe",
+			excludeRanges: []string{"fd00:fd00:fd00:2000::/120"},
+			want:          []string{"fd00:fd00:fd00:2000::101", "fd00:fd00:fd00:2000::102"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			_, ipnet, err := net.ParseCIDR(tt.cidr)
+			g.Expect(err).ToNot(HaveOccurred())
+
+			var end net.IP
+			if tt.end != "" {
+				end = net.ParseIP(tt.end)
+			}
+
+			allocator, err := NewIPAllocator(*ipnet, net.ParseIP(tt.start), end, tt.excludeRanges)
+			g.Expect(err).ToNot(HaveOccurred())
+			for _, ip := range tt.reserved {
+				allocator.Reserve(net.ParseIP(ip))
+			}
+
+			for _, want := range tt.want {
+				ip, err := allocator.Allocate()
+				g.Expect(err).ToNot(HaveOccurred())
+				g.Expect(ip.String()).To(Equal(want))
+				g.Expect(allocator.IsReserved(ip)).To(BeTrue())
+			}
+
+			if tt.end != "" && tt.end == tt.want[len(tt.want)-1] {
+				_, err := allocator.Allocate()
+				g.Expect(err).To(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestIPAllocatorExcludedCount(t *testing.T) {
+	g := NewWithT(t)
+
+	_, ipnet, err := net.ParseCIDR("172.17.0.0/24")
+	g.Expect(err).ToNot(HaveOccurred())
+
+	// the parts of the exclude ranges outside of the allocation range do not count
+	allocator, err := NewIPAllocator(
+		*ipnet,
+		net.ParseIP("172.17.0.10"),
+		net.ParseIP("172.17.0.250"),
+		[]string{"172.17.0.0/28", "172.17.0.12-172.17.0.20", "172.17.0.100-172.17.0.109", "172.17.0.248/29"},
+	)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(allocator.ExcludedCount()).To(Equal(uint64(11 + 10 + 3)))
+}
+
+func TestIPAllocatorMatchesLinearAssignment(t *testing.T) {
+	tests := []struct {
+		name          string
+		cidr          string
+		start         string
+		end           string
+		excludeRanges []string
+	}{
+		{
+			name:  "IPv4",
+			cidr:  "172.17.0.0/22",
+			start: "172.17.0.10",
+			end:   "172.17.3.250",
+		},
+		{
+			name:          "IPv4 exclude ranges",
+			cidr:          "172.17.0.0/22",
+			start:         "172.17.0.10",
+			end:           "172.17.3.250",
+			excludeRanges: []string{"172.17.0.64/26", "172.17.1.0/24", "172.17.0.96/27"},
+		},
+		{
+			name:          "IPv6",
+			cidr:          "fd00:fd00:fd00:2000::/64",
+			start:         "fd00:fd00:fd00:2000::10",
+			end:           "fd00:fd00:fd00:2000::1000",
+			excludeRanges: []string{"fd00:fd00:fd00:2000::200/120"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			_, ipnet, err := net.ParseCIDR(tt.cidr)
+			g.Expect(err).ToNot(HaveOccurred())
+			start := net.ParseIP(tt.start)
+			end := net.ParseIP(tt.end)
+
+			// reserve a random half of the first addresses of the range
+			random := rand.New(rand.NewSource(1))
+			reservations := []ospdirectorv1beta1.IPReservation{}
+			for i := int64(0); i < 1500; i++ {
+				if random.Intn(2) == 0 {
+					ip := new(big.Int).Add(IPToBigInt(start.To16()), big.NewInt(i))
+					reservations = append(reservations, ospdirectorv1beta1.IPReservation{
+						IP: net.IP(ip.FillBytes(make([]byte, net.IPv6len))).String(),
+					})
+				}
+			}
+
+			assignIPDetails := AssignIPDetails{
+				IPnet:         *ipnet,
+				RangeStart:    start,
+				RangeEnd:      end,
+				Reservelist:   reservations,
+				ExcludeRanges: tt.excludeRanges,
+			}
+
+			allocator, err := NewIPAllocator(*ipnet, start, end, tt.excludeRanges)
+			g.Expect(err).ToNot(HaveOccurred())
+			for _, r := range reservations {
+				allocator.Reserve(net.ParseIP(r.IP))
+			}
+
+			// assign IPs for new hosts one by one, like ensureIPs does
+			for i := 0; i < 200; i++ {
+				want, err := linearAssignment(assignIPDetails)
+				g.Expect(err).ToNot(HaveOccurred())
+
+				ip, reservelist, err := IterateForAssignment(assignIPDetails)
+				g.Expect(err).ToNot(HaveOccurred())
+				g.Expect(ip.String()).To(Equal(want.String()))
+				g.Expect(reservelist).To(HaveLen(1))
+
+				ip, err = allocator.Allocate()
+				g.Expect(err).ToNot(HaveOccurred())
+				g.Expect(ip.String()).To(Equal(want.String()))
+
+				assignIPDetails.Reservelist = append(assignIPDetails.Reservelist, ospdirectorv1beta1.IPReservation{IP: want.String()})
+			}
+		})
+	}
+}
+
+// benchmarkIPv6Reservations - reservations of the given count of hosts in a /64 subnet
+func benchmarkIPv6Reservations(b *testing.B, hosts int) (net.IPNet, net.IP, net.IP, []ospdirectorv1beta1.IPReservation) {
+	_, ipnet, err := net.ParseCIDR("fd00:fd00:fd00:2000::/64")
+	if err != nil {
+		b.Fatal(err)
+	}
+	start := net.ParseIP("fd00:fd00:fd00:2000::10")
+	end := net.ParseIP("fd00:fd00:fd00:2000:ffff:ffff:ffff:fffe")
+
+	allocator, err := NewIPAllocator(*ipnet, start, end, nil)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	reservations := []ospdirectorv1beta1.IPReservation{}
+	for i := 0; i < hosts; i++ {
+		ip, err := allocator.Allocate()
+		if err != nil {
+			b.Fatal(err)
+		}
+		reservations = append(reservations, ospdirectorv1beta1.IPReservation{IP: ip.String()})
+	}
+
+	return *ipnet, start, end, reservations
+}
+
+func BenchmarkIPAllocatorIPv6(b *testing.B) {
+	for _, hosts := range []int{1000, 5000} {
+		ipnet, start, end, reservations := benchmarkIPv6Reservations(b, hosts)
+
+		// assign the IPs of 100 new hosts with one allocator, like ensureIPs does
+		b.Run(fmt.Sprintf("allocator-%d-hosts", hosts), func(b *testing.B) {
+			for n := 0; n < b.N; n++ {
+				allocator, err := NewIPAllocator(ipnet, start, end, nil)
+				if err != nil {
+					b.Fatal(err)
+				}
+				for _, r := range reservations {
+					allocator.Reserve(net.ParseIP(r.IP))
+				}
+				for i := 0; i < 100; i++ {
+					if _, err := allocator.Allocate(); err != nil {
+						b.Fatal(err)
+					}
+				}
+			}
+		})
+
+		// assign the IPs of 100 new hosts with the previous linear assignment
+		b.Run(fmt.Sprintf("linear-%d-hosts", hosts), func(b *testing.B) {
+			for n := 0; n < b.N; n++ {
+				assignIPDetails := AssignIPDetails{
+					IPnet:       ipnet,
+					RangeStart:  start,
+					RangeEnd:    end,
+					Reservelist: reservations,
+				}
+				for i := 0; i < 100; i++ {
+					ip, err := linearAssignment(assignIPDetails)
+					if err != nil {
+						b.Fatal(err)
+					}
+					assignIPDetails.Reservelist = append(assignIPDetails.Reservelist, ospdirectorv1beta1.IPReservation{IP: ip.String()})
+				}
+			}
+		})
+	}
+}
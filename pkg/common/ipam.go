@@ -49,85 +49,42 @@ func AssignIP(assignIPDetails AssignIPDetails) (net.IPNet, []ospdirectorv1beta1.
 	return net.IPNet{IP: newip, Mask: assignIPDetails.IPnet.Mask}, updatedreservelist, nil
 }
 
-// IterateForAssignment iterates given an IP/IPNet and a list of reserved IPs
+// IterateForAssignment gets the lowest free IP of the range, which is neither reserved nor excluded
 func IterateForAssignment(assignIPDetails AssignIPDetails) (net.IP, []ospdirectorv1beta1.IPReservation, error) {
-
-	firstip := assignIPDetails.RangeStart
-	var lastip net.IP
-	if assignIPDetails.RangeEnd != nil {
-		lastip = assignIPDetails.RangeEnd
-	} else {
-		var err error
-		firstip, lastip, err = GetIPRange(assignIPDetails.RangeStart, assignIPDetails.IPnet)
-		if err != nil {
-			//logging.Errorf("GetIPRange request failed with: %v", err)
-			return net.IP{}, assignIPDetails.Reservelist, err
-		}
+	allocator, err := NewIPAllocator(
+		assignIPDetails.IPnet,
+		assignIPDetails.RangeStart,
+		assignIPDetails.RangeEnd,
+		assignIPDetails.ExcludeRanges,
+	)
+	if err != nil {
+		return net.IP{}, assignIPDetails.RoleReservelist, err
 	}
-	//logging.Debugf("IterateForAssignment input >> ip: %v | ipnet: %v | first IP: %v | last IP: %v", rangeStart, ipnet, firstip, lastip)
 
-	reserved := make(map[string]bool)
 	for _, r := range assignIPDetails.Reservelist {
-		ip := BigIntToIP(*IPToBigInt(net.ParseIP(r.IP)))
-		reserved[ip.String()] = true
+		allocator.Reserve(net.ParseIP(r.IP))
 	}
 
-	excluded := []*net.IPNet{}
-	for _, v := range assignIPDetails.ExcludeRanges {
-		_, subnet, _ := net.ParseCIDR(v)
-		excluded = append(excluded, subnet)
-	}
-
-	// Iterate every IP address in the range
-	var assignedip net.IP
-	performedassignment := false
-MAINITERATION:
-	for i := IPToBigInt(firstip); IPToBigInt(lastip).Cmp(i) == 1 || IPToBigInt(lastip).Cmp(i) == 0; i.Add(i, big.NewInt(1)) {
-
-		assignedip = BigIntToIP(*i)
-		stringip := fmt.Sprint(assignedip)
-		// For each address see if it has been allocated
-		if reserved[stringip] {
-			// Continue if this IP is allocated.
-			continue
-		}
-
-		// We can try to work with the current IP
-		// However, let's skip 0-based addresses
-		// So go ahead and continue if the 4th/16th byte equals 0
-		ipbytes := i.Bytes()
-		if isIntIPv4(i) {
-			if ipbytes[5] == 0 {
-				continue
-			}
-		} else {
-			if ipbytes[15] == 0 {
-				continue
-			}
-		}
-
-		// Lastly, we need to check if this IP is within the range of excluded subnets
-		for _, subnet := range excluded {
-			if subnet.Contains(BigIntToIP(*i).To16()) {
-				continue MAINITERATION
-			}
-		}
-
-		// Ok, this one looks like we can assign it!
-		performedassignment = true
+	return AssignIPWithAllocator(allocator, assignIPDetails)
+}
 
-		assignIPDetails.RoleReservelist = append(assignIPDetails.RoleReservelist, ospdirectorv1beta1.IPReservation{
-			Hostname: assignIPDetails.Hostname,
-			IP:       assignedip.String(),
-			VIP:      assignIPDetails.VIP,
-			Deleted:  assignIPDetails.Deleted,
-		})
-		break
+// AssignIPWithAllocator gets the lowest free IP of the allocator and adds the reservation to the role reservations.
+// The allocator keeps track of the assigned IPs, so it can be re-used to assign the IPs of all hosts of a network.
+func AssignIPWithAllocator(
+	allocator *IPAllocator,
+	assignIPDetails AssignIPDetails,
+) (net.IP, []ospdirectorv1beta1.IPReservation, error) {
+	assignedip, err := allocator.Allocate()
+	if err != nil {
+		return net.IP{}, assignIPDetails.RoleReservelist, err
 	}
 
-	if !performedassignment {
-		return net.IP{}, assignIPDetails.RoleReservelist, AssignmentError{firstip, lastip, assignIPDetails.IPnet}
-	}
+	assignIPDetails.RoleReservelist = append(assignIPDetails.RoleReservelist, ospdirectorv1beta1.IPReservation{
+		Hostname: assignIPDetails.Hostname,
+		IP:       assignedip.String(),
+		VIP:      assignIPDetails.VIP,
+		Deleted:  assignIPDetails.Deleted,
+	})
 
 	return assignedip, assignIPDetails.RoleReservelist, nil
 }
@@ -193,10 +150,6 @@ func GetIPRange(ip net.IP, ipnet net.IPNet) (net.IP, net.IP, error) {
 
 }
 
-func isIntIPv4(checkipint *big.Int) bool {
-	return len(checkipint.Bytes()) != net.IPv6len
-}
-
 // BigIntToIP converts a big.Int to a net.IP
 func BigIntToIP(inipint big.Int) net.IP {
 	outip := net.IP(make([]byte, net.IPv6len))
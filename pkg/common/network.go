@@ -18,8 +18,11 @@ package common //revive:disable:var-naming
 
 import (
 	"context"
+	"fmt"
+	"strings"
 
 	networkv1 "github.com/k8snetworkplumbingwg/network-attachment-definition-client/pkg/apis/k8s.cni.cncf.io/v1"
+	"github.com/openstack-k8s-operators/osp-director-operator/api/shared"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -50,3 +53,70 @@ func GetAllNetworkAttachmentDefinitions(
 
 	return nadMap, nil
 }
+
+// netplanBondParameters - bonding driver option names which are named differently in netplan
+var netplanBondParameters = map[string]string{
+	"miimon":           "mii-monitor-interval",
+	"xmit_hash_policy": "transmit-hash-policy",
+	"lacp_rate":        "lacp-rate",
+	"ad_select":        "ad-select",
+	"updelay":          "up-delay",
+	"downdelay":        "down-delay",
+	"arp_interval":     "arp-interval",
+	"arp_ip_target":    "arp-ip-targets",
+	"primary_reselect": "primary-reselect-policy",
+	"fail_over_mac":    "fail-over-mac-policy",
+	"num_grat_arp":     "gratuitous-arp",
+	"lp_interval":      "learn-packet-interval",
+}
+
+// SetCtlplaneNetworkTemplateParameters - set the template parameters for the bond and VLAN
+// links of the ctlplane network in the cloud-init network data
+//
+// CtlplaneLink      - the link the ctlplane IP gets configured on
+// CtlplaneVlanLink  - the parent link of the VLAN
+// CtlplaneBond      - the bond, nil if none
+// CtlplaneBondNetplanParameters - the bond options with their netplan names
+// CtlplaneVlan      - the VLAN ID, 0 if none
+func SetCtlplaneNetworkTemplateParameters(
+	templateParameters map[string]interface{},
+	ctlplaneInterface string,
+	cfg *shared.CtlplaneNetworkConfig,
+) {
+	link := ctlplaneInterface
+	var bond *shared.CtlplaneBond
+	bondNetplanParameters := map[string]string{}
+	vlan := 0
+
+	if cfg != nil {
+		if cfg.Bond != nil {
+			bond = cfg.Bond.DeepCopy()
+			if bond.Name == "" {
+				bond.Name = "bond0"
+			}
+			if bond.Mode == "" {
+				bond.Mode = "802.3ad"
+			}
+			link = bond.Name
+
+			for key, value := range bond.Options {
+				if netplanKey, ok := netplanBondParameters[key]; ok {
+					bondNetplanParameters[netplanKey] = value
+				} else {
+					bondNetplanParameters[strings.ReplaceAll(key, "_", "-")] = value
+				}
+			}
+		}
+		vlan = cfg.VLAN
+	}
+
+	templateParameters["CtlplaneVlanLink"] = link
+	if vlan > 0 {
+		link = fmt.Sprintf("%s.%d", link, vlan)
+	}
+
+	templateParameters["CtlplaneLink"] = link
+	templateParameters["CtlplaneBond"] = bond
+	templateParameters["CtlplaneBondNetplanParameters"] = bondNetplanParameters
+	templateParameters["CtlplaneVlan"] = vlan
+}
@@ -34,6 +34,7 @@ type Pvc struct {
 	Labels       map[string]string
 	StorageClass string
 	AccessMode   []corev1.PersistentVolumeAccessMode
+	VolumeMode   string
 }
 
 // CreateOrUpdatePvc -
@@ -53,6 +54,11 @@ func CreateOrUpdatePvc(ctx context.Context, r ReconcilerCommon, obj metav1.Objec
 
 		pvc.Spec.StorageClassName = &pv.StorageClass
 		pvc.Spec.AccessModes = pv.AccessMode
+		// the volume mode is immutable
+		if pv.VolumeMode != "" && pvc.CreationTimestamp.IsZero() {
+			volumeMode := corev1.PersistentVolumeMode(pv.VolumeMode)
+			pvc.Spec.VolumeMode = &volumeMode
+		}
 
 		err := controllerutil.SetOwnerReference(obj, pvc, r.GetScheme())
 		if err != nil {
@@ -0,0 +1,119 @@
+/*
+Copyright 2022 Red Hat
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common //revive:disable:var-naming
+
+import (
+	"sort"
+
+	"github.com/openstack-k8s-operators/osp-director-operator/api/shared"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ScaleDownCandidate - host of a BaremetalSet or VMSet which can get removed on scale-down
+type ScaleDownCandidate struct {
+	// Name of the BareMetalHost or VirtualMachine
+	Name string
+	// Hostname OSP hostname of the host
+	Hostname string
+	// Provisioned time the host got provisioned
+	Provisioned metav1.Time
+	// Ready host is provisioned/running
+	Ready bool
+}
+
+// GetScaleDownHostsByName - get the names of up to count candidates listed in the scale-down
+// policy hostnames, in the order of the list. Candidates in exclude are skipped.
+func GetScaleDownHostsByName(
+	policy *shared.ScaleDownPolicy,
+	candidates []ScaleDownCandidate,
+	exclude []string,
+	count int,
+) []string {
+	removal := []string{}
+	if policy == nil {
+		return removal
+	}
+
+	for _, hostname := range policy.Hostnames {
+		if len(removal) >= count {
+			break
+		}
+
+		for _, c := range candidates {
+			if c.Hostname == hostname &&
+				!StringInSlice(c.Name, exclude) &&
+				!StringInSlice(c.Name, removal) {
+				removal = append(removal, c.Name)
+			}
+		}
+	}
+
+	return removal
+}
+
+// GetScaleDownHosts - get the names of count candidates to remove on scale-down. First the
+// ones listed in the scale-down policy hostnames, then the remaining ones in the order
+// of the policy type. Candidates in exclude are skipped.
+func GetScaleDownHosts(
+	policy *shared.ScaleDownPolicy,
+	candidates []ScaleDownCandidate,
+	exclude []string,
+	count int,
+) []string {
+	removal := GetScaleDownHostsByName(policy, candidates, exclude, count)
+	if policy == nil {
+		return removal
+	}
+
+	remaining := []ScaleDownCandidate{}
+	for _, c := range candidates {
+		if !StringInSlice(c.Name, exclude) && !StringInSlice(c.Name, removal) {
+			remaining = append(remaining, c)
+		}
+	}
+
+	sort.SliceStable(remaining, func(i, j int) bool {
+		a, b := remaining[i], remaining[j]
+
+		switch policy.Type {
+		case shared.ScaleDownPolicyNewest:
+			if !a.Provisioned.Equal(&b.Provisioned) {
+				return b.Provisioned.Before(&a.Provisioned)
+			}
+		case shared.ScaleDownPolicyOldest:
+			if !a.Provisioned.Equal(&b.Provisioned) {
+				return a.Provisioned.Before(&b.Provisioned)
+			}
+		case shared.ScaleDownPolicyNotReadyFirst:
+			if a.Ready != b.Ready {
+				return !a.Ready
+			}
+		}
+
+		// HighestIndex and tie breaker for the other policies
+		return shared.GetHostnameIndex(a.Hostname) > shared.GetHostnameIndex(b.Hostname)
+	})
+
+	for _, c := range remaining {
+		if len(removal) >= count {
+			break
+		}
+		removal = append(removal, c.Name)
+	}
+
+	return removal
+}
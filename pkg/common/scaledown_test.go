@@ -0,0 +1,84 @@
+package common //revive:disable:var-naming
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega" //revive:disable:dot-imports
+	"github.com/openstack-k8s-operators/osp-director-operator/api/shared"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestGetScaleDownHosts(t *testing.T) {
+
+	now := time.Now()
+	candidates := []ScaleDownCandidate{
+		{Name: "bmh-a", Hostname: "compute-0", Provisioned: metav1.NewTime(now.Add(-3 * time.Hour)), Ready: true},
+		{Name: "bmh-b", Hostname: "compute-1", Provisioned: metav1.NewTime(now.Add(-1 * time.Hour)), Ready: false},
+		{Name: "bmh-c", Hostname: "compute-2", Provisioned: metav1.NewTime(now.Add(-5 * time.Hour)), Ready: true},
+		{Name: "bmh-d", Hostname: "compute-10", Provisioned: metav1.NewTime(now.Add(-2 * time.Hour)), Ready: true},
+	}
+
+	tests := []struct {
+		name    string
+		policy  *shared.ScaleDownPolicy
+		exclude []string
+		count   int
+		want    []string
+	}{
+		{
+			name:   "no policy",
+			policy: nil,
+			count:  1,
+			want:   []string{},
+		},
+		{
+			name:   "highest index",
+			policy: &shared.ScaleDownPolicy{Type: shared.ScaleDownPolicyHighestIndex},
+			count:  2,
+			want:   []string{"bmh-d", "bmh-c"},
+		},
+		{
+			name:   "newest",
+			policy: &shared.ScaleDownPolicy{Type: shared.ScaleDownPolicyNewest},
+			count:  2,
+			want:   []string{"bmh-b", "bmh-d"},
+		},
+		{
+			name:   "oldest",
+			policy: &shared.ScaleDownPolicy{Type: shared.ScaleDownPolicyOldest},
+			count:  2,
+			want:   []string{"bmh-c", "bmh-a"},
+		},
+		{
+			name:   "not ready first",
+			policy: &shared.ScaleDownPolicy{Type: shared.ScaleDownPolicyNotReadyFirst},
+			count:  2,
+			want:   []string{"bmh-b", "bmh-d"},
+		},
+		{
+			name: "hostnames before policy type",
+			policy: &shared.ScaleDownPolicy{
+				Type:      shared.ScaleDownPolicyHighestIndex,
+				Hostnames: []string{"compute-0", "compute-99", "compute-1"},
+			},
+			count: 3,
+			want:  []string{"bmh-a", "bmh-b", "bmh-d"},
+		},
+		{
+			name:    "skip annotated hosts",
+			policy:  &shared.ScaleDownPolicy{Type: shared.ScaleDownPolicyHighestIndex},
+			exclude: []string{"bmh-d"},
+			count:   1,
+			want:    []string{"bmh-c"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			g.Expect(GetScaleDownHosts(tt.policy, candidates, tt.exclude, tt.count)).To(Equal(tt.want))
+		})
+	}
+}
@@ -28,6 +28,25 @@ func GetDesiredStateBridgeName(desiredStateBytes []byte) (string, error) {
 	return bridge, nil
 }
 
+// GetDesiredStateBridgeMTU - Get the MTU associated with the desiredState bridge interface, 0 if not set
+func GetDesiredStateBridgeMTU(desiredStateBytes []byte) (int, error) {
+	mtu := 0
+
+	jsonStr, err := GetDesiredStateAsString(desiredStateBytes)
+	if err != nil {
+		return 0, err
+	}
+
+	if gjson.Get(jsonStr, "interfaces.#.bridge").Exists() &&
+		gjson.Get(jsonStr, "interfaces.#.mtu").Exists() &&
+		len(gjson.Get(jsonStr, `interfaces.#(bridge).mtu`).Array()) > 0 {
+
+		mtu = int(gjson.Get(jsonStr, `interfaces.#(bridge).mtu`).Array()[0].Int())
+	}
+
+	return mtu, nil
+}
+
 // GetCurrentCondition - Get current condition with status == corev1.ConditionTrue
 func GetCurrentCondition(conditions nmstateshared.ConditionList) *nmstateshared.Condition {
 	for i, cond := range conditions {
@@ -37,6 +37,8 @@ const (
 	CloudAdminPersistentStorageSize = "4G"
 	// KollaSrcPersistentStorageSize - size in GB
 	KollaSrcPersistentStorageSize = "1G"
+	// Name - openstackclient name is atm fixed
+	Name = "openstackclient"
 	// Count - openstackclient count is atm fixed to 1
 	Count = 1
 	// Role - openstackclient has not tripleo role, set it as const
@@ -321,7 +321,8 @@ func createNetworksMap(
 				}
 
 				if shared.IsIPv6(net.ParseIP(ip)) {
-					network.IPv6 = true
+					// on a dual-stack subnet IPv4 stays the primary family of the ports
+					network.IPv6 = s.IPv4.Cidr == ""
 					subnetDetailsV6 = netDetailsType{
 						AllocationEnd:   s.IPv6.AllocationEnd,
 						AllocationStart: s.IPv6.AllocationStart,
@@ -541,6 +542,21 @@ func createRolesMap(
 								IPAddrSubnet: fmt.Sprintf("%s/%d", reservation.IP, cidrSuffix),
 								Network:      hostRole.Networks[osnet.Spec.NameLower],
 							}
+
+							//
+							// IPv6 address of a dual-stack network
+							//
+							if osnet.Spec.IPv6 != nil && reservation.IPv6 != "" {
+								_, ipv6CidrSuffix, err := common.GetCidrParts(osnet.Spec.IPv6.Cidr)
+								if err != nil {
+									return err
+								}
+
+								ipAddr := hostRole.Nodes[reservation.Hostname].IPaddr[nameLower]
+								ipAddr.IPv6addr = reservation.IPv6
+								ipAddr.IPv6AddrURI = fmt.Sprintf("[%s]", reservation.IPv6)
+								ipAddr.IPv6AddrSubnet = fmt.Sprintf("%s/%d", reservation.IPv6, ipv6CidrSuffix)
+							}
 						}
 
 						*hostnameMapIndex++
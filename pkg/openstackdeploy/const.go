@@ -23,4 +23,7 @@ const (
 
 	// ConfigMapBasename The basename prefix for exports data ConfigMaps
 	ConfigMapBasename = "tripleo-exports-"
+
+	// SnapshotSetConfigVersionLength - length of the config version in the name of a snapshot set
+	SnapshotSetConfigVersionLength = 10
 )
@@ -0,0 +1,56 @@
+/*
+Copyright 2022 Red Hat
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package openstackdeploy
+
+import (
+	"path"
+	"regexp"
+	"strings"
+)
+
+// LimitMatches - one of the host or group patterns of the ansible limit matches one of the names.
+// An empty limit matches all names. Excluded patterns (!pattern) are ignored, so a host is rather
+// matched once too often than missed.
+func LimitMatches(limit string, names ...string) bool {
+	if strings.TrimSpace(limit) == "" {
+		return true
+	}
+
+	for _, pattern := range strings.FieldsFunc(limit, func(r rune) bool {
+		return r == ',' || r == ':'
+	}) {
+		pattern = strings.TrimPrefix(strings.TrimSpace(pattern), "&")
+		if pattern == "" || strings.HasPrefix(pattern, "!") {
+			continue
+		}
+
+		for _, name := range names {
+			if strings.HasPrefix(pattern, "~") {
+				if re, err := regexp.Compile(pattern[1:]); err == nil && re.MatchString(name) {
+					return true
+				}
+				continue
+			}
+
+			if matched, err := path.Match(pattern, name); err == nil && matched {
+				return true
+			}
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,33 @@
+package openstackdeploy
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega" //revive:disable:dot-imports
+)
+
+func TestLimitMatches(t *testing.T) {
+	tests := []struct {
+		name  string
+		limit string
+		names []string
+		want  bool
+	}{
+		{name: "empty limit", limit: "", names: []string{"Controller", "controller-0"}, want: true},
+		{name: "role group", limit: "Controller", names: []string{"Controller", "controller-0"}, want: true},
+		{name: "hostname", limit: "Compute,controller-1", names: []string{"Controller", "controller-0", "controller-1"}, want: true},
+		{name: "wildcard", limit: "controller-*", names: []string{"Controller", "controller-2"}, want: true},
+		{name: "regex", limit: "~^ctrl[0-9]+$", names: []string{"ctrl12"}, want: true},
+		{name: "intersection", limit: "Compute:&compute-0", names: []string{"Compute", "compute-1"}, want: true},
+		{name: "other role", limit: "Compute", names: []string{"Controller", "controller-0"}, want: false},
+		{name: "excluded only", limit: "!controller-0", names: []string{"Controller", "controller-0"}, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			g.Expect(LimitMatches(tt.limit, tt.names...)).To(Equal(tt.want))
+		})
+	}
+}
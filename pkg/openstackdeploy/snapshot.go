@@ -0,0 +1,43 @@
+/*
+Copyright 2022 Red Hat
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package openstackdeploy
+
+import (
+	"fmt"
+
+	ospdirectorv1beta1 "github.com/openstack-k8s-operators/osp-director-operator/api/v1beta1"
+)
+
+// GetSnapshotSetName - get the name of the VirtualMachineSnapshot set taken before the deploy of the config version.
+// The config version gets shortened, like a git commit hash, to keep the name usable as label value.
+func GetSnapshotSetName(deployName string, configVersion string) string {
+	if len(configVersion) > SnapshotSetConfigVersionLength {
+		configVersion = configVersion[:SnapshotSetConfigVersionLength]
+	}
+
+	return fmt.Sprintf("%s-%s", deployName, configVersion)
+}
+
+// GetCurrentSnapshotSet - get the snapshot set of the status if it got taken before the deploy of the current
+// config version, empty otherwise, e.g. when the OpenStackDeploy gets re-run with a new config version.
+func GetCurrentSnapshotSet(instance *ospdirectorv1beta1.OpenStackDeploy) string {
+	if instance.Status.SnapshotSet != GetSnapshotSetName(instance.Name, instance.Spec.ConfigVersion) {
+		return ""
+	}
+
+	return instance.Status.SnapshotSet
+}
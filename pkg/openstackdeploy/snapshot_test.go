@@ -0,0 +1,54 @@
+package openstackdeploy
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega" //revive:disable:dot-imports
+	ospdirectorv1beta1 "github.com/openstack-k8s-operators/osp-director-operator/api/v1beta1"
+)
+
+func TestGetSnapshotSetName(t *testing.T) {
+	tests := []struct {
+		name          string
+		configVersion string
+		want          string
+	}{
+		{name: "short config version", configVersion: "v1", want: "overcloud-update-v1"},
+		{name: "config version hash", configVersion: "n5fch96h548h75hf4hbdhb8hfdh676h57bh96h5c5h59hf4h88h", want: "overcloud-update-n5fch96h54"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			g.Expect(GetSnapshotSetName("overcloud-update", tt.configVersion)).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestGetCurrentSnapshotSet(t *testing.T) {
+	tests := []struct {
+		name          string
+		configVersion string
+		snapshotSet   string
+		want          string
+	}{
+		{name: "no snapshot set", configVersion: "n5fch96h548h75hf4h", snapshotSet: "", want: ""},
+		{name: "snapshot set of the config version", configVersion: "n5fch96h548h75hf4h", snapshotSet: "overcloud-update-n5fch96h54", want: "overcloud-update-n5fch96h54"},
+		{name: "re-run with new config version", configVersion: "n8dh5f5h59ch65fh5c", snapshotSet: "overcloud-update-n5fch96h54", want: ""},
+		{name: "snapshot set named after the deploy only", configVersion: "n5fch96h548h75hf4h", snapshotSet: "overcloud-update", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			instance := &ospdirectorv1beta1.OpenStackDeploy{}
+			instance.Name = "overcloud-update"
+			instance.Spec.ConfigVersion = tt.configVersion
+			instance.Status.SnapshotSet = tt.snapshotSet
+
+			g.Expect(GetCurrentSnapshotSet(instance)).To(Equal(tt.want))
+		})
+	}
+}
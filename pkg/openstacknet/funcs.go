@@ -2,6 +2,8 @@ package openstacknet
 
 import (
 	"context"
+	"math"
+	"net"
 	"sort"
 
 	ospdirectorv1beta1 "github.com/openstack-k8s-operators/osp-director-operator/api/v1beta1"
@@ -68,6 +70,7 @@ func GetAllIPReservations(
 			reservationList,
 			ospdirectorv1beta1.IPReservation{
 				IP:       res.IP,
+				IPv6:     res.IPv6,
 				Hostname: hostname,
 				Deleted:  res.Deleted,
 			},
@@ -92,6 +95,7 @@ func GetAllIPReservations(
 					reservationList,
 					ospdirectorv1beta1.IPReservation{
 						IP:       res.IP,
+						IPv6:     res.IPv6,
 						Hostname: res.Hostname,
 						Deleted:  res.Deleted,
 					},
@@ -128,3 +132,62 @@ func GetAllIPReservations(
 
 	return reservationList
 }
+
+// GetAllIPv6Reservations - get the IPv6 reservations of a dual-stack network from the list of all reservations,
+// with the IPv6 address as IP to assign the next free IPv6 address
+func GetAllIPv6Reservations(
+	reservations []ospdirectorv1beta1.IPReservation,
+) []ospdirectorv1beta1.IPReservation {
+	reservationList := []ospdirectorv1beta1.IPReservation{}
+	for _, res := range reservations {
+		if res.IPv6 != "" {
+			res.IP = res.IPv6
+			reservationList = append(reservationList, res)
+		}
+	}
+
+	return reservationList
+}
+
+// GetExcludedIPCount - get the count of the addresses of the allocation ranges of the osnet, which
+// are excluded by the exclude ranges
+func GetExcludedIPCount(
+	osNet *ospdirectorv1beta1.OpenStackNet,
+) (int64, error) {
+	netDetails := []ospdirectorv1beta1.NetDetails{
+		{
+			Cidr:            osNet.Spec.Cidr,
+			AllocationStart: osNet.Spec.AllocationStart,
+			AllocationEnd:   osNet.Spec.AllocationEnd,
+			ExcludeRanges:   osNet.Spec.ExcludeRanges,
+		},
+	}
+	if osNet.Spec.IPv6 != nil {
+		netDetails = append(netDetails, *osNet.Spec.IPv6)
+	}
+
+	count := uint64(0)
+	for _, details := range netDetails {
+		if len(details.ExcludeRanges) == 0 {
+			continue
+		}
+
+		_, ipnet, err := net.ParseCIDR(details.Cidr)
+		if err != nil {
+			return 0, err
+		}
+
+		allocator, err := common.NewIPAllocator(
+			*ipnet,
+			net.ParseIP(details.AllocationStart),
+			net.ParseIP(details.AllocationEnd),
+			details.ExcludeRanges,
+		)
+		if err != nil {
+			return 0, err
+		}
+		count += min(allocator.ExcludedCount(), math.MaxInt64)
+	}
+
+	return int64(min(count, math.MaxInt64)), nil
+}
@@ -0,0 +1,185 @@
+/*
+Copyright 2022 Red Hat
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package openstacknetconfig
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+
+	"github.com/openstack-k8s-operators/osp-director-operator/api/shared"
+	ospdirectorv1beta1 "github.com/openstack-k8s-operators/osp-director-operator/api/v1beta1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const (
+	// DNSRecordsConfigMapSuffix - suffix of the name of the DNS records ConfigMap of an OpenStackNetConfig
+	DNSRecordsConfigMapSuffix = "-dns-records"
+
+	// DNSRecordsHostsKey - key of the CoreDNS hosts file in the DNS records ConfigMap
+	DNSRecordsHostsKey = "hosts"
+)
+
+// DNSEndpointGVK - external-dns DNSEndpoint
+var DNSEndpointGVK = schema.GroupVersionKind{
+	Group:   "externaldns.k8s.io",
+	Version: "v1alpha1",
+	Kind:    "DNSEndpoint",
+}
+
+// DNSRecord - address record of a host or VIP on a network
+type DNSRecord struct {
+	// Hostname, e.g. controller-0
+	Hostname string
+	// Domain of the network, e.g. internalapi.localdomain
+	Domain string
+	IP     net.IP
+}
+
+// FQDN - name of the host on the network, e.g. controller-0.internalapi.localdomain
+func (r DNSRecord) FQDN() string {
+	return r.Hostname + "." + r.Domain
+}
+
+// Type - A or AAAA
+func (r DNSRecord) Type() string {
+	if shared.IsIPv4(r.IP) {
+		return "A"
+	}
+
+	return "AAAA"
+}
+
+// ReverseName - name of the PTR record, e.g. 10.0.17.172.in-addr.arpa
+func (r DNSRecord) ReverseName() string {
+	if ip := r.IP.To4(); ip != nil {
+		return fmt.Sprintf("%d.%d.%d.%d.in-addr.arpa", ip[3], ip[2], ip[1], ip[0])
+	}
+
+	ip := r.IP.To16()
+	nibbles := make([]string, 0, 2*net.IPv6len)
+	for i := net.IPv6len - 1; i >= 0; i-- {
+		nibbles = append(nibbles, fmt.Sprintf("%x", ip[i]&0x0f), fmt.Sprintf("%x", ip[i]>>4))
+	}
+
+	return strings.Join(nibbles, ".") + ".ip6.arpa"
+}
+
+// GetDNSRecords - get the address records of all IPs of the hosts, sorted by domain, hostname and IP.
+// The domains map holds the domain per key of the IPAddresses of the hosts, addresses without a domain
+// are skipped.
+func GetDNSRecords(
+	hosts map[string]ospdirectorv1beta1.OpenStackHostStatus,
+	domains map[string]string,
+) []DNSRecord {
+	records := []DNSRecord{}
+
+	for hostname, host := range hosts {
+		for key, address := range host.IPAddresses {
+			domain, ok := domains[key]
+			if !ok {
+				continue
+			}
+
+			ip, _, err := net.ParseCIDR(address)
+			if err != nil {
+				ip = net.ParseIP(address)
+			}
+			if ip == nil {
+				continue
+			}
+
+			records = append(records, DNSRecord{
+				Hostname: hostname,
+				Domain:   domain,
+				IP:       ip,
+			})
+		}
+	}
+
+	sort.Slice(records, func(i, j int) bool {
+		if records[i].Domain != records[j].Domain {
+			return records[i].Domain < records[j].Domain
+		}
+		if records[i].Hostname != records[j].Hostname {
+			return records[i].Hostname < records[j].Hostname
+		}
+		return records[i].IP.String() < records[j].IP.String()
+	})
+
+	return records
+}
+
+// GetDNSRecordsConfigMapData - get the records in CoreDNS hosts format and as zone files. Per domain
+// there is a <domain>.zone with the A/AAAA records and a <domain>.reverse.zone with the fully qualified
+// PTR records, both meant to be included into the zones of the DNS server.
+func GetDNSRecordsConfigMapData(records []DNSRecord, ttl int64) map[string]string {
+	hosts := strings.Builder{}
+	zones := map[string]*strings.Builder{}
+	reverseZones := map[string]*strings.Builder{}
+
+	for _, record := range records {
+		fmt.Fprintf(&hosts, "%s %s\n", record.IP.String(), record.FQDN())
+
+		zone, ok := zones[record.Domain]
+		if !ok {
+			zone = &strings.Builder{}
+			fmt.Fprintf(zone, "$ORIGIN %s.\n$TTL %d\n", record.Domain, ttl)
+			zones[record.Domain] = zone
+
+			reverseZones[record.Domain] = &strings.Builder{}
+			fmt.Fprintf(reverseZones[record.Domain], "$TTL %d\n", ttl)
+		}
+		fmt.Fprintf(zone, "%s IN %s %s\n", record.Hostname, record.Type(), record.IP.String())
+		fmt.Fprintf(reverseZones[record.Domain], "%s. IN PTR %s.\n", record.ReverseName(), record.FQDN())
+	}
+
+	data := map[string]string{
+		DNSRecordsHostsKey: hosts.String(),
+	}
+	for domain, zone := range zones {
+		data[domain+".zone"] = zone.String()
+		data[domain+".reverse.zone"] = reverseZones[domain].String()
+	}
+
+	return data
+}
+
+// GetDNSEndpoints - get the records as endpoints of an external-dns DNSEndpoint
+func GetDNSEndpoints(records []DNSRecord, ttl int64) []interface{} {
+	endpoints := []interface{}{}
+
+	for _, record := range records {
+		endpoints = append(endpoints,
+			map[string]interface{}{
+				"dnsName":    record.FQDN(),
+				"recordType": record.Type(),
+				"recordTTL":  ttl,
+				"targets":    []interface{}{record.IP.String()},
+			},
+			map[string]interface{}{
+				"dnsName":    record.ReverseName(),
+				"recordType": "PTR",
+				"recordTTL":  ttl,
+				"targets":    []interface{}{record.FQDN()},
+			},
+		)
+	}
+
+	return endpoints
+}
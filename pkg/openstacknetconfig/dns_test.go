@@ -0,0 +1,98 @@
+package openstacknetconfig
+
+import (
+	"net"
+	"testing"
+
+	. "github.com/onsi/gomega" //revive:disable:dot-imports
+	ospdirectorv1beta1 "github.com/openstack-k8s-operators/osp-director-operator/api/v1beta1"
+)
+
+func TestDNSRecords(t *testing.T) {
+	hosts := map[string]ospdirectorv1beta1.OpenStackHostStatus{
+		"controller-0": {
+			IPAddresses: map[string]string{
+				"ctlplane":          "192.168.25.10/24",
+				"internal_api":      "172.17.0.10/24",
+				"internal_api_ipv6": "fd00:fd00:fd00:2000::10/64",
+				"storage":           "172.18.0.10/24",
+			},
+		},
+		"controlplane": {
+			IPAddresses: map[string]string{
+				"ctlplane": "192.168.25.5/24",
+			},
+		},
+	}
+	domains := map[string]string{
+		"ctlplane":          "ctlplane.localdomain",
+		"internal_api":      "internalapi.localdomain",
+		"internal_api_ipv6": "internalapi.localdomain",
+	}
+
+	records := GetDNSRecords(hosts, domains)
+
+	t.Run("records", func(t *testing.T) {
+		g := NewWithT(t)
+
+		g.Expect(records).To(Equal([]DNSRecord{
+			{Hostname: "controller-0", Domain: "ctlplane.localdomain", IP: net.ParseIP("192.168.25.10")},
+			{Hostname: "controlplane", Domain: "ctlplane.localdomain", IP: net.ParseIP("192.168.25.5")},
+			{Hostname: "controller-0", Domain: "internalapi.localdomain", IP: net.ParseIP("172.17.0.10")},
+			{Hostname: "controller-0", Domain: "internalapi.localdomain", IP: net.ParseIP("fd00:fd00:fd00:2000::10")},
+		}))
+		g.Expect(records[3].Type()).To(Equal("AAAA"))
+		g.Expect(records[2].ReverseName()).To(Equal("10.0.17.172.in-addr.arpa"))
+		g.Expect(records[3].ReverseName()).To(Equal("0.1.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.2.0.0.d.f.0.0.d.f.0.0.d.f.ip6.arpa"))
+	})
+
+	t.Run("config map data", func(t *testing.T) {
+		g := NewWithT(t)
+
+		g.Expect(GetDNSRecordsConfigMapData(records, 3600)).To(Equal(map[string]string{
+			"hosts": "192.168.25.10 controller-0.ctlplane.localdomain\n" +
+				"192.168.25.5 controlplane.ctlplane.localdomain\n" +
+				"172.17.0.10 controller-0.internalapi.localdomain\n" +
+				"fd00:fd00:fd00:2000::10 controller-0.internalapi.localdomain\n",
+			"ctlplane.localdomain.zone": "$ORIGIN ctlplane.localdomain.\n$TTL 3600\n" +
+				"controller-0 IN A 192.168.25.10\n" +
+				"controlplane IN A 192.168.25.5\n",
+			"ctlplane.localdomain.reverse.zone": "$TTL 3600\n" +
+				"10.25.168.192.in-addr.arpa. IN PTR controller-0.ctlplane.localdomain.\n" +
+				"5.25.168.192.in-addr.arpa. IN PTR controlplane.ctlplane.localdomain.\n",
+			"internalapi.localdomain.zone": "$ORIGIN internalapi.localdomain.\n$TTL 3600\n" +
+				"controller-0 IN A 172.17.0.10\n" +
+				"controller-0 IN AAAA fd00:fd00:fd00:2000::10\n",
+			"internalapi.localdomain.reverse.zone": "$TTL 3600\n" +
+				"10.0.17.172.in-addr.arpa. IN PTR controller-0.internalapi.localdomain.\n" +
+				"0.1.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.2.0.0.d.f.0.0.d.f.0.0.d.f.ip6.arpa. IN PTR controller-0.internalapi.localdomain.\n",
+		}))
+	})
+
+	t.Run("dns endpoints", func(t *testing.T) {
+		g := NewWithT(t)
+
+		endpoints := GetDNSEndpoints(records[:1], 300)
+		g.Expect(endpoints).To(Equal([]interface{}{
+			map[string]interface{}{
+				"dnsName":    "controller-0.ctlplane.localdomain",
+				"recordType": "A",
+				"recordTTL":  int64(300),
+				"targets":    []interface{}{"192.168.25.10"},
+			},
+			map[string]interface{}{
+				"dnsName":    "10.25.168.192.in-addr.arpa",
+				"recordType": "PTR",
+				"recordTTL":  int64(300),
+				"targets":    []interface{}{"controller-0.ctlplane.localdomain"},
+			},
+		}))
+	})
+
+	t.Run("removed hosts", func(t *testing.T) {
+		g := NewWithT(t)
+
+		g.Expect(GetDNSRecords(map[string]ospdirectorv1beta1.OpenStackHostStatus{}, domains)).To(BeEmpty())
+		g.Expect(GetDNSRecordsConfigMapData(nil, 3600)).To(Equal(map[string]string{"hosts": ""}))
+	})
+}
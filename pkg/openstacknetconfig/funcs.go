@@ -55,6 +55,12 @@ func WaitOnIPsCreated(
 	for _, osNet := range networks {
 		if ip, ok := osnetcfgHostStatus.IPAddresses[osNet]; ok && ip != "" {
 			hostStatus.IPAddresses[osNet] = ip
+
+			// IPv6 address of a dual-stack network
+			ipv6Key := ospdirectorv1beta1.IPv6AddressKey(osNet)
+			if ipv6, ok := osnetcfgHostStatus.IPAddresses[ipv6Key]; ok && ipv6 != "" {
+				hostStatus.IPAddresses[ipv6Key] = ipv6
+			}
 			continue
 		}
 		common.LogForObject(
@@ -0,0 +1,463 @@
+/*
+Copyright 2022 Red Hat
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package openstacknetconfig
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/openstack-k8s-operators/osp-director-operator/api/shared"
+	ospdirectorv1beta1 "github.com/openstack-k8s-operators/osp-director-operator/api/v1beta1"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// TripleoDefaultStackName - default name of the overcloud stack
+	TripleoDefaultStackName = "overcloud"
+
+	// tripleoSubnetSuffix - suffix of the subnet names in the network_data rendered by the operator
+	tripleoSubnetSuffix = "_subnet"
+)
+
+// tripleoSubnet - subnet of a network in the TripleO network_data.yaml
+type tripleoSubnet struct {
+	IPSubnet   string `json:"ip_subnet,omitempty"`
+	IPv6Subnet string `json:"ipv6_subnet,omitempty"`
+}
+
+// tripleoNetwork - network of the TripleO network_data.yaml, either with the v1 ip_subnet/ipv6_subnet
+// of the network or the v2 subnets
+type tripleoNetwork struct {
+	tripleoSubnet
+	Name      string                   `json:"name"`
+	NameLower string                   `json:"name_lower,omitempty"`
+	Subnets   map[string]tripleoSubnet `json:"subnets,omitempty"`
+}
+
+// tripleoPort - port of a host in the NodePortMap
+type tripleoPort struct {
+	IPAddress   string `json:"ip_address"`
+	IPv6Address string `json:"ipv6_address,omitempty"`
+}
+
+// tripleoDeployedServerPort - port of a host in the DeployedServerPortMap
+type tripleoDeployedServerPort struct {
+	FixedIPs []struct {
+		IPAddress string `json:"ip_address"`
+	} `json:"fixed_ips"`
+}
+
+// tripleoEnvironment - the parameter_defaults of a TripleO environment file
+type tripleoEnvironment struct {
+	ParameterDefaults map[string]json.RawMessage `json:"parameter_defaults"`
+}
+
+// TripleoImport - static IP and MAC reservations of the hosts of an existing TripleO deployment
+type TripleoImport struct {
+	// Reservations - static reservations per operator hostname, e.g. controller-0
+	Reservations map[string]ospdirectorv1beta1.OpenStackNetStaticNodeReservations
+	// Hostnames - TripleO hostname per operator hostname
+	Hostnames map[string]string
+	// Conflicts - addresses which could not be imported or conflict with each other
+	Conflicts []string
+
+	networks []tripleoNetwork
+	// ips - host per reserved IP to find duplicates
+	ips map[string]string
+}
+
+// ImportTripleoReservations - get the static reservations of the hosts from the TripleO network_data.yaml
+// and environment files of an existing deployment, like ips-from-pool-all.yaml or
+// overcloud-baremetal-deployed.yaml. Later environments override the parameters of earlier ones.
+//
+// The hosts of a role get the operator hostname <role name lower>-<index>. The IPs are taken from the
+// <Role>IPs lists, the NodePortMap and the DeployedServerPortMap, the MACs from the
+// OVNStaticBridgeMacMappings. The reservations use the subnet names of the operator, which is the
+// TripleO subnet name without the _subnet suffix.
+func ImportTripleoReservations(
+	stackName string,
+	networkData []byte,
+	environments ...[]byte,
+) (*TripleoImport, error) {
+	tripleoImport := &TripleoImport{
+		Reservations: map[string]ospdirectorv1beta1.OpenStackNetStaticNodeReservations{},
+		Hostnames:    map[string]string{},
+		Conflicts:    []string{},
+		ips:          map[string]string{},
+	}
+
+	if err := yaml.Unmarshal(networkData, &tripleoImport.networks); err != nil {
+		return nil, fmt.Errorf("failed to parse network_data: %w", err)
+	}
+
+	params := map[string]json.RawMessage{}
+	for _, environment := range environments {
+		env := tripleoEnvironment{}
+		if err := yaml.Unmarshal(environment, &env); err != nil {
+			return nil, fmt.Errorf("failed to parse environment: %w", err)
+		}
+		for key, value := range env.ParameterDefaults {
+			params[key] = value
+		}
+	}
+
+	hostnameMap := map[string]string{}
+	nodePortMap := map[string]map[string]tripleoPort{}
+	deployedServerPortMap := map[string]tripleoDeployedServerPort{}
+	macMappings := map[string]map[string]string{}
+	for key, value := range map[string]interface{}{
+		"HostnameMap":                &hostnameMap,
+		"NodePortMap":                &nodePortMap,
+		"DeployedServerPortMap":      &deployedServerPortMap,
+		"OVNStaticBridgeMacMappings": &macMappings,
+	} {
+		if raw, ok := params[key]; ok {
+			if err := json.Unmarshal(raw, value); err != nil {
+				return nil, fmt.Errorf("failed to parse %s: %w", key, err)
+			}
+		}
+	}
+
+	for _, role := range getTripleoRoles(params) {
+		//
+		// <Role>IPs of the ips-from-pool environment, network -> IP per host index
+		//
+		roleIPs := map[string][]string{}
+		if raw, ok := params[role+"IPs"]; ok {
+			if err := json.Unmarshal(raw, &roleIPs); err != nil {
+				return nil, fmt.Errorf("failed to parse %sIPs: %w", role, err)
+			}
+		}
+
+		count := 0
+		if raw, ok := params[role+"Count"]; ok {
+			if err := json.Unmarshal(raw, &count); err != nil {
+				return nil, fmt.Errorf("failed to parse %sCount: %w", role, err)
+			}
+		} else {
+			for _, ips := range roleIPs {
+				count = max(count, len(ips))
+			}
+		}
+
+		hostnameFormat := "%stackname%-" + strings.ToLower(role) + "-%index%"
+		if raw, ok := params[role+"HostnameFormat"]; ok {
+			if err := json.Unmarshal(raw, &hostnameFormat); err != nil {
+				return nil, fmt.Errorf("failed to parse %sHostnameFormat: %w", role, err)
+			}
+		}
+
+		for index := 0; index < count; index++ {
+			hostname := fmt.Sprintf("%s-%d", strings.ToLower(role), index)
+
+			tripleoHostname := strings.NewReplacer(
+				"%stackname%", stackName,
+				"%index%", strconv.Itoa(index),
+			).Replace(hostnameFormat)
+			if mapped, ok := hostnameMap[tripleoHostname]; ok {
+				tripleoHostname = mapped
+			}
+
+			ips := map[string][]string{}
+			for netNameLower, netIPs := range roleIPs {
+				if index < len(netIPs) {
+					ips[netNameLower] = append(ips[netNameLower], netIPs[index])
+				}
+			}
+			for netNameLower, port := range nodePortMap[tripleoHostname] {
+				ips[netNameLower] = append(ips[netNameLower], port.IPAddress, port.IPv6Address)
+			}
+			for _, fixedIP := range deployedServerPortMap[tripleoHostname+"-"+ospdirectorv1beta1.ControlPlaneNameLower].FixedIPs {
+				ips[ospdirectorv1beta1.ControlPlaneNameLower] = append(ips[ospdirectorv1beta1.ControlPlaneNameLower], fixedIP.IPAddress)
+			}
+
+			if len(ips) == 0 && len(macMappings[tripleoHostname]) == 0 {
+				continue
+			}
+
+			reservation := ospdirectorv1beta1.OpenStackNetStaticNodeReservations{
+				IPReservations:  map[string]string{},
+				MACReservations: map[string]string{},
+			}
+			for physnet, mac := range macMappings[tripleoHostname] {
+				reservation.MACReservations[physnet] = mac
+			}
+
+			for _, netNameLower := range sortedKeys(ips) {
+				for _, ip := range ips[netNameLower] {
+					if ip != "" {
+						tripleoImport.addIPReservation(reservation, hostname, netNameLower, ip)
+					}
+				}
+			}
+
+			// the IPv6 address of a dual-stack subnet can only be reserved together with its IPv4 address
+			for _, key := range sortedKeys(reservation.IPReservations) {
+				subnetName := strings.TrimSuffix(key, ospdirectorv1beta1.IPv6AddressKey(""))
+				if _, ok := reservation.IPReservations[subnetName]; key != subnetName && !ok {
+					tripleoImport.Conflicts = append(tripleoImport.Conflicts, fmt.Sprintf("IP address %s of node %s has no IPv4 address on subnet %s",
+						reservation.IPReservations[key],
+						hostname,
+						subnetName,
+					))
+					delete(tripleoImport.ips, reservation.IPReservations[key])
+					delete(reservation.IPReservations, key)
+				}
+			}
+
+			tripleoImport.Reservations[hostname] = reservation
+			tripleoImport.Hostnames[hostname] = tripleoHostname
+		}
+	}
+
+	return tripleoImport, nil
+}
+
+// addIPReservation - add the IP of the host on the TripleO network to the reservations of its subnet
+func (i *TripleoImport) addIPReservation(
+	reservation ospdirectorv1beta1.OpenStackNetStaticNodeReservations,
+	hostname string,
+	netNameLower string,
+	ipAddress string,
+) {
+	ip := net.ParseIP(ipAddress)
+	if ip == nil {
+		i.Conflicts = append(i.Conflicts, fmt.Sprintf("IP address %s of node %s on network %s has an invalid format",
+			ipAddress,
+			hostname,
+			netNameLower,
+		))
+		return
+	}
+
+	subnetName, dualStack, err := i.getSubnet(netNameLower, ip)
+	if err != nil {
+		i.Conflicts = append(i.Conflicts, fmt.Sprintf("IP address %s of node %s: %s", ipAddress, hostname, err.Error()))
+		return
+	}
+
+	key := subnetName
+	if dualStack && shared.IsIPv6(ip) {
+		key = ospdirectorv1beta1.IPv6AddressKey(subnetName)
+	}
+
+	if current, ok := reservation.IPReservations[key]; ok {
+		if current != ip.String() {
+			i.Conflicts = append(i.Conflicts, fmt.Sprintf("node %s has the IP addresses %s and %s on subnet %s",
+				hostname,
+				current,
+				ip.String(),
+				key,
+			))
+		}
+		return
+	}
+
+	if node, ok := i.ips[ip.String()]; ok {
+		i.Conflicts = append(i.Conflicts, fmt.Sprintf("IP address %s of node %s is not uniq. Already used by %s",
+			ip.String(),
+			hostname,
+			node,
+		))
+		return
+	}
+
+	reservation.IPReservations[key] = ip.String()
+	i.ips[ip.String()] = hostname
+}
+
+// getSubnet - get the operator subnet name of the TripleO network which contains the IP, and if it is dual-stack
+func (i *TripleoImport) getSubnet(netNameLower string, ip net.IP) (string, bool, error) {
+	for _, network := range i.networks {
+		nameLower := network.NameLower
+		if nameLower == "" {
+			nameLower = strings.ToLower(network.Name)
+		}
+		if nameLower != netNameLower {
+			continue
+		}
+
+		subnets := map[string]tripleoSubnet{}
+		for name, subnet := range network.Subnets {
+			subnets[strings.TrimSuffix(name, tripleoSubnetSuffix)] = subnet
+		}
+		if network.IPSubnet != "" || network.IPv6Subnet != "" {
+			subnets[nameLower] = network.tripleoSubnet
+		}
+
+		for _, name := range sortedKeys(subnets) {
+			subnet := subnets[name]
+			for _, cidr := range []string{subnet.IPSubnet, subnet.IPv6Subnet} {
+				if cidr == "" {
+					continue
+				}
+				_, ipnet, err := net.ParseCIDR(cidr)
+				if err != nil {
+					return "", false, fmt.Errorf("subnet %s of network %s: %w", name, nameLower, err)
+				}
+				if ipnet.Contains(ip) {
+					return name, subnet.IPSubnet != "" && subnet.IPv6Subnet != "", nil
+				}
+			}
+		}
+
+		return "", false, fmt.Errorf("not within a subnet of network %s", nameLower)
+	}
+
+	//
+	// the ctlplane network is not part of the network_data
+	//
+	if netNameLower == ospdirectorv1beta1.ControlPlaneNameLower {
+		return netNameLower, false, nil
+	}
+
+	return "", false, fmt.Errorf("network %s is not in the network_data", netNameLower)
+}
+
+// CheckConflicts - get the conflicts of the imported reservations with the static reservations and the
+// assigned IP and MAC addresses of the hosts of the OpenStackNetConfig
+func (i *TripleoImport) CheckConflicts(netConfig *ospdirectorv1beta1.OpenStackNetConfig) []string {
+	conflicts := []string{}
+
+	subnets := map[string]bool{}
+	for _, network := range netConfig.Spec.Networks {
+		for _, subnet := range network.Subnets {
+			subnets[subnet.Name] = true
+			subnets[ospdirectorv1beta1.IPv6AddressKey(subnet.Name)] = true
+		}
+	}
+
+	// IP -> node of the static reservations and the assigned IPs
+	ips := map[string]string{}
+	for node, res := range netConfig.Spec.Reservations {
+		for _, ip := range res.IPReservations {
+			ips[ip] = node
+		}
+	}
+	for node, host := range netConfig.Status.Hosts {
+		for _, ip := range host.IPAddresses {
+			if addr, _, err := net.ParseCIDR(ip); err == nil {
+				ips[addr.String()] = node
+			}
+		}
+	}
+
+	for _, hostname := range sortedKeys(i.Reservations) {
+		reservation := i.Reservations[hostname]
+		current := netConfig.Spec.Reservations[hostname]
+		status := netConfig.Status.Hosts[hostname]
+
+		for _, key := range sortedKeys(reservation.IPReservations) {
+			ip := reservation.IPReservations[key]
+
+			if !subnets[key] {
+				conflicts = append(conflicts, fmt.Sprintf("IP address %s of node %s is for subnet %s, which is not in %s",
+					ip,
+					hostname,
+					key,
+					netConfig.Name,
+				))
+			}
+
+			if currentIP, ok := current.IPReservations[key]; ok && currentIP != ip {
+				conflicts = append(conflicts, fmt.Sprintf("IP address %s of node %s on subnet %s conflicts with the reservation %s",
+					ip,
+					hostname,
+					key,
+					currentIP,
+				))
+			}
+
+			if assigned, ok := status.IPAddresses[key]; ok {
+				if addr, _, err := net.ParseCIDR(assigned); err == nil && addr.String() != ip {
+					conflicts = append(conflicts, fmt.Sprintf("IP address %s of node %s on subnet %s conflicts with the assigned IP address %s",
+						ip,
+						hostname,
+						key,
+						addr.String(),
+					))
+				}
+			}
+
+			if node, ok := ips[ip]; ok && node != hostname {
+				conflicts = append(conflicts, fmt.Sprintf("IP address %s of node %s is not uniq. Already used by %s",
+					ip,
+					hostname,
+					node,
+				))
+			}
+		}
+
+		for _, physnet := range sortedKeys(reservation.MACReservations) {
+			mac := reservation.MACReservations[physnet]
+
+			if currentMAC, ok := current.MACReservations[physnet]; ok && currentMAC != mac {
+				conflicts = append(conflicts, fmt.Sprintf("MAC address %s of node %s on physnet %s conflicts with the reservation %s",
+					mac,
+					hostname,
+					physnet,
+					currentMAC,
+				))
+			}
+
+			if assigned, ok := status.OVNBridgeMacAdresses[physnet]; ok && assigned != mac {
+				conflicts = append(conflicts, fmt.Sprintf("MAC address %s of node %s on physnet %s conflicts with the assigned MAC address %s",
+					mac,
+					hostname,
+					physnet,
+					assigned,
+				))
+			}
+		}
+	}
+
+	return conflicts
+}
+
+// getTripleoRoles - get the sorted role names of the <Role>IPs and <Role>HostnameFormat parameters
+func getTripleoRoles(params map[string]json.RawMessage) []string {
+	roles := map[string]bool{}
+	for key, value := range params {
+		switch {
+		case strings.HasSuffix(key, "HostnameFormat"):
+			roles[strings.TrimSuffix(key, "HostnameFormat")] = true
+		case strings.HasSuffix(key, "IPs"):
+			// only the network -> IP list maps, e.g. not DnsServerIPs
+			if err := json.Unmarshal(value, &map[string][]string{}); err == nil {
+				roles[strings.TrimSuffix(key, "IPs")] = true
+			}
+		}
+	}
+	delete(roles, "")
+
+	return sortedKeys(roles)
+}
+
+// sortedKeys - get the sorted keys of a map
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
@@ -0,0 +1,221 @@
+package openstacknetconfig
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega" //revive:disable:dot-imports
+	ospdirectorv1beta1 "github.com/openstack-k8s-operators/osp-director-operator/api/v1beta1"
+)
+
+const (
+	tripleoNetworkData = `
+- name: InternalApi
+  name_lower: internal_api
+  vip: true
+  subnets:
+    internal_api_subnet:
+      ip_subnet: 172.17.0.0/24
+      ipv6_subnet: fd00:fd00:fd00:2000::/64
+      vlan: 20
+    internal_api_leaf1:
+      ip_subnet: 172.17.1.0/24
+      vlan: 21
+- name: Tenant
+  ip_subnet: 172.19.0.0/24
+  vlan: 50
+`
+
+	tripleoIPsFromPool = `
+resource_registry:
+  OS::TripleO::Controller::Ports::InternalApiPort: ../network/ports/internal_api_from_pool.yaml
+parameter_defaults:
+  DnsServerIPs: ["192.168.25.1"]
+  ControllerIPs:
+    ctlplane:
+    - 192.168.25.10
+    - 192.168.25.11
+    internal_api:
+    - 172.17.0.10
+    - 172.17.0.11
+  ComputeIPs:
+    internal_api:
+    - 172.17.1.20
+    tenant:
+    - 172.19.0.20
+`
+
+	tripleoBaremetalDeployed = `
+parameter_defaults:
+  ControllerCount: 2
+  ControllerHostnameFormat: '%stackname%-controller-%index%'
+  ComputeCount: 1
+  ComputeHostnameFormat: '%stackname%-novacompute-%index%'
+  HostnameMap:
+    overcloud-controller-0: controller-0
+    overcloud-controller-1: controller-1
+    overcloud-novacompute-0: compute-0
+  DeployedServerPortMap:
+    compute-0-ctlplane:
+      fixed_ips:
+      - ip_address: 192.168.25.20
+  NodePortMap:
+    controller-0:
+      internal_api:
+        ip_address: 172.17.0.10
+        ipv6_address: fd00:fd00:fd00:2000::10
+  OVNStaticBridgeMacMappings:
+    controller-0:
+      datacentre: fa:16:3a:aa:aa:00
+    compute-0:
+      datacentre: fa:16:3a:aa:aa:20
+`
+)
+
+func TestImportTripleoReservations(t *testing.T) {
+	t.Run("ips from pool", func(t *testing.T) {
+		g := NewWithT(t)
+
+		tripleoImport, err := ImportTripleoReservations(TripleoDefaultStackName, []byte(tripleoNetworkData), []byte(tripleoIPsFromPool))
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(tripleoImport.Conflicts).To(BeEmpty())
+		g.Expect(tripleoImport.Hostnames).To(Equal(map[string]string{
+			"controller-0": "overcloud-controller-0",
+			"controller-1": "overcloud-controller-1",
+			"compute-0":    "overcloud-compute-0",
+		}))
+		g.Expect(tripleoImport.Reservations["controller-1"].IPReservations).To(Equal(map[string]string{
+			"ctlplane":     "192.168.25.11",
+			"internal_api": "172.17.0.11",
+		}))
+		g.Expect(tripleoImport.Reservations["compute-0"].IPReservations).To(Equal(map[string]string{
+			"internal_api_leaf1": "172.17.1.20",
+			"tenant":             "172.19.0.20",
+		}))
+	})
+
+	t.Run("baremetal deployed", func(t *testing.T) {
+		g := NewWithT(t)
+
+		tripleoImport, err := ImportTripleoReservations(
+			TripleoDefaultStackName,
+			[]byte(tripleoNetworkData),
+			[]byte(tripleoIPsFromPool),
+			[]byte(tripleoBaremetalDeployed),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(tripleoImport.Conflicts).To(BeEmpty())
+		g.Expect(tripleoImport.Hostnames).To(HaveKeyWithValue("compute-0", "compute-0"))
+		g.Expect(tripleoImport.Reservations["controller-0"]).To(Equal(ospdirectorv1beta1.OpenStackNetStaticNodeReservations{
+			IPReservations: map[string]string{
+				"ctlplane":          "192.168.25.10",
+				"internal_api":      "172.17.0.10",
+				"internal_api_ipv6": "fd00:fd00:fd00:2000::10",
+			},
+			MACReservations: map[string]string{
+				"datacentre": "fa:16:3a:aa:aa:00",
+			},
+		}))
+		g.Expect(tripleoImport.Reservations["compute-0"]).To(Equal(ospdirectorv1beta1.OpenStackNetStaticNodeReservations{
+			IPReservations: map[string]string{
+				"ctlplane":           "192.168.25.20",
+				"internal_api_leaf1": "172.17.1.20",
+				"tenant":             "172.19.0.20",
+			},
+			MACReservations: map[string]string{
+				"datacentre": "fa:16:3a:aa:aa:20",
+			},
+		}))
+	})
+
+	t.Run("conflicts within the deployment", func(t *testing.T) {
+		g := NewWithT(t)
+
+		tripleoImport, err := ImportTripleoReservations(TripleoDefaultStackName, []byte(tripleoNetworkData), []byte(`
+parameter_defaults:
+  ControllerIPs:
+    internal_api:
+    - 172.17.0.10
+    - 172.17.0.10
+    - 172.18.0.10
+    storage:
+    - 172.20.0.10
+`))
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(tripleoImport.Conflicts).To(ConsistOf(
+			"IP address 172.17.0.10 of node controller-1 is not uniq. Already used by controller-0",
+			"IP address 172.18.0.10 of node controller-2: not within a subnet of network internal_api",
+			"IP address 172.20.0.10 of node controller-0: network storage is not in the network_data",
+		))
+	})
+
+	t.Run("IPv6 address without IPv4 address", func(t *testing.T) {
+		g := NewWithT(t)
+
+		tripleoImport, err := ImportTripleoReservations(TripleoDefaultStackName, []byte(tripleoNetworkData), []byte(`
+parameter_defaults:
+  ControllerCount: 1
+  ControllerHostnameFormat: '%stackname%-controller-%index%'
+  NodePortMap:
+    overcloud-controller-0:
+      internal_api:
+        ipv6_address: fd00:fd00:fd00:2000::10
+`))
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(tripleoImport.Conflicts).To(ConsistOf(
+			"IP address fd00:fd00:fd00:2000::10 of node controller-0 has no IPv4 address on subnet internal_api",
+		))
+		g.Expect(tripleoImport.Reservations["controller-0"].IPReservations).To(BeEmpty())
+	})
+
+	t.Run("invalid environment", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := ImportTripleoReservations(TripleoDefaultStackName, []byte(tripleoNetworkData), []byte("parameter_defaults:\n  ControllerCount: two\n  ControllerHostnameFormat: x\n"))
+		g.Expect(err).To(HaveOccurred())
+	})
+}
+
+func TestTripleoImportCheckConflicts(t *testing.T) {
+	g := NewWithT(t)
+
+	tripleoImport, err := ImportTripleoReservations(
+		TripleoDefaultStackName,
+		[]byte(tripleoNetworkData),
+		[]byte(tripleoIPsFromPool),
+		[]byte(tripleoBaremetalDeployed),
+	)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	netConfig := &ospdirectorv1beta1.OpenStackNetConfig{
+		Spec: ospdirectorv1beta1.OpenStackNetConfigSpec{
+			Networks: []ospdirectorv1beta1.Network{
+				{Name: "Control", NameLower: "ctlplane", Subnets: []ospdirectorv1beta1.Subnet{{Name: "ctlplane"}}},
+				{Name: "InternalApi", NameLower: "internal_api", Subnets: []ospdirectorv1beta1.Subnet{{Name: "internal_api"}, {Name: "internal_api_leaf1"}}},
+			},
+			Reservations: map[string]ospdirectorv1beta1.OpenStackNetStaticNodeReservations{
+				"controller-1": {
+					IPReservations: map[string]string{"internal_api": "172.17.0.99"},
+				},
+			},
+		},
+		Status: ospdirectorv1beta1.OpenStackNetConfigStatus{
+			Hosts: map[string]ospdirectorv1beta1.OpenStackHostStatus{
+				"controller-0": {
+					IPAddresses:          map[string]string{"ctlplane": "192.168.25.10/24"},
+					OVNBridgeMacAdresses: map[string]string{"datacentre": "fa:16:3a:bb:bb:00"},
+				},
+				"openstackclient-0": {
+					IPAddresses: map[string]string{"ctlplane": "192.168.25.20/24"},
+				},
+			},
+		},
+	}
+	netConfig.Name = "openstacknetconfig"
+
+	g.Expect(tripleoImport.CheckConflicts(netConfig)).To(ConsistOf(
+		"IP address 192.168.25.20 of node compute-0 is not uniq. Already used by openstackclient-0",
+		"IP address 172.19.0.20 of node compute-0 is for subnet tenant, which is not in openstacknetconfig",
+		"MAC address fa:16:3a:aa:aa:00 of node controller-0 on physnet datacentre conflicts with the assigned MAC address fa:16:3a:bb:bb:00",
+		"IP address 172.17.0.11 of node controller-1 on subnet internal_api conflicts with the reservation 172.17.0.99",
+	))
+}
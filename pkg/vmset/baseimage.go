@@ -0,0 +1,205 @@
+/*
+Copyright 2022 Red Hat
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vmset
+
+import (
+	"time"
+
+	ospdirectorv1beta2 "github.com/openstack-k8s-operators/osp-director-operator/api/v1beta2"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	cdiv1 "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
+)
+
+// checksumScript - download the image and compare its checksum, CHECKSUM has the format <algorithm>:<hex digest>.
+// It verifies the content at the URL at the time of the check, CDI downloads the image again for the import.
+const checksumScript = `set -eo pipefail
+ALGORITHM=${CHECKSUM%%:*}
+EXPECTED=${CHECKSUM#*:}
+CURL_ARGS=(-fsSL --retry 5 --connect-timeout 120)
+if [ -n "${ACCESS_KEY_ID}" ]; then
+    CURL_ARGS+=(-u "${ACCESS_KEY_ID}:${SECRET_KEY}")
+fi
+if [ -d /certs ]; then
+    cat /certs/* > /tmp/ca-bundle.pem
+    CURL_ARGS+=(--cacert /tmp/ca-bundle.pem)
+fi
+ACTUAL=$(curl "${CURL_ARGS[@]}" "${IMAGE_URL}" | ${ALGORITHM}sum | cut -d ' ' -f 1)
+if [ "${ACTUAL,,}" != "${EXPECTED,,}" ]; then
+    echo "Checksum mismatch for ${IMAGE_URL}: expected ${EXPECTED}, got ${ACTUAL}"
+    exit 1
+fi
+echo "Checksum of ${IMAGE_URL} verified"
+`
+
+// BaseImageDataVolumeSpec - get the spec of the base image DataVolume imported or cloned from the base image source
+func BaseImageDataVolumeSpec(
+	source *ospdirectorv1beta2.BaseImageSource,
+	namespace string,
+	rootDisk ospdirectorv1beta2.OpenStackVMSetDisk,
+) cdiv1.DataVolumeSpec {
+	volumeMode := corev1.PersistentVolumeMode(rootDisk.StorageVolumeMode)
+	spec := cdiv1.DataVolumeSpec{
+		PVC: &corev1.PersistentVolumeClaimSpec{
+			AccessModes: []corev1.PersistentVolumeAccessMode{
+				corev1.PersistentVolumeAccessMode(rootDisk.StorageAccessMode),
+			},
+			StorageClassName: &rootDisk.StorageClass,
+			VolumeMode:       &volumeMode,
+			Resources: corev1.VolumeResourceRequirements{
+				Requests: corev1.ResourceList{
+					corev1.ResourceStorage: DiskSize(rootDisk.DiskSize),
+				},
+			},
+		},
+		Source: &cdiv1.DataVolumeSource{},
+	}
+
+	switch {
+	case source.HTTP != nil:
+		spec.Source.HTTP = &cdiv1.DataVolumeSourceHTTP{
+			URL:           source.HTTP.URL,
+			SecretRef:     source.HTTP.SecretRef,
+			CertConfigMap: source.HTTP.CertConfigMap,
+		}
+	case source.Registry != nil:
+		url := source.Registry.URL
+		spec.Source.Registry = &cdiv1.DataVolumeSourceRegistry{
+			URL: &url,
+		}
+		if source.Registry.SecretRef != "" {
+			spec.Source.Registry.SecretRef = &source.Registry.SecretRef
+		}
+		if source.Registry.CertConfigMap != "" {
+			spec.Source.Registry.CertConfigMap = &source.Registry.CertConfigMap
+		}
+	case source.PVC != nil:
+		spec.Source.PVC = &cdiv1.DataVolumeSourcePVC{
+			Name:      source.PVC.Name,
+			Namespace: source.PVC.Namespace,
+		}
+		if spec.Source.PVC.Namespace == "" {
+			spec.Source.PVC.Namespace = namespace
+		}
+	case source.Snapshot != nil:
+		spec.Source.Snapshot = &cdiv1.DataVolumeSourceSnapshot{
+			Name:      source.Snapshot.Name,
+			Namespace: source.Snapshot.Namespace,
+		}
+		if spec.Source.Snapshot.Namespace == "" {
+			spec.Source.Snapshot.Namespace = namespace
+		}
+	}
+
+	return spec
+}
+
+// BaseImageChecksumJob - get the job which verifies the checksum of the image at the http URL of the base image source
+func BaseImageChecksumJob(
+	name string,
+	namespace string,
+	labels map[string]string,
+	source *ospdirectorv1beta2.BaseImageSourceHTTP,
+) *batchv1.Job {
+	backoffLimit := int32(2)
+	optional := true
+
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			Labels:    labels,
+		},
+	}
+
+	env := []corev1.EnvVar{
+		{
+			Name:  "IMAGE_URL",
+			Value: source.URL,
+		},
+		{
+			Name:  "CHECKSUM",
+			Value: source.Checksum,
+		},
+	}
+	if source.SecretRef != "" {
+		for _, secretEnv := range [][2]string{{"ACCESS_KEY_ID", "accessKeyId"}, {"SECRET_KEY", "secretKey"}} {
+			env = append(env, corev1.EnvVar{
+				Name: secretEnv[0],
+				ValueFrom: &corev1.EnvVarSource{
+					SecretKeyRef: &corev1.SecretKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{
+							Name: source.SecretRef,
+						},
+						Key:      secretEnv[1],
+						Optional: &optional,
+					},
+				},
+			})
+		}
+	}
+
+	container := corev1.Container{
+		Name:    "verify-checksum",
+		Image:   source.ChecksumImageURL,
+		Command: []string{"/bin/bash", "-c", checksumScript},
+		Env:     env,
+	}
+
+	job.Spec.BackoffLimit = &backoffLimit
+	job.Spec.Template.Spec = corev1.PodSpec{
+		RestartPolicy: corev1.RestartPolicyNever,
+	}
+
+	if source.CertConfigMap != "" {
+		job.Spec.Template.Spec.Volumes = []corev1.Volume{
+			{
+				Name: "certs",
+				VolumeSource: corev1.VolumeSource{
+					ConfigMap: &corev1.ConfigMapVolumeSource{
+						LocalObjectReference: corev1.LocalObjectReference{
+							Name: source.CertConfigMap,
+						},
+					},
+				},
+			},
+		}
+		container.VolumeMounts = []corev1.VolumeMount{
+			{
+				Name:      "certs",
+				MountPath: "/certs",
+				ReadOnly:  true,
+			},
+		}
+	}
+
+	job.Spec.Template.Spec.Containers = []corev1.Container{container}
+
+	return job
+}
+
+// GetBaseImageRetryDelay - get the time to wait before a failed base image DataVolume gets recreated.
+// The delay doubles with each failure, starting at 30s, up to 10m.
+func GetBaseImageRetryDelay(failures int32) time.Duration {
+	delay := 30 * time.Second
+	for i := int32(1); i < failures && delay < 10*time.Minute; i++ {
+		delay *= 2
+	}
+
+	return min(delay, 10*time.Minute)
+}
@@ -0,0 +1,56 @@
+/*
+Copyright 2022 Red Hat
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vmset
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega" //revive:disable:dot-imports
+)
+
+func TestGetBaseImageRetryDelay(t *testing.T) {
+	tests := []struct {
+		name     string
+		failures int32
+		want     time.Duration
+	}{
+		{
+			name:     "first failure",
+			failures: 1,
+			want:     30 * time.Second,
+		},
+		{
+			name:     "third failure",
+			failures: 3,
+			want:     2 * time.Minute,
+		},
+		{
+			name:     "capped",
+			failures: 100,
+			want:     10 * time.Minute,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			g.Expect(GetBaseImageRetryDelay(tt.failures)).To(Equal(tt.want))
+		})
+	}
+}
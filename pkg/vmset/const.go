@@ -21,6 +21,9 @@ const (
 	// AppLabel -
 	AppLabel = "osp-vmset"
 
+	// SnapshotSetLabelSelector - label of the VirtualMachineSnapshots which got taken together
+	SnapshotSetLabelSelector = "osp-director.openstack.org/snapshotset"
+
 	// FinalizerName -
 	FinalizerName = "openstackvmsets.osp-director.openstack.org/virtualmachine"
 
@@ -32,4 +35,7 @@ const (
 
 	// KubevirtFencingKubeconfigSecret -
 	KubevirtFencingKubeconfigSecret = "osp-director-operator-fencing-kubeconfig"
+
+	// KubeRootCAConfigMap - CA bundle of the cluster, published to each namespace
+	KubeRootCAConfigMap = "kube-root-ca.crt"
 )
@@ -0,0 +1,191 @@
+/*
+Copyright 2022 Red Hat
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vmset
+
+import (
+	"fmt"
+	"strings"
+
+	ospdirectorv1beta2 "github.com/openstack-k8s-operators/osp-director-operator/api/v1beta2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	virtv1 "kubevirt.io/api/core/v1"
+)
+
+// DiskSize - get the size of a disk with diskSize GB
+func DiskSize(diskSize uint32) resource.Quantity {
+	return resource.MustParse(fmt.Sprintf("%dGi", diskSize))
+}
+
+// AdditionalDiskName - get the name of the volume, DataVolume and PVC of an additional disk of a VM
+func AdditionalDiskName(domainNameUniq string, diskName string) string {
+	return strings.ToLower(fmt.Sprintf("%s-%s", domainNameUniq, diskName))
+}
+
+// HasVolume - has the VM template a volume with the name
+func HasVolume(vm *virtv1.VirtualMachine, name string) bool {
+	if vm.Spec.Template == nil {
+		return false
+	}
+
+	for _, volume := range vm.Spec.Template.Spec.Volumes {
+		if volume.Name == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsHotpluggedVolume - got the volume hot plugged to the VM, or is a hot plug requested
+func IsHotpluggedVolume(vm *virtv1.VirtualMachine, name string) bool {
+	for _, request := range vm.Status.VolumeRequests {
+		if request.AddVolumeOptions != nil && request.AddVolumeOptions.Name == name {
+			return true
+		}
+	}
+
+	if vm.Spec.Template == nil {
+		return false
+	}
+
+	for _, volume := range vm.Spec.Template.Spec.Volumes {
+		if volume.Name != name {
+			continue
+		}
+		if volume.PersistentVolumeClaim != nil && volume.PersistentVolumeClaim.Hotpluggable {
+			return true
+		}
+		if volume.DataVolume != nil && volume.DataVolume.Hotpluggable {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsHotunplugRequested - is a hot unplug of the volume requested
+func IsHotunplugRequested(vm *virtv1.VirtualMachine, name string) bool {
+	for _, request := range vm.Status.VolumeRequests {
+		if request.RemoveVolumeOptions != nil && request.RemoveVolumeOptions.Name == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// GetRemovedAdditionalDisks - get the volumes of additional disks of the VM which are no longer in the spec
+func GetRemovedAdditionalDisks(
+	vm *virtv1.VirtualMachine,
+	domainNameUniq string,
+	disks []ospdirectorv1beta2.OpenStackVMSetDisk,
+) []string {
+	removed := []string{}
+	if vm.Spec.Template == nil {
+		return removed
+	}
+
+	current := map[string]bool{}
+	for _, disk := range disks {
+		current[AdditionalDiskName(domainNameUniq, disk.Name)] = true
+	}
+
+	prefix := AdditionalDiskName(domainNameUniq, "")
+	for _, volume := range vm.Spec.Template.Spec.Volumes {
+		if strings.HasPrefix(volume.Name, prefix) && !current[volume.Name] {
+			removed = append(removed, volume.Name)
+		}
+	}
+
+	return removed
+}
+
+// RemoveVMDisk - remove the disk and volume with the name from the VM template
+func RemoveVMDisk(vm *virtv1.VirtualMachine, name string) {
+	disks := []virtv1.Disk{}
+	for _, disk := range vm.Spec.Template.Spec.Domain.Devices.Disks {
+		if disk.Name != name {
+			disks = append(disks, disk)
+		}
+	}
+	vm.Spec.Template.Spec.Domain.Devices.Disks = disks
+
+	volumes := []virtv1.Volume{}
+	for _, volume := range vm.Spec.Template.Spec.Volumes {
+		if volume.Name != name {
+			volumes = append(volumes, volume)
+		}
+	}
+	vm.Spec.Template.Spec.Volumes = volumes
+
+	dataVolumes := []virtv1.DataVolumeTemplateSpec{}
+	for _, dataVolume := range vm.Spec.DataVolumeTemplates {
+		if dataVolume.Name != name {
+			dataVolumes = append(dataVolumes, dataVolume)
+		}
+	}
+	vm.Spec.DataVolumeTemplates = dataVolumes
+}
+
+// HotplugVolumeOptions - get the options to hot plug the PVC of a disk, hot plugged disks need to use the scsi bus
+func HotplugVolumeOptions(name string) *virtv1.AddVolumeOptions {
+	return &virtv1.AddVolumeOptions{
+		Name: name,
+		Disk: &virtv1.Disk{
+			Name: name,
+			DiskDevice: virtv1.DiskDevice{
+				Disk: &virtv1.DiskTarget{
+					Bus: virtv1.DiskBusSCSI,
+				},
+			},
+		},
+		VolumeSource: &virtv1.HotplugVolumeSource{
+			PersistentVolumeClaim: &virtv1.PersistentVolumeClaimVolumeSource{
+				PersistentVolumeClaimVolumeSource: corev1.PersistentVolumeClaimVolumeSource{
+					ClaimName: name,
+				},
+				Hotpluggable: true,
+			},
+		},
+	}
+}
+
+// GetDiskExpansionState - get the expansion state of the PVC of a disk with the requested size
+func GetDiskExpansionState(
+	pvc *corev1.PersistentVolumeClaim,
+	requested resource.Quantity,
+	allowVolumeExpansion bool,
+) ospdirectorv1beta2.DiskExpansionState {
+	for _, c := range pvc.Status.Conditions {
+		if c.Type == corev1.PersistentVolumeClaimFileSystemResizePending && c.Status == corev1.ConditionTrue {
+			return ospdirectorv1beta2.DiskExpansionStateFileSystemResizePending
+		}
+	}
+
+	capacity := pvc.Status.Capacity[corev1.ResourceStorage]
+	if capacity.Cmp(requested) >= 0 {
+		return ospdirectorv1beta2.DiskExpansionStateNone
+	}
+
+	request := pvc.Spec.Resources.Requests[corev1.ResourceStorage]
+	if request.Cmp(requested) < 0 && !allowVolumeExpansion {
+		return ospdirectorv1beta2.DiskExpansionStateNotSupported
+	}
+
+	return ospdirectorv1beta2.DiskExpansionStateExpanding
+}
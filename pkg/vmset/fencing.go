@@ -0,0 +1,232 @@
+/*
+Copyright 2022 Red Hat
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vmset
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	ospdirectorv1beta2 "github.com/openstack-k8s-operators/osp-director-operator/api/v1beta2"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	virtv1 "kubevirt.io/api/core/v1"
+)
+
+// fencingCheckScript - get the status of each VM with the fencing kubeconfig, the same way the
+// fence_kubevirt agent of pacemaker does. Falls back to query the VM via the API if the image has
+// no fence_kubevirt. Unless INSECURE_SKIP_TLS_VERIFY is set, the API server certificate gets verified
+// with the CA bundle of the cluster. The VMs which failed are written to the termination log.
+const fencingCheckScript = `set -o pipefail
+KUBECONFIG=/etc/fencing/kubeconfig
+FENCE_OPTS=()
+CURL_OPTS=()
+if [ "${INSECURE_SKIP_TLS_VERIFY}" = "true" ]; then
+    FENCE_OPTS+=(--ssl-insecure)
+    CURL_OPTS+=(-k)
+else
+    # the fencing kubeconfig skips the TLS verification, use the CA bundle of the cluster instead
+    sed 's|insecure-skip-tls-verify: true|certificate-authority: /etc/fencing-ca/ca.crt|' \
+        /etc/fencing/kubeconfig > /tmp/kubeconfig || exit 1
+    KUBECONFIG=/tmp/kubeconfig
+    CURL_OPTS+=(--cacert /etc/fencing-ca/ca.crt)
+fi
+FAILED=()
+for VM in ${VMS}; do
+    if command -v fence_kubevirt >/dev/null 2>&1; then
+        fence_kubevirt --kubeconfig="${KUBECONFIG}" --namespace="${NAMESPACE}" "${FENCE_OPTS[@]}" --plug="${VM}" --action=status
+        RC=$?
+        # 0 - VM is on, 2 - VM is off, both mean the status could be read
+        if [ ${RC} -ne 0 ] && [ ${RC} -ne 2 ]; then
+            FAILED+=("${VM}")
+        fi
+    else
+        SERVER=$(awk '/server:/ {print $2}' "${KUBECONFIG}")
+        TOKEN=$(awk '/token:/ {print $2}' "${KUBECONFIG}")
+        if ! curl -fsS "${CURL_OPTS[@]}" -o /dev/null -H "Authorization: Bearer ${TOKEN}" \
+            "${SERVER}/apis/kubevirt.io/v1/namespaces/${NAMESPACE}/virtualmachines/${VM}"; then
+            FAILED+=("${VM}")
+        fi
+    fi
+done
+if [ ${#FAILED[@]} -gt 0 ]; then
+    echo "Fencing status check failed for: ${FAILED[*]}"
+    echo -n "${FAILED[*]}" > /dev/termination-log
+    exit 1
+fi
+echo "Fencing status check succeeded for: ${VMS}"
+`
+
+// Watchdog - get the watchdog device of the VM domain for the watchdog settings
+func Watchdog(settings *ospdirectorv1beta2.VMWatchdog) *virtv1.Watchdog {
+	if settings == nil {
+		return nil
+	}
+
+	action := virtv1.WatchdogAction(settings.Action)
+	if action == "" {
+		action = virtv1.WatchdogActionReset
+	}
+
+	return &virtv1.Watchdog{
+		Name: "watchdog",
+		WatchdogDevice: virtv1.WatchdogDevice{
+			I6300ESB: &virtv1.I6300ESBWatchdog{
+				Action: action,
+			},
+		},
+	}
+}
+
+// FencingCheckJob - get the job which gets the status of the VMs using the fencing kubeconfig
+func FencingCheckJob(
+	name string,
+	namespace string,
+	labels map[string]string,
+	imageURL string,
+	runUID int64,
+	runGID int64,
+	hostnames []string,
+	insecureSkipTLSVerify bool,
+) *batchv1.Job {
+	backoffLimit := int32(0)
+
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			Labels:    labels,
+		},
+	}
+
+	job.Spec.BackoffLimit = &backoffLimit
+	job.Spec.Template.Spec = corev1.PodSpec{
+		RestartPolicy: corev1.RestartPolicyNever,
+		// only the fencing credentials get used
+		AutomountServiceAccountToken: ptr.To(false),
+		SecurityContext: &corev1.PodSecurityContext{
+			RunAsUser:  &runUID,
+			RunAsGroup: &runGID,
+		},
+		Volumes: []corev1.Volume{
+			{
+				Name: "fencing-kubeconfig",
+				VolumeSource: corev1.VolumeSource{
+					Secret: &corev1.SecretVolumeSource{
+						SecretName: KubevirtFencingKubeconfigSecret,
+					},
+				},
+			},
+			{
+				Name: "fencing-ca",
+				VolumeSource: corev1.VolumeSource{
+					ConfigMap: &corev1.ConfigMapVolumeSource{
+						LocalObjectReference: corev1.LocalObjectReference{
+							Name: KubeRootCAConfigMap,
+						},
+					},
+				},
+			},
+		},
+		Containers: []corev1.Container{
+			{
+				Name:    "fencing-check",
+				Image:   imageURL,
+				Command: []string{"/bin/bash", "-c", fencingCheckScript},
+				Env: []corev1.EnvVar{
+					{
+						Name:  "NAMESPACE",
+						Value: namespace,
+					},
+					{
+						Name:  "VMS",
+						Value: strings.Join(hostnames, " "),
+					},
+					{
+						Name:  "INSECURE_SKIP_TLS_VERIFY",
+						Value: strconv.FormatBool(insecureSkipTLSVerify),
+					},
+				},
+				VolumeMounts: []corev1.VolumeMount{
+					{
+						Name:      "fencing-kubeconfig",
+						MountPath: "/etc/fencing",
+						ReadOnly:  true,
+					},
+					{
+						Name:      "fencing-ca",
+						MountPath: "/etc/fencing-ca",
+						ReadOnly:  true,
+					},
+				},
+			},
+		},
+	}
+
+	return job
+}
+
+// GetFencingCheckHostnames - get the hostnames of the VMs checked by the fencing check job
+func GetFencingCheckHostnames(job *batchv1.Job) []string {
+	for _, container := range job.Spec.Template.Spec.Containers {
+		for _, env := range container.Env {
+			if env.Name == "VMS" {
+				return strings.Fields(env.Value)
+			}
+		}
+	}
+
+	return nil
+}
+
+// GetFencingCheckFailedVMs - get the VMs which failed the fencing check from the termination message of the job pods
+func GetFencingCheckFailedVMs(pods []corev1.Pod) []string {
+	failedVMs := []string{}
+	for _, pod := range pods {
+		for _, status := range pod.Status.ContainerStatuses {
+			if status.State.Terminated == nil {
+				continue
+			}
+			for _, hostname := range strings.Fields(status.State.Terminated.Message) {
+				if !slices.Contains(failedVMs, hostname) {
+					failedVMs = append(failedVMs, hostname)
+				}
+			}
+		}
+	}
+	slices.Sort(failedVMs)
+
+	return failedVMs
+}
+
+// GetFencingCheckRequeue - get the time until the next fencing check is due. It is due right away if it
+// never ran or the VMs changed since the last check.
+func GetFencingCheckRequeue(
+	fencingStatus *ospdirectorv1beta2.OpenStackVMSetFencingStatus,
+	hostnames []string,
+	interval time.Duration,
+	now time.Time,
+) time.Duration {
+	if fencingStatus == nil || fencingStatus.LastCheckTime == nil || !slices.Equal(fencingStatus.CheckedVMs, hostnames) {
+		return 0
+	}
+
+	return max(fencingStatus.LastCheckTime.Add(interval).Sub(now), 0)
+}
@@ -0,0 +1,134 @@
+/*
+Copyright 2022 Red Hat
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vmset
+
+import (
+	"reflect"
+
+	ospdirectorv1beta2 "github.com/openstack-k8s-operators/osp-director-operator/api/v1beta2"
+	"k8s.io/utils/ptr"
+	virtv1 "kubevirt.io/api/core/v1"
+)
+
+// Firmware - merge the bootloader of the firmware settings into the firmware of the VM domain,
+// keeping the UUID and serial KubeVirt assigned to the VM
+func Firmware(
+	firmware *virtv1.Firmware,
+	settings *ospdirectorv1beta2.VMFirmware,
+) *virtv1.Firmware {
+	bootloader := Bootloader(settings)
+	if firmware == nil {
+		if bootloader == nil {
+			return nil
+		}
+		firmware = &virtv1.Firmware{}
+	}
+	firmware.Bootloader = bootloader
+
+	return firmware
+}
+
+// Bootloader - get the bootloader of the VM domain for the firmware settings, nil for the KubeVirt default BIOS
+func Bootloader(settings *ospdirectorv1beta2.VMFirmware) *virtv1.Bootloader {
+	if settings == nil {
+		return nil
+	}
+
+	if settings.Bootloader == ospdirectorv1beta2.VMBootloaderUEFI {
+		return &virtv1.Bootloader{
+			EFI: &virtv1.EFI{
+				SecureBoot: ptr.To(settings.SecureBoot),
+			},
+		}
+	}
+
+	return &virtv1.Bootloader{
+		BIOS: &virtv1.BIOS{},
+	}
+}
+
+// Features - merge the System Management Mode required by Secure Boot into the features of the VM domain
+func Features(
+	features *virtv1.Features,
+	settings *ospdirectorv1beta2.VMFirmware,
+) *virtv1.Features {
+	if settings == nil || !settings.SecureBoot {
+		if features != nil {
+			features.SMM = nil
+		}
+
+		return features
+	}
+
+	if features == nil {
+		features = &virtv1.Features{}
+	}
+	features.SMM = &virtv1.FeatureState{
+		Enabled: ptr.To(true),
+	}
+
+	return features
+}
+
+// TPM - get the vTPM device of the VM domain for the firmware settings
+func TPM(settings *ospdirectorv1beta2.VMFirmware) *virtv1.TPMDevice {
+	if settings == nil || settings.TPM == nil {
+		return nil
+	}
+
+	return &virtv1.TPMDevice{
+		Persistent: ptr.To(settings.TPM.Persistent),
+	}
+}
+
+// IsFirmwareUpToDate - does the VMI run with the bootloader, SMM and vTPM of the firmware settings
+func IsFirmwareUpToDate(
+	vmi *virtv1.VirtualMachineInstance,
+	settings *ospdirectorv1beta2.VMFirmware,
+) bool {
+	var bootloader *virtv1.Bootloader
+	if vmi.Spec.Domain.Firmware != nil {
+		bootloader = vmi.Spec.Domain.Firmware.Bootloader
+	}
+	if !isBootloaderEqual(bootloader, Bootloader(settings)) {
+		return false
+	}
+
+	smm := vmi.Spec.Domain.Features != nil &&
+		vmi.Spec.Domain.Features.SMM != nil &&
+		(vmi.Spec.Domain.Features.SMM.Enabled == nil || *vmi.Spec.Domain.Features.SMM.Enabled)
+	if smm != (settings != nil && settings.SecureBoot) {
+		return false
+	}
+
+	return reflect.DeepEqual(vmi.Spec.Domain.Devices.TPM, TPM(settings))
+}
+
+// isBootloaderEqual - compare bootloaders, nil and BIOS are both the KubeVirt default
+func isBootloaderEqual(current *virtv1.Bootloader, expected *virtv1.Bootloader) bool {
+	currentEFI := current != nil && current.EFI != nil
+	expectedEFI := expected != nil && expected.EFI != nil
+	if currentEFI != expectedEFI {
+		return false
+	}
+	if !currentEFI {
+		return true
+	}
+
+	// KubeVirt defaults SecureBoot to true when not set
+	return ptr.Deref(current.EFI.SecureBoot, true) == ptr.Deref(expected.EFI.SecureBoot, true)
+}
@@ -0,0 +1,137 @@
+/*
+Copyright 2022 Red Hat
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vmset
+
+import (
+	"sort"
+
+	ospdirectorv1beta2 "github.com/openstack-k8s-operators/osp-director-operator/api/v1beta2"
+	virtv1 "kubevirt.io/api/core/v1"
+)
+
+const (
+	// MigrationStateInProgress - the live migration of the VM has not completed yet
+	MigrationStateInProgress = "InProgress"
+	// MigrationStateSucceeded - the live migration of the VM completed
+	MigrationStateSucceeded = "Succeeded"
+	// MigrationStateFailed - the live migration of the VM failed
+	MigrationStateFailed = "Failed"
+)
+
+// GetVMStatus - get the guest-level state of a VM from its VMI. A VM without VMI is down.
+func GetVMStatus(vmi *virtv1.VirtualMachineInstance) ospdirectorv1beta2.OpenStackVMSetVMStatus {
+	status := ospdirectorv1beta2.OpenStackVMSetVMStatus{
+		Health: ospdirectorv1beta2.VMHealthDown,
+	}
+	if vmi == nil {
+		return status
+	}
+
+	status.Phase = string(vmi.Status.Phase)
+	status.NodeName = vmi.Status.NodeName
+	status.Ready = hasVMICondition(vmi, virtv1.VirtualMachineInstanceReady)
+	status.AgentConnected = hasVMICondition(vmi, virtv1.VirtualMachineInstanceAgentConnected)
+
+	if vmi.Status.MigrationState != nil {
+		status.MigrationTargetNode = vmi.Status.MigrationState.TargetNode
+		switch {
+		case vmi.Status.MigrationState.Failed:
+			status.MigrationState = MigrationStateFailed
+		case vmi.Status.MigrationState.Completed:
+			status.MigrationState = MigrationStateSucceeded
+		default:
+			status.MigrationState = MigrationStateInProgress
+		}
+	}
+
+	for _, iface := range vmi.Status.Interfaces {
+		ips := iface.IPs
+		if len(ips) == 0 && iface.IP != "" {
+			ips = []string{iface.IP}
+		}
+		status.Interfaces = append(status.Interfaces, ospdirectorv1beta2.OpenStackVMSetVMInterfaceStatus{
+			Name:          iface.Name,
+			InterfaceName: iface.InterfaceName,
+			MAC:           iface.MAC,
+			IPAddresses:   ips,
+		})
+	}
+
+	switch {
+	case vmi.Status.Phase != virtv1.Running:
+		status.Health = ospdirectorv1beta2.VMHealthDown
+	case status.Ready && status.AgentConnected:
+		status.Health = ospdirectorv1beta2.VMHealthHealthy
+	default:
+		status.Health = ospdirectorv1beta2.VMHealthDegraded
+	}
+
+	return status
+}
+
+// GetHealthStatus - count the VMs of the set per health state
+func GetHealthStatus(vmStatus map[string]ospdirectorv1beta2.OpenStackVMSetVMStatus) ospdirectorv1beta2.OpenStackVMSetHealthStatus {
+	health := ospdirectorv1beta2.OpenStackVMSetHealthStatus{}
+	for _, status := range vmStatus {
+		switch status.Health {
+		case ospdirectorv1beta2.VMHealthHealthy:
+			health.HealthyCount++
+		case ospdirectorv1beta2.VMHealthDegraded:
+			health.DegradedCount++
+		default:
+			health.DownCount++
+		}
+	}
+
+	return health
+}
+
+// GetControlPlaneHealthStatus - roll up the health of the VMSets of a control plane. The control
+// plane is down if a VMSet which should run VMs has no healthy VM, and degraded if any VM is
+// not healthy.
+func GetControlPlaneHealthStatus(vmSets []*ospdirectorv1beta2.OpenStackVMSet) ospdirectorv1beta2.OpenStackControlPlaneHealthStatus {
+	health := ospdirectorv1beta2.OpenStackControlPlaneHealthStatus{
+		State: ospdirectorv1beta2.VMHealthHealthy,
+	}
+
+	down := false
+	for _, vmSet := range vmSets {
+		vmSetHealth := GetHealthStatus(vmSet.Status.VMStatus)
+		health.HealthyCount += vmSetHealth.HealthyCount
+		health.DegradedCount += vmSetHealth.DegradedCount
+		health.DownCount += vmSetHealth.DownCount
+
+		if vmSet.Spec.VMCount > 0 && vmSetHealth.HealthyCount == 0 {
+			down = true
+		}
+
+		for hostname, status := range vmSet.Status.VMStatus {
+			if status.Health != ospdirectorv1beta2.VMHealthHealthy {
+				health.UnhealthyVMs = append(health.UnhealthyVMs, hostname)
+			}
+		}
+	}
+	sort.Strings(health.UnhealthyVMs)
+
+	if down {
+		health.State = ospdirectorv1beta2.VMHealthDown
+	} else if len(health.UnhealthyVMs) > 0 {
+		health.State = ospdirectorv1beta2.VMHealthDegraded
+	}
+
+	return health
+}
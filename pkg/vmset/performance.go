@@ -0,0 +1,101 @@
+/*
+Copyright 2022 Red Hat
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vmset
+
+import (
+	ospdirectorv1beta2 "github.com/openstack-k8s-operators/osp-director-operator/api/v1beta2"
+	virtv1 "kubevirt.io/api/core/v1"
+)
+
+// CPU - get the CPU of the VM domain with the dedicated CPU placement and NUMA topology of the performance settings
+func CPU(
+	cores uint32,
+	performance *ospdirectorv1beta2.VMPerformance,
+) *virtv1.CPU {
+	cpu := &virtv1.CPU{
+		Cores: cores,
+	}
+
+	if performance != nil {
+		cpu.DedicatedCPUPlacement = performance.DedicatedCPUPlacement
+		cpu.IsolateEmulatorThread = performance.IsolateEmulatorThread
+		if performance.NUMAGuestMappingPassthrough {
+			cpu.NUMA = &virtv1.NUMA{
+				GuestMappingPassthrough: &virtv1.NUMAGuestMappingPassthrough{},
+			}
+		}
+	}
+
+	return cpu
+}
+
+// Memory - merge the hugepages of the performance settings into the memory of the VM domain
+func Memory(
+	memory *virtv1.Memory,
+	performance *ospdirectorv1beta2.VMPerformance,
+) *virtv1.Memory {
+	if performance == nil || performance.HugepagesPageSize == "" {
+		if memory != nil {
+			memory.Hugepages = nil
+		}
+
+		return memory
+	}
+
+	if memory == nil {
+		memory = &virtv1.Memory{}
+	}
+	memory.Hugepages = &virtv1.Hugepages{
+		PageSize: performance.HugepagesPageSize,
+	}
+
+	return memory
+}
+
+// IsPerformanceUpToDate - does the VMI run with the dedicated CPU placement, NUMA topology and hugepages of the performance settings
+func IsPerformanceUpToDate(
+	vmi *virtv1.VirtualMachineInstance,
+	performance *ospdirectorv1beta2.VMPerformance,
+) bool {
+	if vmi.Spec.Domain.CPU == nil {
+		return false
+	}
+
+	want := CPU(vmi.Spec.Domain.CPU.Cores, performance)
+	current := vmi.Spec.Domain.CPU
+	if current.DedicatedCPUPlacement != want.DedicatedCPUPlacement ||
+		current.IsolateEmulatorThread != want.IsolateEmulatorThread ||
+		(current.NUMA != nil && current.NUMA.GuestMappingPassthrough != nil) != (want.NUMA != nil) {
+		return false
+	}
+
+	pageSize := ""
+	if vmi.Spec.Domain.Memory != nil && vmi.Spec.Domain.Memory.Hugepages != nil {
+		pageSize = vmi.Spec.Domain.Memory.Hugepages.PageSize
+	}
+	wantPageSize := ""
+	if performance != nil {
+		wantPageSize = performance.HugepagesPageSize
+	}
+
+	return pageSize == wantPageSize
+}
+
+// IsLiveMigratable - can VMs with the performance settings be live migrated
+func IsLiveMigratable(performance *ospdirectorv1beta2.VMPerformance) bool {
+	return performance == nil || !performance.NUMAGuestMappingPassthrough
+}
@@ -0,0 +1,206 @@
+/*
+Copyright 2022 Red Hat
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vmset
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"sort"
+	"strings"
+
+	ospdirectorv1beta2 "github.com/openstack-k8s-operators/osp-director-operator/api/v1beta2"
+	"github.com/openstack-k8s-operators/osp-director-operator/pkg/common"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	virtv1 "kubevirt.io/api/core/v1"
+)
+
+// Affinity - get the anti-affinity between the VMs of the set. Without placement two VMs
+// of the set should not run on the same worker node if possible.
+func Affinity(
+	placement *ospdirectorv1beta2.VMPlacement,
+	ownerName string,
+) *corev1.Affinity {
+	antiAffinity, topologyKey := getAntiAffinity(placement)
+
+	switch antiAffinity {
+	case ospdirectorv1beta2.VMAntiAffinityRequired:
+		return common.RequirePodsDistributed(
+			common.OwnerNameLabelSelector,
+			[]string{ownerName},
+			topologyKey,
+		)
+	case ospdirectorv1beta2.VMAntiAffinityNone:
+		return nil
+	default:
+		return common.DistributePods(
+			common.OwnerNameLabelSelector,
+			[]string{ownerName},
+			topologyKey,
+		)
+	}
+}
+
+// getAntiAffinity - get the anti-affinity type and topology key of the placement, with defaults
+func getAntiAffinity(placement *ospdirectorv1beta2.VMPlacement) (ospdirectorv1beta2.VMAntiAffinityType, string) {
+	antiAffinity := ospdirectorv1beta2.VMAntiAffinityPreferred
+	topologyKey := corev1.LabelHostname
+
+	if placement != nil {
+		if placement.AntiAffinity != "" {
+			antiAffinity = placement.AntiAffinity
+		}
+		if placement.AntiAffinityTopologyKey != "" {
+			topologyKey = placement.AntiAffinityTopologyKey
+		}
+	}
+
+	return antiAffinity, topologyKey
+}
+
+// TopologySpreadConstraints - get the topology spread constraints for the VMs of the set
+func TopologySpreadConstraints(
+	placement *ospdirectorv1beta2.VMPlacement,
+	ownerName string,
+) []corev1.TopologySpreadConstraint {
+	if placement == nil || len(placement.TopologySpreadConstraints) == 0 {
+		return nil
+	}
+
+	constraints := []corev1.TopologySpreadConstraint{}
+	for _, constraint := range placement.TopologySpreadConstraints {
+		maxSkew := constraint.MaxSkew
+		if maxSkew < 1 {
+			maxSkew = 1
+		}
+		whenUnsatisfiable := constraint.WhenUnsatisfiable
+		if whenUnsatisfiable == "" {
+			whenUnsatisfiable = corev1.DoNotSchedule
+		}
+
+		constraints = append(constraints, corev1.TopologySpreadConstraint{
+			MaxSkew:           maxSkew,
+			TopologyKey:       constraint.TopologyKey,
+			WhenUnsatisfiable: whenUnsatisfiable,
+			LabelSelector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{
+					common.OwnerNameLabelSelector: ownerName,
+				},
+			},
+		})
+	}
+
+	return constraints
+}
+
+// Tolerations - get the tolerations of the VMs of the set
+func Tolerations(placement *ospdirectorv1beta2.VMPlacement) []corev1.Toleration {
+	if placement == nil {
+		return nil
+	}
+
+	return placement.Tolerations
+}
+
+// GetPlacementWarnings - check where the running VMs of the set break the placement
+func GetPlacementWarnings(
+	placement *ospdirectorv1beta2.VMPlacement,
+	nodeSelector map[string]string,
+	vmis []virtv1.VirtualMachineInstance,
+	nodes []corev1.Node,
+) []string {
+	warnings := []string{}
+
+	nodeLabels := map[string]map[string]string{}
+	for _, node := range nodes {
+		nodeLabels[node.Name] = node.Labels
+	}
+
+	//
+	// anti-affinity, VMs of the set running in the same topology domain
+	//
+	if antiAffinity, topologyKey := getAntiAffinity(placement); antiAffinity != ospdirectorv1beta2.VMAntiAffinityNone {
+		domainVMs := map[string][]string{}
+		for _, vmi := range vmis {
+			if domain, ok := nodeLabels[vmi.Status.NodeName][topologyKey]; ok && vmi.Status.NodeName != "" {
+				domainVMs[domain] = append(domainVMs[domain], vmi.Name)
+			}
+		}
+
+		for _, domain := range slices.Sorted(maps.Keys(domainVMs)) {
+			if len(domainVMs[domain]) > 1 {
+				sort.Strings(domainVMs[domain])
+				warnings = append(warnings, fmt.Sprintf("VirtualMachines %s run in the same %s domain %s",
+					strings.Join(domainVMs[domain], ", "),
+					topologyKey,
+					domain))
+			}
+		}
+	}
+
+	//
+	// topology spread, skew between the domains of the nodes the VMs can run on
+	//
+	if placement != nil {
+		selector := labels.SelectorFromSet(nodeSelector)
+
+		for _, constraint := range placement.TopologySpreadConstraints {
+			maxSkew := int(constraint.MaxSkew)
+			if maxSkew < 1 {
+				maxSkew = 1
+			}
+
+			domainCounts := map[string]int{}
+			for _, node := range nodes {
+				if domain, ok := node.Labels[constraint.TopologyKey]; ok && selector.Matches(labels.Set(node.Labels)) {
+					domainCounts[domain] = 0
+				}
+			}
+			for _, vmi := range vmis {
+				if domain, ok := nodeLabels[vmi.Status.NodeName][constraint.TopologyKey]; ok && vmi.Status.NodeName != "" {
+					domainCounts[domain]++
+				}
+			}
+
+			if len(domainCounts) == 0 {
+				continue
+			}
+
+			minCount, maxCount := -1, 0
+			for _, count := range domainCounts {
+				if minCount < 0 || count < minCount {
+					minCount = count
+				}
+				if count > maxCount {
+					maxCount = count
+				}
+			}
+
+			if maxCount-minCount > maxSkew {
+				warnings = append(warnings, fmt.Sprintf("VirtualMachines are spread with a skew of %d across the %s domains %v, max skew is %d",
+					maxCount-minCount,
+					constraint.TopologyKey,
+					domainCounts,
+					maxSkew))
+			}
+		}
+	}
+
+	return warnings
+}
@@ -0,0 +1,125 @@
+/*
+Copyright 2022 Red Hat
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vmset
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	virtv1 "kubevirt.io/api/core/v1"
+	snapshotv1 "kubevirt.io/api/snapshot/v1beta1"
+)
+
+// VirtualMachineSnapshot - VirtualMachineSnapshot of the VM
+func VirtualMachineSnapshot(
+	name string,
+	namespace string,
+	vmName string,
+	labels map[string]string,
+) *snapshotv1.VirtualMachineSnapshot {
+	return &snapshotv1.VirtualMachineSnapshot{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			Labels:    labels,
+		},
+		Spec: snapshotv1.VirtualMachineSnapshotSpec{
+			Source: corev1.TypedLocalObjectReference{
+				APIGroup: ptr.To(virtv1.GroupVersion.Group),
+				Kind:     "VirtualMachine",
+				Name:     vmName,
+			},
+		},
+	}
+}
+
+// IsSnapshotReady - is the VirtualMachineSnapshot ready to use
+func IsSnapshotReady(snapshot *snapshotv1.VirtualMachineSnapshot) bool {
+	return snapshot.Status != nil && ptr.Deref(snapshot.Status.ReadyToUse, false)
+}
+
+// IsSnapshotFailed - did the VirtualMachineSnapshot fail
+func IsSnapshotFailed(snapshot *snapshotv1.VirtualMachineSnapshot) bool {
+	return snapshot.Status != nil && snapshot.Status.Phase == snapshotv1.Failed
+}
+
+// VirtualMachineRestore - VirtualMachineRestore of the VM from the snapshot
+func VirtualMachineRestore(
+	name string,
+	namespace string,
+	vmName string,
+	snapshotName string,
+	labels map[string]string,
+) *snapshotv1.VirtualMachineRestore {
+	return &snapshotv1.VirtualMachineRestore{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			Labels:    labels,
+		},
+		Spec: snapshotv1.VirtualMachineRestoreSpec{
+			Target: corev1.TypedLocalObjectReference{
+				APIGroup: ptr.To(virtv1.GroupVersion.Group),
+				Kind:     "VirtualMachine",
+				Name:     vmName,
+			},
+			VirtualMachineSnapshotName: snapshotName,
+		},
+	}
+}
+
+// IsRestoreComplete - did the VirtualMachineRestore complete
+func IsRestoreComplete(restore *snapshotv1.VirtualMachineRestore) bool {
+	return restore.Status != nil && ptr.Deref(restore.Status.Complete, false)
+}
+
+// GetRestoreFailure - get the message of the failure condition of the VirtualMachineRestore, empty if it did not fail
+func GetRestoreFailure(restore *snapshotv1.VirtualMachineRestore) string {
+	if restore.Status == nil {
+		return ""
+	}
+
+	for _, c := range restore.Status.Conditions {
+		if c.Type == snapshotv1.ConditionFailure && c.Status == corev1.ConditionTrue {
+			return c.Message
+		}
+	}
+
+	return ""
+}
+
+// IsSnapshotOrRestoreInProgress - KubeVirt rejects changes of the VM spec while a snapshot or restore is in progress
+func IsSnapshotOrRestoreInProgress(vm *virtv1.VirtualMachine) bool {
+	return vm.Status.SnapshotInProgress != nil || vm.Status.RestoreInProgress != nil
+}
+
+// GetVolumeDataVolumeName - get the name of the DataVolume the volume of the VM references, the default
+// if the VM does not have the volume. A restore of the VM from a snapshot replaces the DataVolumes of
+// the VM with new ones.
+func GetVolumeDataVolumeName(
+	vm *virtv1.VirtualMachine,
+	volumeName string,
+	defaultName string,
+) string {
+	for _, v := range vm.Spec.Template.Spec.Volumes {
+		if v.Name == volumeName && v.DataVolume != nil && v.DataVolume.Name != "" {
+			return v.DataVolume.Name
+		}
+	}
+
+	return defaultName
+}
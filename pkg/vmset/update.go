@@ -0,0 +1,125 @@
+/*
+Copyright 2022 Red Hat
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vmset
+
+import (
+	"fmt"
+	"reflect"
+
+	ospdirectorv1beta2 "github.com/openstack-k8s-operators/osp-director-operator/api/v1beta2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	virtv1 "kubevirt.io/api/core/v1"
+)
+
+// GetUpdateStrategyType - get the update strategy type, Manual if not set
+func GetUpdateStrategyType(updateStrategy *ospdirectorv1beta2.VMUpdateStrategy) ospdirectorv1beta2.VMUpdateStrategyType {
+	if updateStrategy == nil || updateStrategy.Type == "" {
+		return ospdirectorv1beta2.VMUpdateStrategyManual
+	}
+
+	return updateStrategy.Type
+}
+
+// MemoryRequest - get the memory request of a VM with memory GB
+func MemoryRequest(memory uint32) resource.Quantity {
+	return resource.MustParse(fmt.Sprintf("%dGi", memory))
+}
+
+// IsVMIUpToDate - is the VMI running with the requested cores, memory, firmware, performance settings and watchdog
+func IsVMIUpToDate(
+	vmi *virtv1.VirtualMachineInstance,
+	cores uint32,
+	memory uint32,
+	firmware *ospdirectorv1beta2.VMFirmware,
+	performance *ospdirectorv1beta2.VMPerformance,
+	watchdog *ospdirectorv1beta2.VMWatchdog,
+) bool {
+	if vmi.Spec.Domain.CPU == nil || vmi.Spec.Domain.CPU.Cores != cores {
+		return false
+	}
+
+	if !IsFirmwareUpToDate(vmi, firmware) {
+		return false
+	}
+
+	if !IsPerformanceUpToDate(vmi, performance) {
+		return false
+	}
+
+	if !reflect.DeepEqual(vmi.Spec.Domain.Devices.Watchdog, Watchdog(watchdog)) {
+		return false
+	}
+
+	current, ok := vmi.Spec.Domain.Resources.Requests[corev1.ResourceMemory]
+	if !ok {
+		return false
+	}
+
+	return current.Cmp(MemoryRequest(memory)) == 0
+}
+
+// IsVMISettled - is the VMI running with the guest agent connected and no migration in progress
+func IsVMISettled(vmi *virtv1.VirtualMachineInstance) bool {
+	if vmi.Status.Phase != virtv1.Running {
+		return false
+	}
+
+	if migration := vmi.Status.MigrationState; migration != nil && !migration.Completed && !migration.Failed {
+		return false
+	}
+
+	return hasVMICondition(vmi, virtv1.VirtualMachineInstanceAgentConnected)
+}
+
+// IsVMILiveMigratable - can the VMI be live migrated
+func IsVMILiveMigratable(vmi *virtv1.VirtualMachineInstance) bool {
+	return hasVMICondition(vmi, virtv1.VirtualMachineInstanceIsMigratable)
+}
+
+// IsVMRestartRequired - has KubeVirt flagged changes of the VM which can not be applied without restart
+func IsVMRestartRequired(vm *virtv1.VirtualMachine) bool {
+	for _, c := range vm.Status.Conditions {
+		if c.Type == virtv1.VirtualMachineRestartRequired && c.Status == corev1.ConditionTrue {
+			return true
+		}
+	}
+
+	return false
+}
+
+// GetVMIMigrationUID - get the UID of the last migration of the VMI, empty if it never got migrated
+func GetVMIMigrationUID(vmi *virtv1.VirtualMachineInstance) string {
+	if vmi.Status.MigrationState == nil {
+		return ""
+	}
+
+	return string(vmi.Status.MigrationState.MigrationUID)
+}
+
+func hasVMICondition(
+	vmi *virtv1.VirtualMachineInstance,
+	condType virtv1.VirtualMachineInstanceConditionType,
+) bool {
+	for _, c := range vmi.Status.Conditions {
+		if c.Type == condType && c.Status == corev1.ConditionTrue {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,136 @@
+/*
+Copyright 2022 Red Hat
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vmset
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega" //revive:disable:dot-imports
+	ospdirectorv1beta2 "github.com/openstack-k8s-operators/osp-director-operator/api/v1beta2"
+	corev1 "k8s.io/api/core/v1"
+	virtv1 "kubevirt.io/api/core/v1"
+)
+
+func TestIsVMIUpToDate(t *testing.T) {
+	performance := &ospdirectorv1beta2.VMPerformance{
+		DedicatedCPUPlacement:       true,
+		HugepagesPageSize:           "1Gi",
+		NUMAGuestMappingPassthrough: true,
+	}
+
+	newVMI := func(cores uint32, memory uint32, performance *ospdirectorv1beta2.VMPerformance) *virtv1.VirtualMachineInstance {
+		vmi := &virtv1.VirtualMachineInstance{}
+		vmi.Spec.Domain.CPU = CPU(cores, performance)
+		vmi.Spec.Domain.Memory = Memory(nil, performance)
+		vmi.Spec.Domain.Resources.Requests = corev1.ResourceList{
+			corev1.ResourceMemory: MemoryRequest(memory),
+		}
+
+		return vmi
+	}
+	withWatchdog := func(vmi *virtv1.VirtualMachineInstance, watchdog *ospdirectorv1beta2.VMWatchdog) *virtv1.VirtualMachineInstance {
+		vmi.Spec.Domain.Devices.Watchdog = Watchdog(watchdog)
+
+		return vmi
+	}
+
+	tests := []struct {
+		name        string
+		vmi         *virtv1.VirtualMachineInstance
+		performance *ospdirectorv1beta2.VMPerformance
+		watchdog    *ospdirectorv1beta2.VMWatchdog
+		want        bool
+	}{
+		{
+			name: "up to date",
+			vmi:  newVMI(4, 16, nil),
+			want: true,
+		},
+		{
+			name: "cores changed",
+			vmi:  newVMI(2, 16, nil),
+			want: false,
+		},
+		{
+			name: "memory changed",
+			vmi:  newVMI(4, 8, nil),
+			want: false,
+		},
+		{
+			name:        "up to date with performance settings",
+			vmi:         newVMI(4, 16, performance),
+			performance: performance,
+			want:        true,
+		},
+		{
+			name:        "performance settings added",
+			vmi:         newVMI(4, 16, nil),
+			performance: performance,
+			want:        false,
+		},
+		{
+			name: "performance settings removed",
+			vmi:  newVMI(4, 16, performance),
+			want: false,
+		},
+		{
+			name: "hugepages page size changed",
+			vmi:  newVMI(4, 16, performance),
+			performance: &ospdirectorv1beta2.VMPerformance{
+				DedicatedCPUPlacement:       true,
+				HugepagesPageSize:           "2Mi",
+				NUMAGuestMappingPassthrough: true,
+			},
+			want: false,
+		},
+		{
+			name: "NUMA passthrough disabled",
+			vmi:  newVMI(4, 16, performance),
+			performance: &ospdirectorv1beta2.VMPerformance{
+				DedicatedCPUPlacement: true,
+				HugepagesPageSize:     "1Gi",
+			},
+			want: false,
+		},
+		{
+			name:     "watchdog added",
+			vmi:      newVMI(4, 16, nil),
+			watchdog: &ospdirectorv1beta2.VMWatchdog{},
+			want:     false,
+		},
+		{
+			name:     "up to date with watchdog",
+			vmi:      withWatchdog(newVMI(4, 16, nil), &ospdirectorv1beta2.VMWatchdog{}),
+			watchdog: &ospdirectorv1beta2.VMWatchdog{Action: ospdirectorv1beta2.VMWatchdogActionReset},
+			want:     true,
+		},
+		{
+			name:     "watchdog action changed",
+			vmi:      withWatchdog(newVMI(4, 16, nil), &ospdirectorv1beta2.VMWatchdog{}),
+			watchdog: &ospdirectorv1beta2.VMWatchdog{Action: ospdirectorv1beta2.VMWatchdogActionPoweroff},
+			want:     false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			g.Expect(IsVMIUpToDate(tt.vmi, 4, 16, nil, tt.performance, tt.watchdog)).To(Equal(tt.want))
+		})
+	}
+}
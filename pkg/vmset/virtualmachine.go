@@ -21,7 +21,6 @@ import (
 
 	ospdirectorv1beta1 "github.com/openstack-k8s-operators/osp-director-operator/api/v1beta1"
 	corev1 "k8s.io/api/core/v1"
-	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	virtv1 "kubevirt.io/api/core/v1"
 	cdiv1 "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
@@ -175,7 +174,7 @@ func DataVolume(
 
 		dataVolume.Spec.PVC.Resources = corev1.VolumeResourceRequirements{
 			Requests: corev1.ResourceList{
-				corev1.ResourceStorage: resource.MustParse(fmt.Sprintf("%dGi", diskSize)),
+				corev1.ResourceStorage: DiskSize(diskSize),
 			},
 		}
 		dataVolume.Spec.PVC.VolumeMode = &volMode